@@ -0,0 +1,11 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-conditions.html#intrinsic-function-reference-conditions-and]
+
+exports.evaluate = function() {
+	if ((arguments.length < 2) || (arguments.length > 10))
+		throw 'must have 2 to 10 arguments';
+	for (let i = 0; i < arguments.length; i++)
+		if (!arguments[i])
+			return false;
+	return true;
+};
@@ -0,0 +1,8 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-base64.html]
+
+exports.evaluate = function(string) {
+	if (arguments.length !== 1)
+		throw 'must have 1 argument';
+	return util.btoa(util.stringToBytes(String(string)));
+};
@@ -0,0 +1,26 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-cidr.html]
+
+exports.evaluate = function(ipBlock, count, cidrBits) {
+	if (arguments.length !== 3)
+		throw 'must have 3 arguments';
+	let match = /^(\d+)\.(\d+)\.(\d+)\.(\d+)\/(\d+)$/.exec(ipBlock);
+	if (match === null)
+		throw util.sprintf('not an IPv4 CIDR block: %q', ipBlock);
+	let address = ((parseInt(match[1]) << 24) | (parseInt(match[2]) << 16) | (parseInt(match[3]) << 8) | parseInt(match[4])) >>> 0;
+	let prefix = parseInt(match[5]);
+	count = parseInt(count);
+	cidrBits = parseInt(cidrBits);
+	let subnetPrefix = 32 - cidrBits;
+	if (subnetPrefix < prefix)
+		throw 'subnet mask is larger than the CIDR block';
+	if (count > Math.pow(2, subnetPrefix - prefix))
+		throw 'count is larger than the number of available subnets';
+	let size = Math.pow(2, cidrBits);
+	let subnets = [];
+	for (let i = 0; i < count; i++) {
+		let a = address + i * size;
+		subnets.push([(a >>> 24) & 255, (a >>> 16) & 255, (a >>> 8) & 255, a & 255].join('.') + '/' + subnetPrefix);
+	}
+	return subnets;
+};
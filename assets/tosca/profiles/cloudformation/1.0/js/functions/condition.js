@@ -0,0 +1,9 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/conditions-section-structure.html]
+
+// The parser appends the condition's value as the last argument
+exports.evaluate = function(name, condition) {
+	if (arguments.length !== 2)
+		throw 'must have 1 argument';
+	return !!condition;
+};
@@ -0,0 +1,10 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-conditions.html#intrinsic-function-reference-conditions-equals]
+
+const tosca = require('tosca.lib.utils');
+
+exports.evaluate = function(a, b) {
+	if (arguments.length !== 2)
+		throw 'must have 2 arguments';
+	return tosca.deepEqual(a, b);
+};
@@ -0,0 +1,15 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-findinmap.html]
+
+// The parser appends the mapping as the last argument
+exports.evaluate = function(mapName, topLevelKey, secondLevelKey, mapping) {
+	if (arguments.length !== 4)
+		throw 'must have 3 arguments';
+	let top = mapping[topLevelKey];
+	if (top === undefined)
+		throw util.sprintf('key %q not found in mapping %q', topLevelKey, mapName);
+	let value = top[secondLevelKey];
+	if (value === undefined)
+		throw util.sprintf('key %q not found in mapping %q at %q', secondLevelKey, mapName, topLevelKey);
+	return value;
+};
@@ -0,0 +1,22 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-getatt.html]
+
+const tosca = require('tosca.lib.utils');
+
+exports.evaluate = function(name, attribute) {
+	if (arguments.length !== 2)
+		throw 'must have 2 arguments';
+	if (!tosca.isTosca(clout))
+		throw 'Clout is not TOSCA';
+	for (let id in clout.vertexes) {
+		let vertex = clout.vertexes[id];
+		if (tosca.isNodeTemplate(vertex) && (vertex.properties.name === name)) {
+			// Attributes are only known after deployment
+			let attributes = vertex.properties.attributes;
+			if ((attributes !== undefined) && (attribute in attributes))
+				return clout.coerce(attributes[attribute]);
+			return null;
+		}
+	}
+	throw util.sprintf('resource %q not found', name);
+};
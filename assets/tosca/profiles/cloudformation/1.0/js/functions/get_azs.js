@@ -0,0 +1,16 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-getavailabilityzones.html]
+
+const tosca = require('tosca.lib.utils');
+
+exports.evaluate = function(region) {
+	if (arguments.length !== 1)
+		throw 'must have 1 argument';
+	if (!region) {
+		if (!tosca.isTosca(clout))
+			throw 'Clout is not TOSCA';
+		region = clout.coerce(clout.properties.tosca.inputs['AWS::Region']);
+	}
+	// The actual availability zones are only known at deployment
+	return [region + 'a', region + 'b', region + 'c'];
+};
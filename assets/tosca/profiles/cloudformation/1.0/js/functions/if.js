@@ -0,0 +1,9 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-conditions.html#intrinsic-function-reference-conditions-if]
+
+// The parser appends the condition's value as the last argument
+exports.evaluate = function(name, valueIfTrue, valueIfFalse, condition) {
+	if (arguments.length !== 4)
+		throw 'must have 3 arguments';
+	return condition ? valueIfTrue : valueIfFalse;
+};
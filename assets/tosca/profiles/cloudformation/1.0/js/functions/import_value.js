@@ -0,0 +1,6 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-importvalue.html]
+
+exports.evaluate = function() {
+	return 'TODO';
+};
@@ -0,0 +1,10 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-join.html]
+
+exports.evaluate = function(delimiter, values) {
+	if (arguments.length !== 2)
+		throw 'must have 2 arguments';
+	if (!Array.isArray(values))
+		throw 'second argument must be a list';
+	return values.join(delimiter);
+};
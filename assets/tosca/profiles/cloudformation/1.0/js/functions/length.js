@@ -0,0 +1,10 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-length.html]
+
+exports.evaluate = function(values) {
+	if (arguments.length !== 1)
+		throw 'must have 1 argument';
+	if (!Array.isArray(values))
+		throw 'argument must be a list';
+	return values.length;
+};
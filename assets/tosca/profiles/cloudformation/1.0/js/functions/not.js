@@ -0,0 +1,8 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-conditions.html#intrinsic-function-reference-conditions-not]
+
+exports.evaluate = function(condition) {
+	if (arguments.length !== 1)
+		throw 'must have 1 argument';
+	return !condition;
+};
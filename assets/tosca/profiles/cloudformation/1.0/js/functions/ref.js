@@ -0,0 +1,23 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-ref.html]
+
+const tosca = require('tosca.lib.utils');
+
+exports.evaluate = function(name) {
+	if (arguments.length !== 1)
+		throw 'must have 1 argument';
+	if (!tosca.isTosca(clout))
+		throw 'Clout is not TOSCA';
+	if (name === 'AWS::NoValue')
+		return null;
+	let inputs = clout.properties.tosca.inputs;
+	if (name in inputs)
+		return clout.coerce(inputs[name]);
+	for (let id in clout.vertexes) {
+		let vertex = clout.vertexes[id];
+		if (tosca.isNodeTemplate(vertex) && (vertex.properties.name === name))
+			// The physical ID is only known after deployment, so we return the logical ID
+			return name;
+	}
+	throw util.sprintf('parameter or resource %q not found', name);
+};
@@ -0,0 +1,13 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-select.html]
+
+exports.evaluate = function(index, values) {
+	if (arguments.length !== 2)
+		throw 'must have 2 arguments';
+	if (!Array.isArray(values))
+		throw 'second argument must be a list';
+	index = parseInt(index);
+	if (isNaN(index) || (index < 0) || (index >= values.length))
+		throw util.sprintf('index out of range: %v', index);
+	return values[index];
+};
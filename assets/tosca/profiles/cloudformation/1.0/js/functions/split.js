@@ -0,0 +1,8 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-split.html]
+
+exports.evaluate = function(delimiter, string) {
+	if (arguments.length !== 2)
+		throw 'must have 2 arguments';
+	return String(string).split(delimiter);
+};
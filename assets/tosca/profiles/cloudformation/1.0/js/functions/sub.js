@@ -0,0 +1,27 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-sub.html]
+
+const tosca = require('tosca.lib.utils');
+const ref = require('tosca.function.ref');
+const getAtt = require('tosca.function.get_att');
+
+exports.evaluate = function(string, variables) {
+	if ((arguments.length !== 1) && (arguments.length !== 2))
+		throw 'must have 1 or 2 arguments';
+	if (!tosca.isTosca(clout))
+		throw 'Clout is not TOSCA';
+	if (variables === undefined)
+		variables = {};
+	return string.replace(/\$\{([^}]*)\}/g, function(match, name) {
+		if (name.charAt(0) === '!')
+			// Literal
+			return '${' + name.substring(1) + '}';
+		name = name.trim();
+		if (name in variables)
+			return variables[name];
+		let dot = name.indexOf('.');
+		if (dot !== -1)
+			return getAtt.evaluate(name.substring(0, dot), name.substring(dot + 1));
+		return ref.evaluate(name);
+	});
+};
@@ -0,0 +1,8 @@
+
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference-ToJsonString.html]
+
+exports.evaluate = function(value) {
+	if (arguments.length !== 1)
+		throw 'must have 1 argument';
+	return JSON.stringify(value);
+};
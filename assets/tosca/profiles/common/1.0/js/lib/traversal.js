@@ -58,6 +58,9 @@ exports.traverseValues = function(clout_, traverser) {
 	if (tosca.isTosca(clout_)) {
 		exports.traverseObjectValues(traverser, ['inputs'], clout_.properties.tosca.inputs);
 		exports.traverseObjectValues(traverser, ['outputs'], clout_.properties.tosca.outputs);
+		exports.traverseObjectValues(traverser, ['capabilities'], clout_.properties.tosca.capabilities);
+		if (clout_.properties.tosca.uploadResources !== undefined)
+			exports.traverseObjectValues(traverser, ['uploadResources', 'parameters'], clout_.properties.tosca.uploadResources.parameters);
 	}
 
 	for (let vertexId in clout_.vertexes) {
@@ -122,11 +125,77 @@ exports.traverseValues = function(clout_, traverser) {
 			let path = ['policies', policy.name];
 
 			exports.traverseObjectValues(traverser, copyAndPush(path, 'properties'), policy.properties, vertex);
+
+			for (let e = 0, l = vertex.edgesOut.size(); e < l; e++) {
+				let edge = vertex.edgesOut[e];
+				if (!tosca.isTosca(edge, 'PolicyTrigger'))
+					continue;
+
+				let triggerVertex = edge.target;
+				let triggerPath = copyAndPush(path, 'triggers', triggerVertex.properties.name);
+
+				// Trigger conditions are not traversed, because they are evaluated against attribute snapshots
+				// (see evaluateTrigger in utils.js)
+
+				exports.traverseObjectValues(traverser, copyAndPush(triggerPath, 'parameters'), triggerVertex.properties.parameters, vertex);
+
+				for (let ee = 0, ll = triggerVertex.edgesOut.size(); ee < ll; ee++) {
+					let triggerEdge = triggerVertex.edgesOut[ee];
+					if (!tosca.isTosca(triggerEdge, 'PolicyTriggerAction'))
+						continue;
+
+					let action = triggerEdge.target.properties;
+					let actionPath = copyAndPush(triggerPath, 'actions', triggerEdge.properties.sequence);
+					if (action.callOperation !== undefined)
+						exports.traverseObjectValues(traverser, copyAndPush(actionPath, 'inputs'), action.callOperation.inputs, vertex);
+					if (action.update !== undefined)
+						exports.traverseObjectValues(traverser, copyAndPush(actionPath, 'update'), action.update, vertex);
+				}
+			}
+		} else if (tosca.isTosca(vertex, 'Plugin')) {
+			let plugin = vertex.properties;
+			let path = ['plugins', plugin.name];
+
+			exports.traverseObjectValues(traverser, copyAndPush(path, 'properties'), plugin.properties, vertex);
 		} else if (tosca.isTosca(vertex, 'Substitution')) {
 			let substitution = vertex.properties;
 			let path = ['substitution'];
 
 			exports.traverseObjectValues(traverser, copyAndPush(path, 'properties'), substitution.properties, vertex);
+		} else if (tosca.isTosca(vertex, 'Workflow')) {
+			let workflow = vertex.properties;
+			let path = ['workflows', workflow.name];
+
+			exports.traverseObjectValues(traverser, copyAndPush(path, 'inputs'), workflow.inputs, vertex);
+
+			for (let e = 0, l = vertex.edgesOut.size(); e < l; e++) {
+				let edge = vertex.edgesOut[e];
+				if (!tosca.isTosca(edge, 'WorkflowStep'))
+					continue;
+
+				let stepVertex = edge.target;
+				let stepPath = copyAndPush(path, 'steps', stepVertex.properties.name);
+
+				// Operation inputs are evaluated in the context of the step's target
+				let targetVertex = stepVertex;
+				for (let ee = 0, ll = stepVertex.edgesOut.size(); ee < ll; ee++) {
+					let stepEdge = stepVertex.edgesOut[ee];
+					if (tosca.isTosca(stepEdge, 'NodeTemplateTarget') || tosca.isTosca(stepEdge, 'GroupTarget')) {
+						targetVertex = stepEdge.target;
+						break;
+					}
+				}
+
+				for (let ee = 0, ll = stepVertex.edgesOut.size(); ee < ll; ee++) {
+					let stepEdge = stepVertex.edgesOut[ee];
+					if (!tosca.isTosca(stepEdge, 'WorkflowActivity'))
+						continue;
+
+					let callOperation = stepEdge.target.properties.callOperation;
+					if (callOperation !== undefined)
+						exports.traverseObjectValues(traverser, copyAndPush(stepPath, 'activities', stepEdge.properties.sequence, 'inputs'), callOperation.inputs, targetVertex);
+				}
+			}
 		}
 	}
 };
@@ -48,7 +48,7 @@ exports.getPolicyTargets = function (vertex) {
   for (let e = 0, l = vertex.edgesOut.size(); e < l; e++) {
     let edge = vertex.edgesOut[e];
     if (exports.isTosca(edge, 'NodeTemplateTarget')) targets.push(clout.vertexes[edge.targetID].properties);
-    else if (toexportssca.isTosca(edge, 'GroupTarget')) {
+    else if (exports.isTosca(edge, 'GroupTarget')) {
       let members = exports.getGroupMembers(clout.vertexes[edge.targetID]);
       for (let m = 0, ll = members.length; m < ll; m++) addTarget(members[m]);
     }
@@ -65,6 +65,116 @@ exports.getGroupMembers = function (vertex) {
   return members;
 };
 
+// Evaluates a policy trigger's condition for each of the policy's node template targets (group
+// targets are expanded to their members), with their attributes temporarily replaced by those in
+// the snapshot, which is a map of node template names to maps of attribute values. Returns the
+// trigger's actions, in order, narrowed down to the targets for which the condition holds. Expects
+// a coerced Clout.
+exports.evaluateTrigger = function (vertex, triggerName, snapshot) {
+  let triggerVertex = null;
+  for (let e = 0, l = vertex.edgesOut.size(); e < l; e++) {
+    let edge = vertex.edgesOut[e];
+    if (exports.isTosca(edge, 'PolicyTrigger') && (edge.target.properties.name === triggerName)) {
+      triggerVertex = edge.target;
+      break;
+    }
+  }
+  if (triggerVertex === null) throw util.sprintf('trigger not found in policy "%s": %s', vertex.properties.name, triggerName);
+
+  let condition = triggerVertex.properties.condition;
+  let holds = {};
+  let targetVertexes = getPolicyTargetVertexes(vertex);
+  for (let t = 0, l = targetVertexes.length; t < l; t++) {
+    let targetVertex = targetVertexes[t];
+    let name = targetVertex.properties.name;
+    if (condition === null) holds[name] = true;
+    else holds[name] = evaluateCondition(condition, targetVertex, snapshot ? snapshot[name] : undefined);
+  }
+
+  let actionEdges = [];
+  for (let e = 0, l = triggerVertex.edgesOut.size(); e < l; e++) {
+    let edge = triggerVertex.edgesOut[e];
+    if (exports.isTosca(edge, 'PolicyTriggerAction')) actionEdges.push(edge);
+  }
+  actionEdges.sort(function (a, b) {
+    return a.properties.sequence - b.properties.sequence;
+  });
+
+  let anyHolds = false;
+  for (let name in holds)
+    if (holds[name]) {
+      anyHolds = true;
+      break;
+    }
+
+  let actions = [];
+  for (let a = 0, l = actionEdges.length; a < l; a++) {
+    let actionVertex = actionEdges[a].target;
+    let action = { nodeTemplates: [], groups: [] };
+    let hasTargets = false;
+    for (let key in actionVertex.properties) action[key] = actionVertex.properties[key];
+
+    for (let e = 0, ll = actionVertex.edgesOut.size(); e < ll; e++) {
+      let edge = actionVertex.edgesOut[e];
+      if (exports.isTosca(edge, 'NodeTemplateTarget')) {
+        hasTargets = true;
+        if (holds[edge.target.properties.name]) action.nodeTemplates.push(edge.target.properties.name);
+      } else if (exports.isTosca(edge, 'GroupTarget')) {
+        hasTargets = true;
+        let members = exports.getGroupMembers(edge.target);
+        for (let m = 0, lll = members.length; m < lll; m++)
+          if (holds[members[m].name]) {
+            action.groups.push(edge.target.properties.name);
+            break;
+          }
+      }
+    }
+
+    // Workflow actions have no targets of their own
+    if (hasTargets ? (action.nodeTemplates.length > 0) || (action.groups.length > 0) : anyHolds) actions.push(action);
+  }
+
+  return actions;
+};
+
+function getPolicyTargetVertexes(vertex) {
+  let targetVertexes = [];
+
+  function addTargetVertex(targetVertex) {
+    for (let t = 0, l = targetVertexes.length; t < l; t++) if (targetVertexes[t] === targetVertex) return;
+    targetVertexes.push(targetVertex);
+  }
+
+  for (let e = 0, l = vertex.edgesOut.size(); e < l; e++) {
+    let edge = vertex.edgesOut[e];
+    if (exports.isTosca(edge, 'NodeTemplateTarget')) addTargetVertex(edge.target);
+    else if (exports.isTosca(edge, 'GroupTarget'))
+      for (let ee = 0, ll = edge.target.edgesOut.size(); ee < ll; ee++) {
+        let memberEdge = edge.target.edgesOut[ee];
+        if (exports.isTosca(memberEdge, 'Member')) addTargetVertex(memberEdge.target);
+      }
+  }
+  return targetVertexes;
+}
+
+function evaluateCondition(condition, vertex, attributes) {
+  let vertexAttributes = vertex.properties.attributes;
+  let saved = {};
+  for (let name in attributes) {
+    saved[name] = vertexAttributes[name];
+    vertexAttributes[name] = attributes[name];
+  }
+
+  try {
+    // The condition is a validation clause, so we validate the node template itself
+    return clout.newValidators([condition], vertex, vertex, vertex).isValid(vertex.properties);
+  } finally {
+    for (let name in saved)
+      if (saved[name] === undefined) delete vertexAttributes[name];
+      else vertexAttributes[name] = saved[name];
+  }
+}
+
 exports.addHistory = function (description) {
   let metadata = clout.metadata;
   if (metadata === undefined) metadata = clout.metadata = {};
@@ -74,10 +74,14 @@ function resolve(sourceVertex, sourceNodeTemplate, requirement) {
         return;
     }
 
-    candidates = gatherCandidateCapabilities(requirement, candidates);
+    let allocationRejections = [];
+    candidates = gatherCandidateCapabilities(sourceVertex, requirement, candidates, allocationRejections);
     
     if (candidates.length === 0) {
-        unsatisfied(location, name, 'no candidate node template provides required capability');
+        if (allocationRejections.length !== 0)
+            unsatisfied(location, name, util.sprintf('no candidate capability has enough capacity for the allocation: %s', allocationRejections.join('; ')));
+        else
+            unsatisfied(location, name, 'no candidate node template provides required capability');
         return;
     }
 
@@ -109,7 +113,9 @@ function resolve(sourceVertex, sourceNodeTemplate, requirement) {
         }
 
     env.log.debugf('%s: satisfied %q with capability %q in node template %q', location.path, name, chosen.capabilityName, chosen.nodeTemplateName);
-    addRelationship(sourceVertex, requirement, chosen.vertex, chosen.capabilityName);
+    if (chosen.allocation !== null)
+        env.log.debugf('%s: allocated %s, remaining capacity is %s', location.path, JSON.stringify(chosen.allocation.properties), JSON.stringify(chosen.allocation.remaining));
+    addRelationship(sourceVertex, requirement, chosen.vertex, chosen.capabilityName, chosen.allocation);
 }
 
 function gatherCandidateNodeTemplates(sourceVertex, requirement) {
@@ -204,7 +210,7 @@ function gatherCandidateNodeTemplates(sourceVertex, requirement) {
     return candidates;
 }
 
-function gatherCandidateCapabilities(requirement, candidateNodeTemplates) {
+function gatherCandidateCapabilities(sourceVertex, requirement, candidateNodeTemplates, allocationRejections) {
     let path = requirement.location.path;
     let capabilityName = requirement.capabilityName;
     let capabilityTypeName = requirement.capabilityTypeName;
@@ -305,11 +311,23 @@ function gatherCandidateCapabilities(requirement, candidateNodeTemplates) {
                 }
             }
 
+            let allocation = null;
+            if (requirement.allocation) {
+                allocation = allocate(sourceVertex, requirement.allocation, candidateVertex, candidateCapabilityName, candidateCapability);
+                if (allocation.insufficient.length !== 0) {
+                    let rejection = util.sprintf('capability %q in node template %q would be left with %s', candidateCapabilityName, candidateNodeTemplateName, allocation.insufficient.join(', '));
+                    env.log.debugf('%s: %s', path, rejection);
+                    allocationRejections.push(rejection);
+                    continue;
+                }
+            }
+
             candidates.push({
                 vertex: candidateVertex,
                 nodeTemplateName: candidateNodeTemplateName,
                 capability: candidateCapability,
-                capabilityName: candidateCapabilityName
+                capabilityName: candidateCapabilityName,
+                allocation: allocation
             });
         }
     }
@@ -317,7 +335,42 @@ function gatherCandidateCapabilities(requirement, candidateNodeTemplates) {
     return candidates;
 }
 
-function addRelationship(sourceVertex, requirement, targetVertex, capabilityName) {
+// Subtracts the requested allocation, as well as the allocations of existing relationships, from the
+// capability's properties
+function allocate(sourceVertex, requested, targetVertex, capabilityName, capability) {
+    let allocation = {
+        properties: {},
+        remaining: {},
+        insufficient: []
+    };
+
+    for (let propertyName in requested) {
+        let value = clout.coerce(clout.newCoercible(requested[propertyName], sourceVertex));
+        allocation.properties[propertyName] = value;
+
+        let remaining = tosca.getComparable(clout.coerce(capability.properties[propertyName]));
+        if ((remaining === null) || (typeof remaining !== 'number')) {
+            // Unassigned capacity cannot be allocated from
+            allocation.insufficient.push(util.sprintf('no %s capacity', propertyName));
+            continue;
+        }
+
+        for (let e = 0, l = targetVertex.edgesIn.size(); e < l; e++) {
+            let edge = targetVertex.edgesIn[e];
+            if (tosca.isTosca(edge, 'Relationship') && (edge.properties.capability === capabilityName) && edge.properties.allocation)
+                remaining -= tosca.getComparable(edge.properties.allocation.properties[propertyName]) || 0;
+        }
+
+        remaining -= tosca.getComparable(value);
+        allocation.remaining[propertyName] = remaining;
+        if (remaining < 0)
+            allocation.insufficient.push(util.sprintf('%s %v', propertyName, remaining));
+    }
+
+    return allocation;
+}
+
+function addRelationship(sourceVertex, requirement, targetVertex, capabilityName, allocation) {
     let edge = sourceVertex.newEdgeTo(targetVertex);
     edge.metadata['puccini'] = {
         version: '1.0',
@@ -346,6 +399,12 @@ function addRelationship(sourceVertex, requirement, targetVertex, capabilityName
             interfaces: {},
             capability: capabilityName
         };
+
+    if (allocation !== null)
+        edge.properties.allocation = {
+            properties: allocation.properties,
+            remaining: allocation.remaining
+        };
 }
 
 function countRelationships(vertex, capabilityName) {
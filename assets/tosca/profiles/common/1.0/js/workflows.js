@@ -0,0 +1,337 @@
+const tosca = require('tosca.lib.utils');
+
+// See WORKFLOWS.md
+
+// Collect node templates and existing workflows
+let nodeTemplateVertexes = [];
+let workflowVertexes = {};
+for (let vertexId in clout.vertexes) {
+    let vertex = clout.vertexes[vertexId];
+    if (tosca.isNodeTemplate(vertex))
+        nodeTemplateVertexes.push(vertex);
+    else if (tosca.isTosca(vertex, 'Workflow'))
+        workflowVertexes[vertex.properties.name] = vertex;
+}
+
+// For consistent results, we will sort the node templates by name
+nodeTemplateVertexes.sort(function(a, b) {
+    return a.properties.name < b.properties.name ? -1 : 1;
+});
+
+generate('install', install);
+generate('uninstall', uninstall);
+
+if (env.arguments.history !== 'false')
+    tosca.addHistory('workflows');
+transcribe.output(clout);
+
+function install(workflow) {
+    for (let v = 0, l = nodeTemplateVertexes.length; v < l; v++) {
+        let vertex = nodeTemplateVertexes[v];
+        let name = vertex.properties.name;
+
+        workflow.addStep(name + '_create', vertex, null, [
+            ['setNodeState', 'creating'],
+            ['callOperation', vertex.properties.interfaces, 'Standard', 'create'],
+            ['setNodeState', 'created']
+        ]);
+        workflow.addStep(name + '_configure', vertex, null, [
+            ['setNodeState', 'configuring'],
+            ['callOperation', vertex.properties.interfaces, 'Standard', 'configure'],
+            ['setNodeState', 'configured']
+        ]);
+        workflow.addStep(name + '_start', vertex, null, [
+            ['setNodeState', 'starting'],
+            ['callOperation', vertex.properties.interfaces, 'Standard', 'start'],
+            ['setNodeState', 'started']
+        ]);
+
+        workflow.link(name + '_create', name + '_configure');
+        workflow.link(name + '_configure', name + '_start');
+    }
+
+    forEachRelationship(function(vertex, edge, relationshipName) {
+        let name = vertex.properties.name;
+        let targetName = edge.target.properties.name;
+        let interfaces = edge.properties.interfaces;
+        let requirementName = edge.properties.name;
+
+        workflow.addStep(relationshipName + '_pre_configure', vertex, requirementName, [
+            ['callOperation', interfaces, 'Configure', 'pre_configure_source'],
+            ['callOperation', interfaces, 'Configure', 'pre_configure_target']
+        ]);
+        workflow.addStep(relationshipName + '_post_configure', vertex, requirementName, [
+            ['callOperation', interfaces, 'Configure', 'post_configure_source'],
+            ['callOperation', interfaces, 'Configure', 'post_configure_target']
+        ]);
+        workflow.addStep(relationshipName + '_add', vertex, requirementName, [
+            ['callOperation', interfaces, 'Configure', 'add_target'],
+            ['callOperation', interfaces, 'Configure', 'add_source']
+        ]);
+
+        workflow.link(name + '_create', relationshipName + '_pre_configure');
+        workflow.link(relationshipName + '_pre_configure', name + '_configure');
+        workflow.link(name + '_configure', relationshipName + '_post_configure');
+        workflow.link(relationshipName + '_post_configure', name + '_start');
+        workflow.link(name + '_start', relationshipName + '_add');
+
+        if (isHostedOn(edge))
+            // The source can be created only after its host has started
+            workflow.link(targetName + '_start', name + '_create');
+        else
+            // The source can be configured only after its target has started
+            workflow.link(targetName + '_start', relationshipName + '_pre_configure');
+    });
+}
+
+function uninstall(workflow) {
+    for (let v = 0, l = nodeTemplateVertexes.length; v < l; v++) {
+        let vertex = nodeTemplateVertexes[v];
+        let name = vertex.properties.name;
+
+        workflow.addStep(name + '_stop', vertex, null, [
+            ['setNodeState', 'stopping'],
+            ['callOperation', vertex.properties.interfaces, 'Standard', 'stop'],
+            ['setNodeState', 'configured']
+        ]);
+        workflow.addStep(name + '_delete', vertex, null, [
+            ['setNodeState', 'deleting'],
+            ['callOperation', vertex.properties.interfaces, 'Standard', 'delete'],
+            ['setNodeState', 'initial']
+        ]);
+
+        workflow.link(name + '_stop', name + '_delete');
+    }
+
+    forEachRelationship(function(vertex, edge, relationshipName) {
+        let name = vertex.properties.name;
+        let targetName = edge.target.properties.name;
+        let interfaces = edge.properties.interfaces;
+        let requirementName = edge.properties.name;
+
+        workflow.addStep(relationshipName + '_remove', vertex, requirementName, [
+            ['callOperation', interfaces, 'Configure', 'remove_target'],
+            ['callOperation', interfaces, 'Configure', 'remove_source']
+        ]);
+
+        workflow.link(name + '_stop', relationshipName + '_remove');
+        workflow.link(relationshipName + '_remove', name + '_delete');
+
+        if (isHostedOn(edge))
+            // The host can be stopped only after the source has been deleted
+            workflow.link(name + '_delete', targetName + '_stop');
+        else
+            // The target can be stopped only after the source has been disconnected
+            workflow.link(relationshipName + '_remove', targetName + '_stop');
+    });
+}
+
+function generate(workflowName, generator) {
+    let existing = workflowVertexes[workflowName];
+    if (existing !== undefined) {
+        if (!isGenerated(existing))
+            // Explicit workflows take precedence
+            return;
+        removeWorkflow(existing);
+    }
+
+    let workflow = new Workflow(workflowName);
+    generator(workflow);
+
+    let cycle = workflow.findCycle();
+    if (cycle !== null) {
+        cycleDetected(workflowName, cycle);
+        return;
+    }
+
+    workflow.emit();
+}
+
+function forEachRelationship(f) {
+    for (let v = 0, l = nodeTemplateVertexes.length; v < l; v++) {
+        let vertex = nodeTemplateVertexes[v];
+        let indexes = {};
+        for (let e = 0, ll = vertex.edgesOut.size(); e < ll; e++) {
+            let edge = vertex.edgesOut[e];
+            if (!tosca.isTosca(edge, 'Relationship'))
+                continue;
+
+            // There could be several relationships for the same requirement name
+            let requirementName = edge.properties.name;
+            let index = indexes[requirementName] || 0;
+            indexes[requirementName] = index + 1;
+
+            f(vertex, edge, util.sprintf('%s_%s_%d', vertex.properties.name, requirementName, index));
+        }
+    }
+}
+
+function isHostedOn(edge) {
+    for (let typeName in edge.properties.types) {
+        let type = edge.properties.types[typeName];
+        if (type && type.metadata && (type.metadata.role === 'host'))
+            return true;
+    }
+    return false;
+}
+
+function isGenerated(vertex) {
+    let metadata = vertex.properties.metadata;
+    return (metadata !== undefined) && (metadata !== null) && (metadata['puccini.generated'] === 'true');
+}
+
+function removeWorkflow(vertex) {
+    for (let e = 0, l = vertex.edgesOut.size(); e < l; e++) {
+        let edge = vertex.edgesOut[e];
+        if (!tosca.isTosca(edge, 'WorkflowStep'))
+            continue;
+
+        let stepVertex = edge.target;
+        for (let ee = 0, ll = stepVertex.edgesOut.size(); ee < ll; ee++) {
+            let stepEdge = stepVertex.edgesOut[ee];
+            if (tosca.isTosca(stepEdge, 'WorkflowActivity'))
+                stepEdge.target.remove();
+        }
+        stepVertex.remove();
+    }
+    vertex.remove();
+}
+
+function cycleDetected(workflowName, cycle) {
+    let message = util.sprintf('cycle in "%s" workflow: %s', workflowName, cycle.join(' -> '));
+    if (typeof problems === 'undefined')
+        throw message;
+    else
+        problems.reportFull(11, 'Workflows', '', message, -1, -1);
+}
+
+function setMetadata(entity, kind) {
+    entity.metadata['puccini'] = {
+        version: '1.0',
+        kind: kind
+    };
+}
+
+//
+// Workflow
+//
+
+function Workflow(name) {
+    this.name = name;
+    this.steps = {};
+    this.stepNames = [];
+
+    // Activities are arrays: ['setNodeState', state] or ['callOperation', interfaces, interfaceName, operationName]
+    // (operations that don't exist are skipped)
+    this.addStep = function(name, vertex, requirementName, activities) {
+        let step = {
+            name: name,
+            vertex: vertex,
+            requirementName: requirementName,
+            activities: [],
+            next: []
+        };
+
+        for (let a = 0, l = activities.length; a < l; a++) {
+            let activity = activities[a];
+            if (activity[0] === 'setNodeState')
+                step.activities.push({setNodeState: activity[1]});
+            else if (activity[0] === 'callOperation') {
+                let interfaces = activity[1];
+                let interface_ = interfaces ? interfaces[activity[2]] : undefined;
+                if ((interface_ !== undefined) && (activity[3] in interface_.operations))
+                    step.activities.push({callOperation: {interface: activity[2], operation: activity[3], inputs: {}}});
+            }
+        }
+
+        this.steps[name] = step;
+        this.stepNames.push(name);
+    };
+
+    this.link = function(fromName, toName) {
+        let from = this.steps[fromName];
+        if ((from !== undefined) && (toName in this.steps) && (from.next.indexOf(toName) === -1))
+            from.next.push(toName);
+    };
+
+    // Returns the step names of a cycle, or null if there is none
+    this.findCycle = function() {
+        let self = this;
+        let states = {}; // 1 = visiting, 2 = visited
+        let path = [];
+
+        function visit(name) {
+            states[name] = 1;
+            path.push(name);
+            let next = self.steps[name].next;
+            for (let n = 0, l = next.length; n < l; n++) {
+                let nextName = next[n];
+                if (states[nextName] === 1)
+                    return path.slice(path.indexOf(nextName)).concat([nextName]);
+                if (states[nextName] === undefined) {
+                    let cycle = visit(nextName);
+                    if (cycle !== null)
+                        return cycle;
+                }
+            }
+            path.pop();
+            states[name] = 2;
+            return null;
+        }
+
+        for (let s = 0, l = this.stepNames.length; s < l; s++) {
+            let name = this.stepNames[s];
+            if (states[name] === undefined) {
+                let cycle = visit(name);
+                if (cycle !== null)
+                    return cycle;
+            }
+        }
+
+        return null;
+    };
+
+    // Emits vertexes in the same shape as those of explicit workflows
+    this.emit = function() {
+        let workflowVertex = clout.newVertex(clout.newKey());
+        setMetadata(workflowVertex, 'Workflow');
+        workflowVertex.properties.name = this.name;
+        workflowVertex.properties.description = util.sprintf('Generated "%s" workflow', this.name);
+        workflowVertex.properties.metadata = {'puccini.generated': 'true'};
+        workflowVertex.properties.inputs = {};
+
+        let stepVertexes = {};
+        for (let s = 0, l = this.stepNames.length; s < l; s++) {
+            let step = this.steps[this.stepNames[s]];
+
+            let stepVertex = clout.newVertex(clout.newKey());
+            setMetadata(stepVertex, 'WorkflowStep');
+            stepVertex.properties.name = step.name;
+            stepVertex.properties.targetRelationship = step.requirementName !== null ? step.requirementName : '';
+            stepVertex.properties.filter = null;
+            stepVertex.properties.host = '';
+            stepVertexes[step.name] = stepVertex;
+
+            setMetadata(workflowVertex.newEdgeTo(stepVertex), 'WorkflowStep');
+            setMetadata(stepVertex.newEdgeTo(step.vertex), 'NodeTemplateTarget');
+
+            for (let a = 0, ll = step.activities.length; a < ll; a++) {
+                let activityVertex = clout.newVertex(clout.newKey());
+                setMetadata(activityVertex, 'WorkflowActivity');
+                let activity = step.activities[a];
+                for (let key in activity)
+                    activityVertex.properties[key] = activity[key];
+
+                let edge = stepVertex.newEdgeTo(activityVertex);
+                setMetadata(edge, 'WorkflowActivity');
+                edge.properties.sequence = a;
+            }
+        }
+
+        for (let s = 0, l = this.stepNames.length; s < l; s++) {
+            let step = this.steps[this.stepNames[s]];
+            for (let n = 0, ll = step.next.length; n < ll; n++)
+                setMetadata(stepVertexes[step.name].newEdgeTo(stepVertexes[step.next[n]]), 'OnSuccess');
+        }
+    };
+}
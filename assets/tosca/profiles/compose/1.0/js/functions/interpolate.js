@@ -0,0 +1,43 @@
+
+// [https://docs.docker.com/reference/compose-file/interpolation/]
+
+const tosca = require('tosca.lib.utils');
+
+// Variables are inputs; unset variables are null
+exports.evaluate = function(string) {
+	if (arguments.length !== 1)
+		throw 'must have 1 argument';
+	if (!tosca.isTosca(clout))
+		throw 'Clout is not TOSCA';
+	let inputs = clout.properties.tosca.inputs;
+	return string.replace(/\$(?:(\$)|\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?+])([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))/g, function(match, escape, name, operator, argument, unbracedName) {
+		if (escape !== undefined)
+			return '$';
+		if (unbracedName !== undefined)
+			name = unbracedName;
+		let value = null;
+		if (name in inputs)
+			value = clout.coerce(inputs[name]);
+		let unset = (value === null) || (value === undefined);
+		let empty = unset || (value === '');
+		switch (operator) {
+		case ':-':
+			return empty ? argument : value;
+		case '-':
+			return unset ? argument : value;
+		case ':?':
+			if (empty)
+				throw util.sprintf('variable %q: %s', name, argument || 'required');
+			return value;
+		case '?':
+			if (unset)
+				throw util.sprintf('variable %q: %s', name, argument || 'required');
+			return value;
+		case ':+':
+			return empty ? '' : argument;
+		case '+':
+			return unset ? '' : argument;
+		}
+		return unset ? '' : value;
+	});
+};
@@ -3,12 +3,13 @@ package profiles
 import (
 	"context"
 	"embed"
+	"io/fs"
 
 	"github.com/tliron/exturl"
 	"github.com/tliron/go-kutil/util"
 )
 
-//go:embed cloudify/* common/* hot/* implicit/* simple/* simple-for-nfv/*
+//go:embed cloudify/* cloudformation/* common/* compose/* etsi-nfv-sol001/* hot/* implicit/* simple/* simple-for-nfv/*
 var profiles embed.FS
 
 func init() {
@@ -19,6 +20,10 @@ func init() {
 	}
 }
 
+func FS() fs.FS {
+	return profiles
+}
+
 func Get(path string) []byte {
 	if content, err := profiles.ReadFile(path); err == nil {
 		return content
@@ -120,18 +120,30 @@ func (self *FunctionCall) Signature(arguments []ard.Value) string {
 
 // ([Coercible] interface)
 func (self *FunctionCall) Coerce() (ard.Value, error) {
+	trace := self.GetTrace()
+	record := trace.Begin(self, "evaluate")
+
 	arguments, err := self.CoerceArguments()
 	if err != nil {
+		trace.End(record, nil, err)
 		return nil, err
 	}
 
 	logEvaluate.Debugf("%s %s", self.Path, self.Signature(arguments))
 
+	if err := trace.Enter(record, arguments); err != nil {
+		trace.End(record, nil, err)
+		return nil, self.WrapError(arguments, err)
+	}
+
 	data, err := self.ExecutionContext.Call(self.Name, "evaluate", arguments...)
 	if err != nil {
+		trace.End(record, nil, err)
 		return nil, self.WrapError(arguments, err)
 	}
 
+	trace.End(record, data, nil)
+
 	// TODO: Coerce result?
 
 	if err := self.Validators.Apply(data); err == nil {
@@ -167,8 +179,12 @@ func (self *FunctionCall) CoerceArguments() ([]ard.Value, error) {
 }
 
 func (self *FunctionCall) Validate(value ard.Value, errorWhenInvalid bool) (bool, error) {
+	trace := self.GetTrace()
+	record := trace.Begin(self, "validate")
+
 	arguments, err := self.CoerceArguments()
 	if err != nil {
+		trace.End(record, nil, err)
 		return false, err
 	}
 
@@ -177,11 +193,19 @@ func (self *FunctionCall) Validate(value ard.Value, errorWhenInvalid bool) (bool
 
 	logValidate.Debugf("%s %s", self.Path, self.Signature(arguments))
 
+	if err := trace.Enter(record, arguments); err != nil {
+		trace.End(record, nil, err)
+		return false, self.WrapError(arguments, err)
+	}
+
 	r, err := self.ExecutionContext.Call(self.Name, "validate", arguments...)
 	if err != nil {
+		trace.End(record, nil, err)
 		return false, self.WrapError(arguments, err)
 	}
 
+	trace.End(record, r, nil)
+
 	switch valid := r.(type) {
 	case bool:
 		if valid {
@@ -205,13 +229,30 @@ func (self *FunctionCall) Convert(value ard.Value) (ard.Value, error) {
 
 	logConvert.Debugf("%s %s", self.Path, self.Signature(arguments))
 
+	trace := self.GetTrace()
+	record := trace.Begin(self, "convert")
+	if err := trace.Enter(record, arguments); err != nil {
+		trace.End(record, nil, err)
+		return false, self.WrapError(arguments, err)
+	}
+
 	if r, err := self.ExecutionContext.Call(self.Name, "convert", arguments...); err == nil {
+		trace.End(record, r, nil)
 		return r, nil
 	} else {
+		trace.End(record, nil, err)
 		return false, self.WrapError(arguments, err)
 	}
 }
 
+// Will return nil if we are not tracing
+func (self *FunctionCall) GetTrace() *Trace {
+	if (self.ExecutionContext != nil) && (self.ExecutionContext.CloutContext != nil) && (self.ExecutionContext.CloutContext.Context != nil) {
+		return self.ExecutionContext.CloutContext.Context.Trace
+	}
+	return nil
+}
+
 // Utils
 
 func encodeArgument(argument ard.Value) string {
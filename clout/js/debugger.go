@@ -0,0 +1,163 @@
+package js
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/tliron/go-kutil/terminal"
+)
+
+const debuggerHelp = `c, continue    continue until the next breakpoint
+s, step        break at the next function call
+a, arguments   print the coerced arguments
+w, where       print the function call stack
+t, trace       print the trace so far
+b NAME         add a breakpoint for a scriptlet name ("*" for all)
+d NAME         delete a breakpoint
+l, list        list breakpoints
+q, quit        abort execution
+h, help        print this help
+`
+
+var DebuggerAbortedError = errors.New("aborted by debugger")
+
+//
+// Debugger
+//
+
+type Debugger struct {
+	Breakpoints map[string]struct{}
+	Writer      io.Writer
+	Stylist     *terminal.Stylist
+
+	reader   *bufio.Reader
+	stepping bool
+	aborted  bool
+	lock     sync.Mutex
+}
+
+func NewDebugger(breakpoints []string, reader io.Reader, writer io.Writer, stylist *terminal.Stylist) *Debugger {
+	if stylist == nil {
+		stylist = terminal.NewStylist(false)
+	}
+
+	self := Debugger{
+		Breakpoints: make(map[string]struct{}),
+		Writer:      writer,
+		Stylist:     stylist,
+		reader:      bufio.NewReader(reader),
+	}
+
+	for _, breakpoint := range breakpoints {
+		self.Breakpoints[breakpoint] = struct{}{}
+	}
+
+	return &self
+}
+
+func (self *Debugger) ShouldBreak(scriptletName string) bool {
+	if self.stepping {
+		return true
+	}
+	if _, ok := self.Breakpoints["*"]; ok {
+		return true
+	}
+	_, ok := self.Breakpoints[scriptletName]
+	return ok
+}
+
+// Blocks until the user continues. Returns [DebuggerAbortedError] if the user quits,
+// and will keep returning it for all subsequent calls.
+func (self *Debugger) Break(trace *Trace, record *TraceRecord) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if self.aborted {
+		return DebuggerAbortedError
+	}
+
+	if !self.ShouldBreak(record.Scriptlet) {
+		return nil
+	}
+
+	self.stepping = false
+
+	fmt.Fprintf(self.Writer, "%s ", self.Stylist.Heading("break:"))
+	record.Write(self.Writer, self.Stylist)
+
+	for {
+		fmt.Fprint(self.Writer, "(debug) ")
+
+		line, err := self.reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				// No more input, so continue without breaking again
+				self.Breakpoints = make(map[string]struct{})
+				fmt.Fprintln(self.Writer)
+				return nil
+			}
+			return err
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "c", "continue":
+			return nil
+
+		case "s", "step":
+			self.stepping = true
+			return nil
+
+		case "a", "arguments":
+			for index, argument := range record.Arguments {
+				fmt.Fprintf(self.Writer, "%d: %s\n", index, self.Stylist.Value(encodeArgument(argument)))
+			}
+
+		case "w", "where":
+			for record_ := record; record_ != nil; record_ = record_.Parent {
+				record_.Write(self.Writer, self.Stylist)
+			}
+
+		case "t", "trace":
+			// Note: we are already holding our own lock, but not the trace's
+			trace.Write(self.Writer, self.Stylist)
+
+		case "b":
+			if len(fields) == 2 {
+				self.Breakpoints[fields[1]] = struct{}{}
+			} else {
+				fmt.Fprintln(self.Writer, self.Stylist.Error("usage: b NAME"))
+			}
+
+		case "d":
+			if len(fields) == 2 {
+				delete(self.Breakpoints, fields[1])
+			} else {
+				fmt.Fprintln(self.Writer, self.Stylist.Error("usage: d NAME"))
+			}
+
+		case "l", "list":
+			for breakpoint := range self.Breakpoints {
+				fmt.Fprintln(self.Writer, self.Stylist.Name(breakpoint))
+			}
+
+		case "q", "quit":
+			self.aborted = true
+			return DebuggerAbortedError
+
+		case "h", "help", "?":
+			fmt.Fprint(self.Writer, debuggerHelp)
+
+		default:
+			fmt.Fprintf(self.Writer, "%s\n", self.Stylist.Error(fmt.Sprintf("unknown command: %s", fields[0])))
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	contextpkg "context"
 	"io"
 	"os"
-	"sync"
 
 	"github.com/dop251/goja"
 	"github.com/tliron/commonjs-goja"
@@ -33,8 +32,9 @@ type Environment struct {
 	Stdin         io.Writer
 	StdoutStylist *terminal.Stylist
 	URLContext    *exturl.Context
-
-	programCache sync.Map
+	Trace         *Trace
+	ProgramCache  *ProgramCache
+	Redactor      *Redactor
 }
 
 func NewEnvironment(name string, log commonlog.Logger, arguments map[string]string, quiet bool, format string, strict bool, pretty bool, base64 bool, filePath string, urlContext *exturl.Context) *Environment {
@@ -65,21 +65,47 @@ func (self *Environment) Require(clout *cloutpkg.Clout, scriptletName string, ex
 }
 
 func (self *Environment) NewJsEnvironment(clout *cloutpkg.Clout, extensions map[string]commonjs.CreateExtensionFunc) *commonjs.Environment {
-	environment := commonjs.NewEnvironment(self.URLContext)
+	var environment *commonjs.Environment
+	if self.ProgramCache != nil {
+		environment = self.ProgramCache.NewEnvironment(self.URLContext)
+	} else {
+		environment = commonjs.NewEnvironment(self.URLContext)
+	}
 
 	environment.CreateResolver = func(url exturl.URL, jsContext *commonjs.Context) commonjs.ResolveFunc {
 		// commonjs.ResolveFunc signature
 		return func(context contextpkg.Context, id string, bareId bool) (exturl.URL, error) {
+			// Relative to the requiring scriptlet
+			switch url_ := url.(type) {
+			case *exturl.InternalURL:
+				id = ResolveScriptletName(id, url_.Path)
+			case *ScriptletURL:
+				id = ResolveScriptletName(id, url_.Path)
+			}
+
 			if scriptlet, err := GetScriptlet(id, clout); err == nil {
-				url := self.URLContext.NewInternalURL(id)
-				url.SetContent(scriptlet)
-				return url, nil
+				if self.ProgramCache != nil {
+					return NewScriptletURL(self.URLContext, id, scriptlet, environment.Extensions), nil
+				} else {
+					url := self.URLContext.NewInternalURL(id)
+					url.SetContent(scriptlet)
+					return url, nil
+				}
 			} else {
 				return nil, err
 			}
 		}
 	}
 
+	// ES modules are transpiled to CommonJS
+	// (Called only when the program is not cached)
+	environment.Precompile = func(url exturl.URL, script string, jsContext *commonjs.Context) (string, error) {
+		if self.ProgramCache != nil {
+			self.ProgramCache.compilations.Add(1)
+		}
+		return TranspileESModule(script)
+	}
+
 	environment.Extensions = []commonjs.Extension{{
 		Name:   "bind",
 		Create: api.CreateEarlyBindExtension,
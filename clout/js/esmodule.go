@@ -0,0 +1,1025 @@
+package js
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ECMAScript module syntax (import and export statements) is not supported by goja, so
+// we transpile it to CommonJS. Other modern syntax (async functions, optional chaining,
+// etc.) is supported natively by goja.
+//
+// Notes:
+//
+//   - Named imports are bound when the import statement runs, not live. This only
+//     matters for circular imports.
+//   - Line numbers are preserved so that errors point to the original source.
+//   - Top-level await is not supported.
+
+const esModulePrologue = "Object.defineProperty(exports, '__esModule', { value: true });"
+
+// Transpiles ECMAScript module syntax to CommonJS. If the script is not a module it
+// will be returned as is.
+func TranspileESModule(script string) (string, error) {
+	if !strings.Contains(script, "import") && !strings.Contains(script, "export") {
+		return script, nil
+	}
+
+	transpiler := newESModuleTranspiler(script)
+	if err := transpiler.transpile(); err != nil {
+		if !transpiler.isModule && !transpiler.usesDynamicImport {
+			// Our lexer is only a heuristic, so leave syntax errors in non-modules to the runtime
+			return script, nil
+		}
+		return "", err
+	}
+
+	if !transpiler.isModule && !transpiler.usesDynamicImport {
+		return script, nil
+	}
+
+	var builder strings.Builder
+	if transpiler.isModule {
+		builder.WriteString(esModulePrologue)
+	}
+	if transpiler.usesDynamicImport {
+		builder.WriteString(" const __import = function (id) { return new Promise(function (resolve) { resolve(require(id)); }); };")
+	}
+	if transpiler.usesImportMeta {
+		builder.WriteString(" const __importMeta = { url: __filename };")
+	}
+	for _, statement := range transpiler.prologue {
+		builder.WriteString(" ")
+		builder.WriteString(statement)
+	}
+	// Note: no newline, so that line numbers are preserved
+	builder.WriteString(" ")
+	builder.WriteString(transpiler.output.String())
+
+	return builder.String(), nil
+}
+
+//
+// esModuleTranspiler
+//
+
+type esModuleTranspiler struct {
+	script            string
+	position          int
+	output            strings.Builder
+	prologue          []string
+	depth             int
+	regexAllowed      bool
+	previousWord      string
+	previousChar      byte // last character of the previous token, 0 at the start
+	newline           bool // whether a line terminator follows the previous token
+	controlParens     []bool
+	afterControlParen bool
+	blockBraces       []bool
+	counter           int
+	isModule          bool
+	usesDynamicImport bool
+	usesImportMeta    bool
+}
+
+func newESModuleTranspiler(script string) *esModuleTranspiler {
+	return &esModuleTranspiler{
+		script:       script,
+		regexAllowed: true,
+	}
+}
+
+func (self *esModuleTranspiler) transpile() error {
+	length := len(self.script)
+	for self.position < length {
+		c := self.script[self.position]
+
+		switch {
+		case isJSWhitespace(c):
+			if c == '\n' {
+				self.newline = true
+			}
+			self.copy(self.position + 1)
+
+		case (c == '/') && self.peek(1) == '/', (c == '/') && self.peek(1) == '*':
+			end, err := skipJSComment(self.script, self.position)
+			if err != nil {
+				return err
+			}
+			if strings.Contains(self.script[self.position:end], "\n") {
+				self.newline = true
+			}
+			self.copy(end)
+
+		case (c == '\'') || (c == '"'):
+			end, err := skipJSString(self.script, self.position)
+			if err != nil {
+				return err
+			}
+			self.token(end)
+			self.regexAllowed = false
+			self.previousWord = ""
+
+		case c == '`':
+			end, err := skipJSTemplate(self.script, self.position)
+			if err != nil {
+				return err
+			}
+			self.token(end)
+			self.regexAllowed = false
+			self.previousWord = ""
+
+		case (c == '/') && self.regexAllowed:
+			end, err := skipJSRegex(self.script, self.position)
+			if err != nil {
+				return err
+			}
+			self.token(end)
+			self.regexAllowed = false
+			self.previousWord = ""
+
+		case isJSNumberStart(self.script, self.position):
+			self.token(self.position + len(readJSNumber(self.script, self.position)))
+			self.regexAllowed = false
+			self.previousWord = ""
+
+		case isJSIdentifierStart(rune(c)):
+			start := self.position
+			word := readJSIdentifier(self.script, start)
+			end := start + len(word)
+
+			var err error
+			switch {
+			case (word == "import") && self.isImportExpression(end):
+				err = self.importExpression(end)
+			case ((word == "import") || (word == "export")) && self.isStatement(end):
+				if word == "import" {
+					err = self.import_(end)
+				} else {
+					err = self.export(end)
+				}
+			default:
+				self.token(end)
+				self.regexAllowed = isJSKeywordBeforeExpression(word)
+				self.previousWord = word
+			}
+			if err != nil {
+				return err
+			}
+
+		default:
+			regexAllowed := true
+			afterControlParen := false
+			switch c {
+			case '(':
+				self.controlParens = append(self.controlParens, isJSControlKeyword(self.previousWord))
+				self.depth++
+			case ')':
+				// After the condition of a control statement an expression may follow
+				afterControlParen = popJSControlParen(&self.controlParens)
+				regexAllowed = afterControlParen
+				self.depth--
+			case '{':
+				self.blockBraces = append(self.blockBraces, self.afterControlParen || isJSBlockKeyword(self.previousWord) || self.isStatementStart())
+				self.depth++
+			case '[':
+				self.depth++
+			case '}':
+				// After a block an expression statement may follow, but not after an object literal
+				regexAllowed = popJSBlockBrace(&self.blockBraces)
+				self.depth--
+			case ']':
+				regexAllowed = false
+				self.depth--
+			}
+			self.token(self.position + 1)
+			self.regexAllowed = regexAllowed
+			self.afterControlParen = afterControlParen
+			self.previousWord = ""
+		}
+	}
+
+	return nil
+}
+
+// Dynamic import or "import.meta"
+func (self *esModuleTranspiler) isImportExpression(position int) bool {
+	if previousNonSpace(self.script, position-len("import")) == '.' {
+		return false
+	}
+
+	next, _ := skipJSSpace(self.script, position)
+	switch self.peekAt(next) {
+	case '(':
+		// Not a method named "import"
+		if end, err := skipJSBalanced(self.script, next); err == nil {
+			end, _ = skipJSSpace(self.script, end)
+			return self.peekAt(end) != '{'
+		}
+		return true
+	case '.':
+		return true
+	}
+	return false
+}
+
+func (self *esModuleTranspiler) importExpression(position int) error {
+	next, _ := skipJSSpace(self.script, position)
+
+	if self.peekAt(next) == '(' {
+		// Dynamic import
+		self.usesDynamicImport = true
+		self.output.WriteString("__import")
+		self.position = position
+	} else {
+		// import.meta
+		word := readJSIdentifier(self.script, next+1)
+		if word != "meta" {
+			return self.errorf(next, "unsupported: import.%s", word)
+		}
+		self.usesImportMeta = true
+		self.output.WriteString("__importMeta")
+		self.position = next + 1 + len(word)
+	}
+
+	self.previousChar = self.script[self.position-1]
+	self.newline = false
+	self.afterControlParen = false
+	self.regexAllowed = false
+	self.previousWord = ""
+	return nil
+}
+
+// Import and export statements must be at the top level and at the start of a statement.
+// Otherwise the keyword is, for example, a property name (as in "{ import: 1 }").
+func (self *esModuleTranspiler) isStatement(position int) bool {
+	if (self.depth != 0) || !self.isStatementStart() {
+		return false
+	}
+
+	next, _ := skipJSSpace(self.script, position)
+	switch self.peekAt(next) {
+	case ':', '.', '(', '=':
+		return false
+	}
+	return true
+}
+
+func (self *esModuleTranspiler) isStatementStart() bool {
+	switch self.previousChar {
+	case 0, ';', '{', '}':
+		return true
+	}
+
+	// Automatic semicolon insertion (a heuristic)
+	return self.newline && !strings.ContainsRune(",=+-*/%&|^!?:.<>([", rune(self.previousChar)) && !isJSKeywordBeforeExpression(self.previousWord)
+}
+
+func (self *esModuleTranspiler) import_(position int) error {
+	self.isModule = true
+	tokens := newJSTokens(self.script, position)
+
+	var specifier string
+	var defaultName, namespaceName string
+	var names [][2]string // imported, local
+
+	token := tokens.next()
+	if isJSStringToken(token) {
+		// Side-effect import
+		specifier = token
+	} else {
+		if isJSIdentifierToken(token) {
+			defaultName = token
+			if token = tokens.next(); token == "," {
+				token = tokens.next()
+			}
+		}
+
+		switch token {
+		case "*":
+			if tokens.next() != "as" {
+				return self.errorf(tokens.position, "expected \"as\"")
+			}
+			if namespaceName = tokens.next(); !isJSIdentifierToken(namespaceName) {
+				return self.errorf(tokens.position, "expected namespace name")
+			}
+			token = tokens.next()
+
+		case "{":
+			var err error
+			if names, err = self.specifiers(tokens); err != nil {
+				return err
+			}
+			token = tokens.next()
+		}
+
+		if token != "from" {
+			return self.errorf(tokens.position, "expected \"from\"")
+		}
+		if specifier = tokens.next(); !isJSStringToken(specifier) {
+			return self.errorf(tokens.position, "expected module specifier")
+		}
+	}
+
+	tokens.optionalSemicolon()
+
+	var statement strings.Builder
+	if (defaultName == "") && (namespaceName == "") && (len(names) == 0) {
+		fmt.Fprintf(&statement, "require(%s);", specifier)
+	} else {
+		module := self.temporaryName()
+		fmt.Fprintf(&statement, "const %s = require(%s);", module, specifier)
+		if defaultName != "" {
+			fmt.Fprintf(&statement, " const %s = (%s && %s.__esModule) ? %s.default : %s;", defaultName, module, module, module, module)
+		}
+		if namespaceName != "" {
+			fmt.Fprintf(&statement, " const %s = %s;", namespaceName, module)
+		}
+		for _, name := range names {
+			fmt.Fprintf(&statement, " const %s = %s[%q];", name[1], module, name[0])
+		}
+	}
+
+	self.replace(tokens.position, statement.String())
+	return nil
+}
+
+func (self *esModuleTranspiler) export(position int) error {
+	self.isModule = true
+	tokens := newJSTokens(self.script, position)
+
+	token := tokens.next()
+	switch token {
+	case "default":
+		afterDefault := tokens.position
+		token = tokens.next()
+		if token == "async" {
+			token = tokens.next()
+		}
+		if (token == "function") || (token == "class") {
+			name := tokens.next()
+			if name == "*" {
+				name = tokens.next()
+			}
+			if isJSIdentifierToken(name) {
+				// Named declaration
+				self.exportGetter("default", name)
+				self.replace(afterDefault, "")
+				return nil
+			}
+		}
+		// Expression
+		self.replace(afterDefault, "exports.default =")
+		return nil
+
+	case "async", "function", "class":
+		if token == "async" {
+			token = tokens.next()
+		}
+		name := tokens.next()
+		if name == "*" {
+			name = tokens.next()
+		}
+		if !isJSIdentifierToken(name) {
+			return self.errorf(tokens.position, "expected declaration name")
+		}
+		self.exportGetter(name, name)
+		self.replace(position, "")
+		return nil
+
+	case "const", "let", "var":
+		names, err := declaredJSNames(self.script, tokens.position)
+		if err != nil {
+			return self.errorf(tokens.position, "%s", err.Error())
+		}
+		for _, name := range names {
+			self.exportGetter(name, name)
+		}
+		self.replace(position, "")
+		return nil
+
+	case "{":
+		names, err := self.specifiers(tokens)
+		if err != nil {
+			return err
+		}
+
+		afterList := tokens.position
+		if tokens.next() == "from" {
+			specifier := tokens.next()
+			if !isJSStringToken(specifier) {
+				return self.errorf(tokens.position, "expected module specifier")
+			}
+			tokens.optionalSemicolon()
+
+			var statement strings.Builder
+			module := self.temporaryName()
+			fmt.Fprintf(&statement, "const %s = require(%s);", module, specifier)
+			for _, name := range names {
+				fmt.Fprintf(&statement, " %s", jsExportGetter(name[1], fmt.Sprintf("%s[%q]", module, name[0])))
+			}
+			self.replace(tokens.position, statement.String())
+		} else {
+			tokens.position = afterList
+			tokens.optionalSemicolon()
+			for _, name := range names {
+				self.exportGetter(name[1], name[0])
+			}
+			self.replace(tokens.position, "")
+		}
+		return nil
+
+	case "*":
+		var namespaceName string
+		token = tokens.next()
+		if token == "as" {
+			if namespaceName = tokens.next(); !isJSIdentifierToken(namespaceName) && !isJSStringToken(namespaceName) {
+				return self.errorf(tokens.position, "expected namespace name")
+			}
+			token = tokens.next()
+		}
+		if token != "from" {
+			return self.errorf(tokens.position, "expected \"from\"")
+		}
+		specifier := tokens.next()
+		if !isJSStringToken(specifier) {
+			return self.errorf(tokens.position, "expected module specifier")
+		}
+		tokens.optionalSemicolon()
+
+		var statement string
+		module := self.temporaryName()
+		if namespaceName != "" {
+			statement = fmt.Sprintf("const %s = require(%s); %s", module, specifier, jsExportGetter(strings.Trim(namespaceName, "'\""), module))
+		} else {
+			statement = fmt.Sprintf("const %s = require(%s); for (const __key in %s) if ((__key !== 'default') && !Object.prototype.hasOwnProperty.call(exports, __key)) Object.defineProperty(exports, __key, { enumerable: true, get: function () { return %s[__key]; } });", module, specifier, module, module)
+		}
+		self.replace(tokens.position, statement)
+		return nil
+	}
+
+	return self.errorf(position, "unsupported export: %s", token)
+}
+
+// Parses "{ a, b as c }" after the "{", returning pairs of [external, local]
+func (self *esModuleTranspiler) specifiers(tokens *jsTokens) ([][2]string, error) {
+	var names [][2]string
+	for {
+		token := tokens.next()
+		if token == "}" {
+			return names, nil
+		}
+
+		name := strings.Trim(token, "'\"")
+		if name == "" {
+			return nil, self.errorf(tokens.position, "expected name")
+		}
+
+		alias := name
+		token = tokens.next()
+		if token == "as" {
+			alias = strings.Trim(tokens.next(), "'\"")
+			token = tokens.next()
+		}
+
+		names = append(names, [2]string{name, alias})
+
+		if token == "}" {
+			return names, nil
+		} else if token != "," {
+			return nil, self.errorf(tokens.position, "expected \",\" or \"}\"")
+		}
+	}
+}
+
+func (self *esModuleTranspiler) exportGetter(name string, local string) {
+	self.prologue = append(self.prologue, jsExportGetter(name, local))
+}
+
+// Replaces the source up to the end position, preserving newlines
+func (self *esModuleTranspiler) replace(end int, replacement string) {
+	self.output.WriteString(replacement)
+	if replacement != "" {
+		self.output.WriteString(" ")
+	}
+	self.output.WriteString(strings.Repeat("\n", strings.Count(self.script[self.position:end], "\n")))
+	self.position = end
+	self.regexAllowed = true
+	self.previousWord = ""
+	self.previousChar = ';'
+	self.newline = false
+	self.afterControlParen = false
+}
+
+// Copies a token (not whitespace or a comment)
+func (self *esModuleTranspiler) token(end int) {
+	self.copy(end)
+	self.previousChar = self.script[end-1]
+	self.newline = false
+	self.afterControlParen = false
+}
+
+func (self *esModuleTranspiler) copy(end int) {
+	self.output.WriteString(self.script[self.position:end])
+	self.position = end
+}
+
+func (self *esModuleTranspiler) peek(offset int) byte {
+	return self.peekAt(self.position + offset)
+}
+
+func (self *esModuleTranspiler) peekAt(position int) byte {
+	if position < len(self.script) {
+		return self.script[position]
+	}
+	return 0
+}
+
+func (self *esModuleTranspiler) temporaryName() string {
+	name := fmt.Sprintf("__module%d", self.counter)
+	self.counter++
+	return name
+}
+
+func (self *esModuleTranspiler) errorf(position int, format string, arguments ...any) error {
+	if position > len(self.script) {
+		position = len(self.script)
+	}
+	row := strings.Count(self.script[:position], "\n") + 1
+	return fmt.Errorf("ES module syntax @%d: %s", row, fmt.Sprintf(format, arguments...))
+}
+
+func jsExportGetter(name string, expression string) string {
+	return fmt.Sprintf("Object.defineProperty(exports, %q, { enumerable: true, get: function () { return %s; } });", name, expression)
+}
+
+//
+// jsTokens
+//
+
+// A minimal tokenizer for import/export statements
+type jsTokens struct {
+	script   string
+	position int
+}
+
+func newJSTokens(script string, position int) *jsTokens {
+	return &jsTokens{script, position}
+}
+
+// Returns "" at end of script
+func (self *jsTokens) next() string {
+	var err error
+	if self.position, err = skipJSSpace(self.script, self.position); err != nil {
+		return ""
+	}
+	if self.position >= len(self.script) {
+		return ""
+	}
+
+	start := self.position
+	c := self.script[start]
+	switch {
+	case (c == '\'') || (c == '"'):
+		if end, err := skipJSString(self.script, start); err == nil {
+			self.position = end
+		} else {
+			return ""
+		}
+	case isJSIdentifierStart(rune(c)):
+		self.position += len(readJSIdentifier(self.script, start))
+	default:
+		self.position++
+	}
+
+	return self.script[start:self.position]
+}
+
+func (self *jsTokens) optionalSemicolon() {
+	position := self.position
+	if self.next() != ";" {
+		self.position = position
+	}
+}
+
+// Utils
+
+// Returns the names bound by a const/let/var declaration starting at position (after the keyword)
+func declaredJSNames(script string, position int) ([]string, error) {
+	var names []string
+	length := len(script)
+
+	for {
+		// Binding
+		var err error
+		if position, err = skipJSSpace(script, position); err != nil {
+			return nil, err
+		}
+		if position >= length {
+			return names, nil
+		}
+
+		switch c := script[position]; {
+		case (c == '{') || (c == '['):
+			end, err := skipJSBalanced(script, position)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, patternJSNames(script[position+1:end-1])...)
+			position = end
+		case isJSIdentifierStart(rune(c)):
+			name := readJSIdentifier(script, position)
+			names = append(names, name)
+			position += len(name)
+		default:
+			return nil, fmt.Errorf("unsupported declaration")
+		}
+
+		// Skip initializer until "," or end of statement
+		nextDeclarator := false
+		for !nextDeclarator {
+			if position >= length {
+				return names, nil
+			}
+
+			c := script[position]
+			switch {
+			case c == ';':
+				return names, nil
+			case c == ',':
+				nextDeclarator = true
+				position++
+			case c == '\n':
+				// Automatic semicolon insertion (a heuristic)
+				if continuesJSStatement(script, position) {
+					position++
+				} else {
+					return names, nil
+				}
+			case (c == '{') || (c == '(') || (c == '['):
+				if position, err = skipJSBalanced(script, position); err != nil {
+					return nil, err
+				}
+			case (c == '\'') || (c == '"'):
+				if position, err = skipJSString(script, position); err != nil {
+					return nil, err
+				}
+			case c == '`':
+				if position, err = skipJSTemplate(script, position); err != nil {
+					return nil, err
+				}
+			case (c == '/') && ((peekJS(script, position+1) == '/') || (peekJS(script, position+1) == '*')):
+				if position, err = skipJSComment(script, position); err != nil {
+					return nil, err
+				}
+			default:
+				position++
+			}
+		}
+	}
+}
+
+// Extracts bound names from a destructuring pattern (without the outer brackets)
+func patternJSNames(pattern string) []string {
+	var names []string
+	position := 0
+	length := len(pattern)
+	for position < length {
+		c := pattern[position]
+		switch {
+		case (c == '{') || (c == '['):
+			if end, err := skipJSBalanced(pattern, position); err == nil {
+				names = append(names, patternJSNames(pattern[position+1:end-1])...)
+				position = end
+			} else {
+				return names
+			}
+		case c == '=':
+			// Skip default value
+			for (position < length) && (pattern[position] != ',') {
+				if (pattern[position] == '{') || (pattern[position] == '(') || (pattern[position] == '[') {
+					if end, err := skipJSBalanced(pattern, position); err == nil {
+						position = end
+						continue
+					}
+				}
+				position++
+			}
+		case isJSIdentifierStart(rune(c)):
+			name := readJSIdentifier(pattern, position)
+			position += len(name)
+			next, _ := skipJSSpace(pattern, position)
+			if peekJS(pattern, next) == ':' {
+				// Property key, not a binding
+				position = next + 1
+			} else {
+				names = append(names, name)
+			}
+		default:
+			position++
+		}
+	}
+	return names
+}
+
+func continuesJSStatement(script string, newline int) bool {
+	previous := previousNonSpace(script, newline)
+	next, _ := skipJSSpace(script, newline)
+	return strings.ContainsRune(",=+-*/%&|^!?:.<>(", rune(previous)) || strings.ContainsRune(",=+-*/%&|^?:.)", rune(peekJS(script, next)))
+}
+
+func skipJSSpace(script string, position int) (int, error) {
+	length := len(script)
+	for position < length {
+		c := script[position]
+		if isJSWhitespace(c) {
+			position++
+		} else if (c == '/') && ((peekJS(script, position+1) == '/') || (peekJS(script, position+1) == '*')) {
+			var err error
+			if position, err = skipJSComment(script, position); err != nil {
+				return position, err
+			}
+		} else {
+			break
+		}
+	}
+	return position, nil
+}
+
+func skipJSComment(script string, position int) (int, error) {
+	if peekJS(script, position+1) == '/' {
+		if end := strings.IndexByte(script[position:], '\n'); end != -1 {
+			return position + end, nil
+		}
+		return len(script), nil
+	}
+
+	if end := strings.Index(script[position+2:], "*/"); end != -1 {
+		return position + 2 + end + 2, nil
+	}
+	return 0, fmt.Errorf("unterminated comment")
+}
+
+func skipJSString(script string, position int) (int, error) {
+	quote := script[position]
+	length := len(script)
+	for position++; position < length; position++ {
+		switch script[position] {
+		case '\\':
+			position++
+		case quote:
+			return position + 1, nil
+		case '\n':
+			return 0, fmt.Errorf("unterminated string")
+		}
+	}
+	return 0, fmt.Errorf("unterminated string")
+}
+
+func skipJSTemplate(script string, position int) (int, error) {
+	length := len(script)
+	for position++; position < length; position++ {
+		switch script[position] {
+		case '\\':
+			position++
+		case '`':
+			return position + 1, nil
+		case '$':
+			if peekJS(script, position+1) == '{' {
+				end, err := skipJSBalanced(script, position+1)
+				if err != nil {
+					return 0, err
+				}
+				position = end - 1
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated template literal")
+}
+
+func skipJSRegex(script string, position int) (int, error) {
+	length := len(script)
+	inClass := false
+	for position++; position < length; position++ {
+		switch script[position] {
+		case '\\':
+			position++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				// Flags
+				position++
+				for (position < length) && isJSIdentifierPart(rune(script[position])) {
+					position++
+				}
+				return position, nil
+			}
+		case '\n':
+			return 0, fmt.Errorf("unterminated regular expression")
+		}
+	}
+	return 0, fmt.Errorf("unterminated regular expression")
+}
+
+// Skips from an opening bracket to after its matching closing bracket
+func skipJSBalanced(script string, position int) (int, error) {
+	depth := 0
+	length := len(script)
+	regexAllowed := true
+	var previousWord string
+	var previousChar byte
+	var controlParens, blockBraces []bool
+	afterControlParen := false
+	for position < length {
+		c := script[position]
+		var err error
+		wasAfterControlParen := afterControlParen
+		afterControlParen = false
+		switch {
+		case (c == '{') || (c == '(') || (c == '['):
+			switch c {
+			case '(':
+				controlParens = append(controlParens, isJSControlKeyword(previousWord))
+			case '{':
+				blockBraces = append(blockBraces, wasAfterControlParen || isJSBlockKeyword(previousWord) || (previousChar == ';') || (previousChar == '{') || (previousChar == '}'))
+			}
+			depth++
+			position++
+			regexAllowed = true
+			previousWord = ""
+		case (c == '}') || (c == ')') || (c == ']'):
+			depth--
+			position++
+			if depth == 0 {
+				return position, nil
+			}
+			switch c {
+			case ')':
+				// After the condition of a control statement an expression may follow
+				afterControlParen = popJSControlParen(&controlParens)
+				regexAllowed = afterControlParen
+			case '}':
+				// After a block an expression statement may follow, but not after an object literal
+				regexAllowed = popJSBlockBrace(&blockBraces)
+			default:
+				regexAllowed = false
+			}
+			previousWord = ""
+		case (c == '\'') || (c == '"'):
+			position, err = skipJSString(script, position)
+			regexAllowed = false
+			previousWord = ""
+		case c == '`':
+			position, err = skipJSTemplate(script, position)
+			regexAllowed = false
+			previousWord = ""
+		case (c == '/') && ((peekJS(script, position+1) == '/') || (peekJS(script, position+1) == '*')):
+			afterControlParen = wasAfterControlParen
+			if position, err = skipJSComment(script, position); err != nil {
+				return 0, err
+			}
+			continue
+		case (c == '/') && regexAllowed:
+			position, err = skipJSRegex(script, position)
+			regexAllowed = false
+			previousWord = ""
+		case isJSNumberStart(script, position):
+			position += len(readJSNumber(script, position))
+			regexAllowed = false
+			previousWord = ""
+		case isJSIdentifierStart(rune(c)):
+			word := readJSIdentifier(script, position)
+			position += len(word)
+			regexAllowed = isJSKeywordBeforeExpression(word)
+			previousWord = word
+		case isJSWhitespace(c):
+			afterControlParen = wasAfterControlParen
+			position++
+		default:
+			position++
+			regexAllowed = true
+			previousWord = ""
+		}
+		if err != nil {
+			return 0, err
+		}
+		if !isJSWhitespace(c) && (position > 0) {
+			previousChar = script[position-1]
+		}
+	}
+	return 0, fmt.Errorf("unbalanced brackets")
+}
+
+func readJSIdentifier(script string, position int) string {
+	end := position
+	for end < len(script) {
+		if !isJSIdentifierPart(rune(script[end])) && (script[end] < 0x80) {
+			break
+		}
+		end++
+	}
+	return script[position:end]
+}
+
+func readJSNumber(script string, position int) string {
+	end := position
+	if script[end] == '.' {
+		end++
+	}
+	end += len(readJSIdentifier(script, end))
+	return script[position:end]
+}
+
+func previousNonSpace(script string, position int) byte {
+	for position--; position >= 0; position-- {
+		if !isJSWhitespace(script[position]) {
+			return script[position]
+		}
+	}
+	return 0
+}
+
+func peekJS(script string, position int) byte {
+	if position < len(script) {
+		return script[position]
+	}
+	return 0
+}
+
+func isJSWhitespace(c byte) bool {
+	return (c == ' ') || (c == '\t') || (c == '\n') || (c == '\r') || (c == '\v') || (c == '\f')
+}
+
+func isJSIdentifierStart(c rune) bool {
+	return (c == '_') || (c == '$') || unicode.IsLetter(c) || (c >= 0x80)
+}
+
+func isJSIdentifierPart(c rune) bool {
+	return isJSIdentifierStart(c) || unicode.IsDigit(c)
+}
+
+func isJSIdentifierToken(token string) bool {
+	return (token != "") && isJSIdentifierStart(rune(token[0]))
+}
+
+func isJSStringToken(token string) bool {
+	return (token != "") && ((token[0] == '\'') || (token[0] == '"'))
+}
+
+// After these keywords a "/" starts a regular expression rather than a division
+func isJSKeywordBeforeExpression(word string) bool {
+	switch word {
+	case "return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await":
+		return true
+	}
+	return false
+}
+
+// Numeric literals, including "0x1f", "1e5", "10n", and ".5"
+func isJSNumberStart(script string, position int) bool {
+	c := script[position]
+	if c == '.' {
+		c = peekJS(script, position+1)
+	}
+	return (c >= '0') && (c <= '9')
+}
+
+// The parenthesized condition after these keywords may be followed by an expression statement
+func isJSControlKeyword(word string) bool {
+	switch word {
+	case "if", "while", "for", "with":
+		return true
+	}
+	return false
+}
+
+// A "{" after these keywords opens a block rather than an object literal
+func isJSBlockKeyword(word string) bool {
+	switch word {
+	case "else", "do", "try", "finally":
+		return true
+	}
+	return false
+}
+
+func popJSBlockBrace(blockBraces *[]bool) bool {
+	if length := len(*blockBraces); length > 0 {
+		block := (*blockBraces)[length-1]
+		*blockBraces = (*blockBraces)[:length-1]
+		return block
+	}
+	return false
+}
+
+func popJSControlParen(controlParens *[]bool) bool {
+	if length := len(*controlParens); length > 0 {
+		control := (*controlParens)[length-1]
+		*controlParens = (*controlParens)[:length-1]
+		return control
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package js
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dop251/goja"
+)
+
+var esModuleTests = []struct {
+	name    string
+	script  string
+	exports map[string]any // nil means the script is not a module
+}{
+	{
+		name:    "division",
+		script:  "export function g(x) { return x / 2 / 3; }\nexport const v = g(12);",
+		exports: map[string]any{"v": int64(2)},
+	},
+	{
+		name:    "division in class",
+		script:  "export default class K { m(x) { return x / 2 / 3; } }\nexport const v = new K().m(60);",
+		exports: map[string]any{"v": int64(10)},
+	},
+	{
+		name:    "division by identifiers and numbers",
+		script:  "const a = 12, b = 2;\nexport const v = a / b / .5 / 1e1;\nexport const w = (a) / [b][0] / 3;",
+		exports: map[string]any{"v": 1.2, "w": int64(2)},
+	},
+	{
+		name:    "regex literals",
+		script:  "const re = /export/g;\nlet x = true, y = 'import';\nif (x) /import/.test(y);\nexport function validate() { return /[/]import/.test('/import') && re.test('export'); }\nexport const v = validate();",
+		exports: map[string]any{"v": true},
+	},
+	{
+		name:    "regex after keyword",
+		script:  "export function f(s) { return typeof /export/ === 'object' && /a/.test(s); }\nexport const v = f('a');",
+		exports: map[string]any{"v": true},
+	},
+	{
+		name:    "template literals",
+		script:  "const name = 'import';\nexport const v = `export ${name} from ${ { a: 1 }.a / 1 }`;",
+		exports: map[string]any{"v": "export import from 1"},
+	},
+	{
+		name:    "comments",
+		script:  "// import x from 'y';\n/* export const z = 1; */\nexport const v = 1; // export default 2",
+		exports: map[string]any{"v": int64(1)},
+	},
+	{
+		name:    "strings",
+		script:  "export const v = 'import x from \"y\"' + \"export default 1\";",
+		exports: map[string]any{"v": "import x from \"y\"export default 1"},
+	},
+	{
+		name:    "imports",
+		script:  "import other, { a, b as c } from './other';\nimport * as ns from './other';\nexport const v = a + c + ns.a + other.a;",
+		exports: map[string]any{"v": int64(5)},
+	},
+	{
+		name:    "re-exports",
+		script:  "export { a, b as c } from './other';\nexport * as ns from './other';",
+		exports: map[string]any{"a": int64(1), "c": int64(2)},
+	},
+	{
+		name:    "re-export all",
+		script:  "export * from './other';",
+		exports: map[string]any{"a": int64(1), "b": int64(2)},
+	},
+	{
+		name:    "export list",
+		script:  "const a = 1, b = 2;\nexport { a, b as c };",
+		exports: map[string]any{"a": int64(1), "c": int64(2)},
+	},
+	{
+		name:    "export default expression",
+		script:  "export default 1 / 2;",
+		exports: map[string]any{"default": 0.5},
+	},
+	{
+		name:    "export default function",
+		script:  "export default function f() { return 1; }\nexport const v = f();",
+		exports: map[string]any{"v": int64(1)},
+	},
+	{
+		name:   "not a module",
+		script: "const important = 'export';\nimportant.length / 2 / 3;",
+	},
+	{
+		name:   "import and export property keys",
+		script: "function f() { return { import: 1, export: { export: 2 } }; }\nconst o = { a: { import: 1 } };\nexports.v = f().import + o.a.import;",
+	},
+	{
+		name:   "import and export properties",
+		script: "const o = { import: 1, export: 2 };\no.import + o.export;\nexports.export = o.export;",
+	},
+	{
+		name:   "regex after block",
+		script: "let x = true, y = \"'\";\nif (x) {}\n/'/.test(y);\nwhile (false) { x = 1; } /'/.test(y);",
+	},
+	{
+		name:    "regex after block in module",
+		script:  "let x = true, y = \"'\";\nif (x) {}\n/'/.test(y);\nexport function f() { try {} finally {} /'/.test(y); return { a: 4 }.a / 2 / 2; }\nexport const v = f();",
+		exports: map[string]any{"v": int64(1)},
+	},
+	{
+		name:    "import and export keys in module",
+		script:  "import { a } from './other';\nexport const o = { import: a, export: { export: 2 } };\nexport const v = o.import + o.export.export;",
+		exports: map[string]any{"v": int64(3)},
+	},
+	{
+		name:    "statement after newline",
+		script:  "const a = 1\nexport const v = a\nexport { a }",
+		exports: map[string]any{"a": int64(1), "v": int64(1)},
+	},
+}
+
+func TestTranspileESModule(t *testing.T) {
+	for _, test := range esModuleTests {
+		t.Run(test.name, func(t *testing.T) {
+			script, err := TranspileESModule(test.script)
+			if err != nil {
+				t.Fatalf("%s\n%s", err.Error(), test.script)
+			}
+
+			if test.exports == nil {
+				// Must be lexed without errors (not only left to the runtime)
+				if err := newESModuleTranspiler(test.script).transpile(); err != nil {
+					t.Fatalf("%s\n%s", err.Error(), test.script)
+				}
+				if script != test.script {
+					t.Fatalf("script was changed:\n%s", script)
+				}
+				return
+			}
+
+			if lines, originalLines := strings.Count(script, "\n"), strings.Count(test.script, "\n"); lines != originalLines {
+				t.Errorf("line count changed from %d to %d", originalLines, lines)
+			}
+
+			runtime := goja.New()
+			exports := runtime.NewObject()
+			runtime.Set("exports", exports)
+			runtime.Set("require", func(id string) *goja.Object {
+				module := runtime.NewObject()
+				module.Set("__esModule", true)
+				module.Set("a", 1)
+				module.Set("b", 2)
+				module.Set("default", module)
+				return module
+			})
+
+			if _, err := runtime.RunString(script); err != nil {
+				t.Fatalf("%s\n%s", err.Error(), script)
+			}
+
+			for name, value := range test.exports {
+				if exported := exports.Get(name); (exported == nil) || (exported.Export() != value) {
+					t.Errorf("export %q: expected %v, got %v", name, value, exported)
+				}
+			}
+		})
+	}
+}
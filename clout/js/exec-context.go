@@ -13,18 +13,23 @@ import (
 //
 
 type ExecContext struct {
-	Clout      *cloutpkg.Clout
-	Problems   *problemspkg.Problems
-	URLContext *exturl.Context
-	History    bool
-	Format     string
-	Strict     bool
-	Pretty     bool
-	Base64     bool
+	Clout        *cloutpkg.Clout
+	Problems     *problemspkg.Problems
+	URLContext   *exturl.Context
+	History      bool
+	Format       string
+	Strict       bool
+	Pretty       bool
+	Base64       bool
+	Trace        *Trace
+	ProgramCache *ProgramCache
 }
 
 func (self *ExecContext) NewEnvironment(scriptletName string, arguments map[string]string) *Environment {
-	return NewEnvironment(scriptletName, log, arguments, true, self.Format, self.Strict, self.Pretty, self.Base64, "", self.URLContext)
+	environment := NewEnvironment(scriptletName, log, arguments, true, self.Format, self.Strict, self.Pretty, self.Base64, "", self.URLContext)
+	environment.Trace = self.Trace
+	environment.ProgramCache = self.ProgramCache
+	return environment
 }
 
 func (self *ExecContext) Exec(scriptletName string, arguments map[string]string) *goja.Object {
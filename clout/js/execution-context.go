@@ -1,5 +1,12 @@
 package js
 
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dop251/goja"
+)
+
 //
 // ExecutionContext
 //
@@ -22,8 +29,34 @@ func (self *CloutContext) NewExecutionContext(site any, source any, target any)
 
 func (self *ExecutionContext) Call(scriptletName string, functionName string, arguments ...any) (any, error) {
 	if exports, err := self.CloutContext.JSContext.Environment.Require(scriptletName, true, nil); err == nil {
-		return self.CloutContext.JSContext.Environment.GetAndCall(exports, functionName, self, arguments...)
+		if result, err := self.CloutContext.JSContext.Environment.GetAndCall(exports, functionName, self, arguments...); err == nil {
+			return settle(result)
+		} else {
+			return nil, err
+		}
 	} else {
 		return nil, err
 	}
 }
+
+// Async functions return a promise. Because scriptlets are called synchronously from
+// within the JavaScript runtime, pending jobs will not run before we need the result,
+// so the promise must already be settled when the function returns (i.e. the function
+// must not "await").
+func settle(result any) (any, error) {
+	if promise, ok := result.(*goja.Promise); ok {
+		switch promise.State() {
+		case goja.PromiseStateFulfilled:
+			return promise.Result().Export(), nil
+		case goja.PromiseStateRejected:
+			reason := promise.Result()
+			if err, ok := reason.Export().(error); ok {
+				return nil, err
+			}
+			return nil, errors.New(reason.String())
+		default:
+			return nil, fmt.Errorf("async scriptlet function did not settle (did it \"await\"?)")
+		}
+	}
+	return result, nil
+}
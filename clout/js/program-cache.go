@@ -0,0 +1,92 @@
+package js
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sync/atomic"
+
+	"github.com/tliron/commonjs-goja"
+	"github.com/tliron/exturl"
+)
+
+// Compiled goja programs cannot be serialized, so this cache lives in memory only and
+// does not help separate processes. It is useful for tools that create several
+// environments for the same Clout (resolve, coerce, outputs) or that process many Clouts.
+// There is no eviction, so it should not outlive such a unit of work.
+
+//
+// ProgramCache
+//
+
+// Compiled scriptlet programs keyed by a hash of their source code. Can be shared between
+// Environment instances (and thus between Clouts) in the same process, including
+// concurrently.
+type ProgramCache struct {
+	// commonjs-goja shares its program cache with child environments, so we keep an
+	// environment here just to be the parent
+	environment *commonjs.Environment
+
+	compilations atomic.Int64
+}
+
+func NewProgramCache() *ProgramCache {
+	return &ProgramCache{
+		environment: commonjs.NewEnvironment(nil),
+	}
+}
+
+func (self *ProgramCache) NewEnvironment(urlContext *exturl.Context) *commonjs.Environment {
+	environment := self.environment.NewChild()
+	environment.URLContext = urlContext
+	return environment
+}
+
+// The number of programs compiled so far, i.e. cache misses.
+func (self *ProgramCache) Compilations() int64 {
+	return self.compilations.Load()
+}
+
+// Removes all cached programs.
+func (self *ProgramCache) Clear() {
+	self.environment.ClearCache()
+}
+
+//
+// ScriptletURL
+//
+
+// An internal URL for a scriptlet. Its key is a hash of the scriptlet's name and source
+// code so that it can be used to key a [ProgramCache]. (The name is included because
+// relative imports are resolved against it.)
+type ScriptletURL struct {
+	*exturl.InternalURL
+
+	hash string
+}
+
+// The extension names are included in the hash because they are parameters of the
+// compiled module wrapper.
+func NewScriptletURL(urlContext *exturl.Context, name string, scriptlet string, extensions []commonjs.Extension) *ScriptletURL {
+	hash := sha256.New()
+	io.WriteString(hash, name)
+	hash.Write([]byte{0})
+	for _, extension := range extensions {
+		io.WriteString(hash, extension.Name)
+		hash.Write([]byte{0})
+	}
+	io.WriteString(hash, scriptlet)
+
+	url := urlContext.NewInternalURL(name)
+	url.SetContent(scriptlet)
+
+	return &ScriptletURL{
+		InternalURL: url,
+		hash:        hex.EncodeToString(hash.Sum(nil)),
+	}
+}
+
+// ([exturl.URL] interface)
+func (self *ScriptletURL) Key() string {
+	return "scriptlet:" + self.hash
+}
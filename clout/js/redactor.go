@@ -0,0 +1,302 @@
+package js
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/tliron/go-ard"
+	cloutpkg "github.com/tliron/go-puccini/clout"
+)
+
+const RedactedValue = "******"
+
+//
+// Redactor
+//
+
+// Masks the values of hidden inputs (marked with "hidden" value metadata) in output.
+//
+// Masked are the hidden inputs themselves, values derived from them via function calls (e.g. a
+// concatenation that includes a hidden input), and any other value that is equal to a hidden
+// input's value. Only whole values are masked, not substrings.
+//
+// Coerced values are replaced entirely. In values that have not been coerced only the
+// "$primitive" is replaced, so that the structure of the Clout is kept. Function calls are left as
+// is (their arguments refer to inputs by name), though their results will be masked once they are
+// coerced.
+type Redactor struct {
+	clout   *cloutpkg.Clout
+	hidden  []string            // input names
+	derived map[string]struct{} // paths of values derived from hidden inputs
+}
+
+// Must be called before coercion, which removes the value metadata and the function calls.
+func NewRedactor(clout *cloutpkg.Clout) *Redactor {
+	self := Redactor{clout: clout}
+	if inputs, ok := ard.With(clout.Properties).Get("tosca", "inputs").StringMap(); ok {
+		for name, input := range inputs {
+			if hidden, _ := ard.With(input).Get("$meta", "metadata", "hidden").String(); hidden == "true" {
+				self.hidden = append(self.hidden, name)
+			}
+		}
+	}
+
+	if len(self.hidden) > 0 {
+		self.derived = make(map[string]struct{})
+		inputsPath := appendRedactorPath(appendRedactorPath("clout", "tosca"), "inputs")
+		for _, name := range self.hidden {
+			self.derived[appendRedactorPath(inputsPath, name)] = struct{}{}
+		}
+
+		self.findDerived(clout.Properties, "clout")
+		for id, vertex := range clout.Vertexes {
+			path := appendRedactorPath("vertex", id)
+			self.findDerived(vertex.Properties, path)
+			for index, edge := range vertex.EdgesOut {
+				self.findDerived(edge.Properties, appendRedactorPath(appendRedactorPath(path, "edge"), strconv.Itoa(index)))
+			}
+		}
+	}
+
+	return &self
+}
+
+// Returns a masked copy of the data, which can also be a Clout. The input values are taken from
+// the Clout at the time of the call, so it should be called after coercion and execution.
+//
+// Values derived from hidden inputs can only be recognized in a Clout.
+func (self *Redactor) Redact(data any) any {
+	if (self == nil) || (len(self.hidden) == 0) {
+		return data
+	}
+
+	switch data_ := data.(type) {
+	case *CloutAPI:
+		return self.RedactClout(data_.Clout)
+	case *cloutpkg.Clout:
+		return self.RedactClout(data_)
+	default:
+		return self.redact(ard.Copy(data), "", self.getValues())
+	}
+}
+
+// Returns a masked copy of the Clout. See [Redactor.Redact].
+func (self *Redactor) RedactClout(clout *cloutpkg.Clout) *cloutpkg.Clout {
+	if (self == nil) || (len(self.hidden) == 0) {
+		return clout
+	}
+
+	values := self.getValues()
+	clout = clout.CopyAsIs()
+
+	self.redact(clout.Properties, "clout", values)
+	for id, vertex := range clout.Vertexes {
+		path := appendRedactorPath("vertex", id)
+		self.redact(vertex.Properties, path, values)
+		for index, edge := range vertex.EdgesOut {
+			self.redact(edge.Properties, appendRedactorPath(appendRedactorPath(path, "edge"), strconv.Itoa(index)), values)
+		}
+	}
+
+	return clout
+}
+
+func (self *Redactor) getValues() []string {
+	var values []string
+	if inputs, ok := ard.With(self.clout.Properties).Get("tosca", "inputs").StringMap(); ok {
+		for _, name := range self.hidden {
+			input := inputs[name]
+			if map_, ok := input.(ard.StringMap); ok {
+				input = map_["$primitive"]
+			}
+			if value, ok := input.(string); ok && (value != "") {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// Records the paths of function calls that depend on hidden inputs. The paths follow the
+// structure that the values will have once they are coerced.
+func (self *Redactor) findDerived(value ard.Value, path string) {
+	switch value_ := value.(type) {
+	case ard.StringMap:
+		if isCoercible(value_) {
+			if _, ok := value_["$functionCall"]; ok {
+				if self.dependsOnHidden(value_) {
+					self.derived[path] = struct{}{}
+				}
+			} else {
+				forEachCoercibleEntry(value_, path, self.findDerived)
+			}
+		} else {
+			for key, entry := range value_ {
+				self.findDerived(entry, appendRedactorPath(path, key))
+			}
+		}
+
+	case ard.Map:
+		for key, entry := range value_ {
+			if key_, ok := key.(string); ok {
+				self.findDerived(entry, appendRedactorPath(path, key_))
+			}
+		}
+
+	case ard.List:
+		for index, entry := range value_ {
+			self.findDerived(entry, appendRedactorPath(path, strconv.Itoa(index)))
+		}
+	}
+}
+
+// True if the coercible value calls a function that gets a hidden input, either directly or via
+// its arguments
+func (self *Redactor) dependsOnHidden(value ard.Value) bool {
+	map_, ok := value.(ard.StringMap)
+	if !ok {
+		return false
+	}
+
+	if functionCall, ok := map_["$functionCall"].(ard.StringMap); ok {
+		arguments, _ := functionCall["arguments"].(ard.List)
+
+		if name, ok := functionCall["name"].(string); ok && isInputFunction(name) && (len(arguments) > 0) {
+			// The first argument is the input name (or a path starting with it)
+			argument := arguments[0]
+			if list, ok := ard.With(argument).Get("$list").List(); ok && (len(list) > 0) {
+				argument = list[0]
+			}
+			if input, ok := ard.With(argument).Get("$primitive").String(); ok && slices.Contains(self.hidden, input) {
+				return true
+			}
+		}
+
+		for _, argument := range arguments {
+			if self.dependsOnHidden(argument) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	dependsOnHidden := false
+	forEachCoercibleEntry(map_, "", func(entry ard.Value, path string) {
+		if !dependsOnHidden && self.dependsOnHidden(entry) {
+			dependsOnHidden = true
+		}
+	})
+	return dependsOnHidden
+}
+
+// Modifies the value in place
+func (self *Redactor) redact(value ard.Value, path string, values []string) ard.Value {
+	_, derived := self.derived[path]
+
+	switch value_ := value.(type) {
+	case ard.StringMap:
+		if isCoercible(value_) {
+			if primitive, ok := value_["$primitive"]; ok {
+				if derived || isRedactedValue(primitive, values) {
+					value_["$primitive"] = RedactedValue
+				}
+			} else if _, ok := value_["$functionCall"]; !ok {
+				forEachCoercibleEntry(value_, path, func(entry ard.Value, path string) {
+					self.redact(entry, path, values)
+				})
+			}
+			return value
+		}
+
+		if derived {
+			return RedactedValue
+		}
+		for key, entry := range value_ {
+			value_[key] = self.redact(entry, appendRedactorPath(path, key), values)
+		}
+
+	case ard.Map:
+		if derived {
+			return RedactedValue
+		}
+		for key, entry := range value_ {
+			if key_, ok := key.(string); ok {
+				value_[key] = self.redact(entry, appendRedactorPath(path, key_), values)
+			} else {
+				value_[key] = self.redact(entry, "", values)
+			}
+		}
+
+	case ard.List:
+		if derived {
+			return RedactedValue
+		}
+		for index, entry := range value_ {
+			value_[index] = self.redact(entry, appendRedactorPath(path, strconv.Itoa(index)), values)
+		}
+
+	default:
+		if derived || isRedactedValue(value, values) {
+			return RedactedValue
+		}
+	}
+
+	return value
+}
+
+// Utils
+
+// Functions that get the value of an input (TOSCA, HOT, and CloudFormation)
+// (The prefix is parsing.MetadataFunctionPrefix, which we cannot import here)
+var inputFunctions = []string{
+	"tosca.function.get_input",
+	"tosca.function.get_param",
+	"tosca.function.ref",
+}
+
+func isInputFunction(name string) bool {
+	return slices.Contains(inputFunctions, name)
+}
+
+func isRedactedValue(value ard.Value, values []string) bool {
+	if value_, ok := value.(string); ok {
+		return slices.Contains(values, value_)
+	}
+	return false
+}
+
+func isCoercible(value ard.Value) bool {
+	if map_, ok := value.(ard.StringMap); ok {
+		for _, key := range []string{"$primitive", "$list", "$map", "$functionCall"} {
+			if _, ok := map_[key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Calls the function for the elements of "$list" and the entries of "$map" (with string keys),
+// with the paths that they will have once coerced
+func forEachCoercibleEntry(value ard.StringMap, path string, f func(ard.Value, string)) {
+	if list, ok := value["$list"].(ard.List); ok {
+		for index, entry := range list {
+			f(entry, appendRedactorPath(path, strconv.Itoa(index)))
+		}
+	} else if list, ok := value["$map"].(ard.List); ok {
+		for _, entry := range list {
+			if key, ok := ard.With(entry).Get("$key", "$primitive").String(); ok {
+				f(entry, appendRedactorPath(path, key))
+			}
+		}
+	}
+}
+
+// An empty path never matches
+func appendRedactorPath(path string, segment string) string {
+	if path == "" {
+		return ""
+	}
+	return path + "\x00" + segment
+}
@@ -0,0 +1,136 @@
+package js
+
+import (
+	"testing"
+
+	"github.com/tliron/go-ard"
+	cloutpkg "github.com/tliron/go-puccini/clout"
+)
+
+func TestRedactorUncoerced(t *testing.T) {
+	clout := newTestRedactorClout()
+	redacted := NewRedactor(clout).RedactClout(clout)
+
+	inputs := redacted.Properties["tosca"].(ard.StringMap)["inputs"].(ard.StringMap)
+	assertTestRedacted(t, "hidden input", inputs["password"].(ard.StringMap)["$primitive"], true)
+	assertTestRedacted(t, "hidden integer input", inputs["port"].(ard.StringMap)["$primitive"], true)
+	assertTestRedacted(t, "input", inputs["user"].(ard.StringMap)["$primitive"], false)
+
+	properties := redacted.Vertexes["server"].Properties["properties"].(ard.StringMap)
+	assertTestRedacted(t, "equal value", properties["literal"].(ard.StringMap)["$primitive"], true)
+	assertTestRedacted(t, "substring", properties["substring"].(ard.StringMap)["$primitive"], false)
+	assertTestRedacted(t, "equal value in list", properties["list"].(ard.StringMap)["$list"].(ard.List)[0].(ard.StringMap)["$primitive"], true)
+
+	// Function calls are kept for coercion
+	if _, ok := properties["derived"].(ard.StringMap)["$functionCall"]; !ok {
+		t.Errorf("function call not kept")
+	}
+
+	// The original is not modified
+	if inputs := clout.Properties["tosca"].(ard.StringMap)["inputs"].(ard.StringMap); inputs["password"].(ard.StringMap)["$primitive"] != "secret" {
+		t.Errorf("original modified")
+	}
+}
+
+func TestRedactorCoerced(t *testing.T) {
+	clout := newTestRedactorClout()
+	redactor := NewRedactor(clout)
+
+	// Coerce by hand
+	clout.Properties["tosca"] = ard.StringMap{
+		"inputs": ard.StringMap{"password": "secret", "port": 8080, "user": "admin"},
+	}
+	clout.Vertexes["server"].Properties["properties"] = ard.StringMap{
+		"derived":   "user:secret",
+		"unrelated": "user:admin",
+		"literal":   "secret",
+		"substring": "not a secret",
+		"list":      ard.List{"secret", "public"},
+	}
+
+	redacted := redactor.RedactClout(clout)
+
+	inputs := redacted.Properties["tosca"].(ard.StringMap)["inputs"].(ard.StringMap)
+	assertTestRedacted(t, "hidden input", inputs["password"], true)
+	assertTestRedacted(t, "hidden integer input", inputs["port"], true)
+	assertTestRedacted(t, "input", inputs["user"], false)
+
+	properties := redacted.Vertexes["server"].Properties["properties"].(ard.StringMap)
+	assertTestRedacted(t, "derived value", properties["derived"], true)
+	assertTestRedacted(t, "function call of another input", properties["unrelated"], false)
+	assertTestRedacted(t, "equal value", properties["literal"], true)
+	assertTestRedacted(t, "substring", properties["substring"], false)
+	assertTestRedacted(t, "equal value in list", properties["list"].(ard.List)[0], true)
+	assertTestRedacted(t, "list", properties["list"].(ard.List)[1], false)
+
+	// Arbitrary data
+	data := redactor.Redact(ard.StringMap{"a": "secret", "b": "not a secret"}).(ard.StringMap)
+	assertTestRedacted(t, "equal value in data", data["a"], true)
+	assertTestRedacted(t, "substring in data", data["b"], false)
+}
+
+func TestRedactorNoHidden(t *testing.T) {
+	clout := cloutpkg.NewClout()
+	clout.Properties["tosca"] = ard.StringMap{
+		"inputs": ard.StringMap{"password": ard.StringMap{"$primitive": "secret"}},
+	}
+
+	if NewRedactor(clout).RedactClout(clout) != clout {
+		t.Errorf("copied without hidden inputs")
+	}
+}
+
+// Utils
+
+func newTestRedactorClout() *cloutpkg.Clout {
+	clout := cloutpkg.NewClout()
+
+	hidden := ard.StringMap{"metadata": ard.StringMap{"hidden": "true"}}
+	clout.Properties["tosca"] = ard.StringMap{
+		"inputs": ard.StringMap{
+			"password": ard.StringMap{"$meta": hidden, "$primitive": "secret"},
+			"port":     ard.StringMap{"$meta": hidden, "$primitive": 8080},
+			"user":     ard.StringMap{"$primitive": "admin"},
+		},
+	}
+
+	vertex := clout.NewVertex("server")
+	vertex.Properties["properties"] = ard.StringMap{
+		"derived":   newTestConcat(newTestGetInput("password")),
+		"unrelated": newTestConcat(newTestGetInput("user")),
+		"literal":   ard.StringMap{"$primitive": "secret"},
+		"substring": ard.StringMap{"$primitive": "not a secret"},
+		"list": ard.StringMap{"$list": ard.List{
+			ard.StringMap{"$primitive": "secret"},
+			ard.StringMap{"$primitive": "public"},
+		}},
+	}
+
+	return clout
+}
+
+func newTestGetInput(name string) ard.StringMap {
+	return ard.StringMap{"$functionCall": ard.StringMap{
+		"name":      "tosca.function.get_input",
+		"arguments": ard.List{ard.StringMap{"$primitive": name}},
+	}}
+}
+
+func newTestConcat(argument ard.StringMap) ard.StringMap {
+	return ard.StringMap{"$functionCall": ard.StringMap{
+		"name":      "tosca.function.concat",
+		"arguments": ard.List{ard.StringMap{"$primitive": "user:"}, argument},
+	}}
+}
+
+func assertTestRedacted(t *testing.T, name string, value ard.Value, redacted bool) {
+	t.Helper()
+
+	if (value == RedactedValue) != redacted {
+		if redacted {
+			t.Errorf("%s: not masked: %v", name, value)
+		} else {
+			t.Errorf("%s: masked", name)
+		}
+	}
+}
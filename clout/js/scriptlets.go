@@ -28,6 +28,35 @@ func GetScriptlet(name string, clout *cloutpkg.Clout) (string, error) {
 	return scriptlet, nil
 }
 
+// Resolves a relative scriptlet name ("./name" or "../name") against the name of
+// the requiring scriptlet. Slashes in relative names are treated as dots. Other
+// names are returned as is.
+func ResolveScriptletName(name string, baseName string) string {
+	if !strings.HasPrefix(name, "./") && !strings.HasPrefix(name, "../") {
+		return name
+	}
+
+	// Start from the section of the requiring scriptlet
+	path := strings.Split(baseName, ".")
+	if len(path) > 0 {
+		path = path[:len(path)-1]
+	}
+
+	for _, segment := range strings.Split(name, "/") {
+		switch segment {
+		case ".", "":
+		case "..":
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		default:
+			path = append(path, strings.Split(strings.TrimSuffix(segment, ".js"), ".")...)
+		}
+	}
+
+	return strings.Join(path, ".")
+}
+
 func SetScriptlet(name string, scriptlet string, clout *cloutpkg.Clout) error {
 	metadata, err := GetScriptletsMetadata(clout)
 	if err != nil {
@@ -0,0 +1,205 @@
+package js
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-kutil/terminal"
+)
+
+//
+// Trace
+//
+
+type Trace struct {
+	Records  []*TraceRecord `json:"records" yaml:"records"`
+	Debugger *Debugger      `json:"-" yaml:"-"`
+
+	stack []*TraceRecord
+	lock  sync.Mutex
+}
+
+func NewTrace() *Trace {
+	return &Trace{
+		Records: make([]*TraceRecord, 0),
+	}
+}
+
+// Note that a nil trace is valid and will simply do nothing, so callers do not
+// have to check if tracing is enabled
+func (self *Trace) Begin(functionCall *FunctionCall, functionName string) *TraceRecord {
+	if self == nil {
+		return nil
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	record := &TraceRecord{
+		Scriptlet: functionCall.Name,
+		Function:  functionName,
+		Path:      functionCall.Path,
+		URL:       functionCall.URL,
+		Row:       functionCall.Row,
+		Column:    functionCall.Column,
+		Children:  make([]*TraceRecord, 0),
+	}
+
+	if length := len(self.stack); length > 0 {
+		parent := self.stack[length-1]
+		record.Parent = parent
+		parent.Children = append(parent.Children, record)
+	} else {
+		self.Records = append(self.Records, record)
+	}
+
+	self.stack = append(self.stack, record)
+
+	return record
+}
+
+// Called after the arguments have been coerced but before the scriptlet is called.
+// Returns an error if the debugger asked to abort.
+func (self *Trace) Enter(record *TraceRecord, arguments []ard.Value) error {
+	if (self == nil) || (record == nil) {
+		return nil
+	}
+
+	self.lock.Lock()
+	record.Arguments = arguments
+	debugger := self.Debugger
+	self.lock.Unlock()
+
+	if debugger != nil {
+		return debugger.Break(self, record)
+	}
+
+	return nil
+}
+
+func (self *Trace) End(record *TraceRecord, result ard.Value, err error) {
+	if (self == nil) || (record == nil) {
+		return
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.Result = result
+	}
+
+	// Pop (records are always ended in reverse order of beginning)
+	for index := len(self.stack) - 1; index >= 0; index-- {
+		if self.stack[index] == record {
+			self.stack = self.stack[:index]
+			break
+		}
+	}
+}
+
+func (self *Trace) Write(writer io.Writer, stylist *terminal.Stylist) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if stylist == nil {
+		stylist = terminal.NewStylist(false)
+	}
+
+	writeTraceRecords(writer, stylist, self.Records, terminal.TreePrefix{})
+}
+
+//
+// TraceRecord
+//
+
+type TraceRecord struct {
+	Scriptlet string         `json:"scriptlet" yaml:"scriptlet"`
+	Function  string         `json:"function" yaml:"function"`
+	Arguments []ard.Value    `json:"arguments" yaml:"arguments"`
+	Result    ard.Value      `json:"result,omitempty" yaml:"result,omitempty"`
+	Error     string         `json:"error,omitempty" yaml:"error,omitempty"`
+	Path      string         `json:"path,omitempty" yaml:"path,omitempty"`
+	URL       string         `json:"url,omitempty" yaml:"url,omitempty"`
+	Row       int            `json:"row" yaml:"row"`
+	Column    int            `json:"column" yaml:"column"`
+	Children  []*TraceRecord `json:"children,omitempty" yaml:"children,omitempty"`
+
+	Parent *TraceRecord `json:"-" yaml:"-"`
+}
+
+func (self *TraceRecord) Signature() string {
+	s := make([]string, len(self.Arguments))
+	for index, argument := range self.Arguments {
+		s[index] = encodeArgument(argument)
+	}
+	return fmt.Sprintf("%s(%s)", self.Scriptlet, strings.Join(s, ","))
+}
+
+func (self *TraceRecord) Location() string {
+	if self.URL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s@%d,%d", self.URL, self.Row, self.Column)
+}
+
+func (self *TraceRecord) Write(writer io.Writer, stylist *terminal.Stylist) {
+	if stylist == nil {
+		stylist = terminal.NewStylist(false)
+	}
+
+	message := stylist.Name(self.Signature())
+	if self.Function != "evaluate" {
+		message = fmt.Sprintf("%s %s", stylist.Heading(self.Function), message)
+	}
+
+	if self.Error != "" {
+		message += fmt.Sprintf(" ✗ %s", stylist.Error(self.Error))
+	} else if self.Result != nil {
+		message += fmt.Sprintf(" → %s", stylist.Value(encodeArgument(self.Result)))
+	}
+
+	if self.Path != "" {
+		message += fmt.Sprintf(" %s", stylist.Path(self.Path))
+	}
+
+	if location := self.Location(); location != "" {
+		message += fmt.Sprintf(" %s", location)
+	}
+
+	fmt.Fprintf(writer, "%s\n", message)
+}
+
+// Utils
+
+func writeTraceRecords(writer io.Writer, stylist *terminal.Stylist, records []*TraceRecord, treePrefix terminal.TreePrefix) {
+	last := len(records) - 1
+	for index, record := range records {
+		isLast := index == last
+		writeTreePrefix(writer, treePrefix, isLast)
+		record.Write(writer, stylist)
+		writeTraceRecords(writer, stylist, record.Children, append(treePrefix, isLast))
+	}
+}
+
+// Like [terminal.TreePrefix.Print] but to any writer
+func writeTreePrefix(writer io.Writer, treePrefix terminal.TreePrefix, last bool) {
+	for _, element := range treePrefix {
+		if element {
+			io.WriteString(writer, "  ")
+		} else {
+			io.WriteString(writer, "│ ")
+		}
+	}
+
+	if last {
+		io.WriteString(writer, "└─")
+	} else {
+		io.WriteString(writer, "├─")
+	}
+}
@@ -103,6 +103,8 @@ func (self *TranscribeAPI) Output(data any, path string, dontOverwrite bool) err
 		}
 	}
 
+	data = self.context.Redactor.Redact(data)
+
 	transcriber := transcribe.Transcriber{
 		File:        output,
 		Writer:      self.Stdout,
@@ -0,0 +1,530 @@
+package js
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dop251/goja"
+	"github.com/tliron/commonjs-goja/api"
+	"github.com/tliron/go-kutil/problems"
+	"github.com/tliron/go-kutil/util"
+)
+
+// Go packages from which we will generate TypeScript interfaces. Types from other
+// packages will be declared as "any".
+var TypeScriptPackages = []string{
+	"github.com/tliron/go-puccini/",
+	"github.com/tliron/commonjs-goja/api",
+	"github.com/tliron/go-kutil/problems",
+	"github.com/tliron/commonlog",
+}
+
+// The global objects injected into every scriptlet by [Environment.NewJsEnvironment].
+// Note that "problems" is only available when executing via [ExecContext].
+var ScriptletGlobals = []ScriptletGlobal{
+	{"clout", reflect.TypeFor[*CloutAPI](), false},
+	{"env", reflect.TypeFor[*api.Env](), false},
+	{"console", reflect.TypeFor[*api.Console](), false},
+	{"util", reflect.TypeFor[*api.Util](), false},
+	{"transcribe", reflect.TypeFor[*TranscribeAPI](), false},
+	{"ard", reflect.TypeFor[api.ARD](), false},
+	{"os", reflect.TypeFor[api.OS](), false},
+	{"bind", reflect.TypeFor[func(id string, exportName string) (any, error)](), false},
+	{"problems", reflect.TypeFor[*problems.Problems](), true},
+}
+
+// Go types that are used to run scriptlets but are not visible to them. Fields and methods that
+// refer to them are not declared.
+var TypeScriptInternalTypes = []reflect.Type{
+	reflect.TypeFor[Environment](),
+	reflect.TypeFor[CloutContext](),
+	reflect.TypeFor[ExecutionContext](),
+	reflect.TypeFor[ProgramCache](),
+	reflect.TypeFor[Debugger](),
+	reflect.TypeFor[Trace](),
+	reflect.TypeFor[TraceRecord](),
+	reflect.TypeFor[Redactor](),
+}
+
+//
+// ScriptletGlobal
+//
+
+type ScriptletGlobal struct {
+	Name     string
+	Type     reflect.Type
+	Optional bool
+}
+
+//
+// TypeScript
+//
+
+// Generates TypeScript declarations (".d.ts") via reflection on Go types.
+//
+// Go objects are exposed to JavaScript by goja using dromedary-case names for both
+// fields and methods. Plain data (e.g. the Clout's coercible value notation), on the
+// other hand, uses the JSON field names. Use [TypeScript.AddObject] and
+// [TypeScript.AddData] respectively.
+type TypeScript struct {
+	interfaces map[string]string
+	names      map[typeScriptKey]string
+	modules    map[string]string
+	globals    []string
+	aliases    []string
+}
+
+func NewTypeScript() *TypeScript {
+	return &TypeScript{
+		interfaces: make(map[string]string),
+		names:      make(map[typeScriptKey]string),
+		modules:    make(map[string]string),
+	}
+}
+
+// Adds all of [ScriptletGlobals].
+func (self *TypeScript) AddScriptletGlobals() {
+	for _, global := range ScriptletGlobals {
+		self.AddGlobal(global.Name, global.Type, global.Optional)
+	}
+}
+
+func (self *TypeScript) AddGlobal(name string, type_ reflect.Type, optional bool) {
+	typeName := self.typeOf(type_, false)
+	if optional {
+		typeName += " | undefined"
+	}
+	self.globals = append(self.globals, fmt.Sprintf("declare const %s: %s;", name, typeName))
+}
+
+// Returns the TypeScript interface name.
+func (self *TypeScript) AddObject(name string, type_ reflect.Type) string {
+	return self.addInterface(name, type_, false)
+}
+
+// Returns the TypeScript interface name.
+func (self *TypeScript) AddData(name string, type_ reflect.Type) string {
+	return self.addInterface(name, type_, true)
+}
+
+func (self *TypeScript) AddAlias(name string, definition string) {
+	self.aliases = append(self.aliases, fmt.Sprintf("type %s = %s;", name, definition))
+}
+
+var exportsRe = regexp.MustCompile(`(?m)^exports\.([A-Za-z_$][\w$]*)\s*=\s*(function\b[^(]*\(([^)]*)\))?`)
+
+// Declares a module for a scriptlet according to its "exports.name = ..." statements.
+// Does nothing if the scriptlet has no exports.
+func (self *TypeScript) AddScriptletModule(name string, scriptlet string) {
+	matches := exportsRe.FindAllStringSubmatch(scriptlet, -1)
+	if len(matches) == 0 {
+		return
+	}
+
+	var writer strings.Builder
+	exported := make(map[string]struct{})
+	for _, match := range matches {
+		exportName := match[1]
+		if _, ok := exported[exportName]; ok {
+			continue
+		}
+		exported[exportName] = struct{}{}
+
+		if match[2] != "" {
+			var parameters []string
+			for _, parameter := range splitParameters(match[3]) {
+				// Remove default values
+				if index := strings.Index(parameter, "="); index != -1 {
+					parameter = parameter[:index]
+				}
+				if parameter = strings.TrimSpace(parameter); parameter != "" {
+					parameters = append(parameters, parameter+"?: any")
+				}
+			}
+			fmt.Fprintf(&writer, "  export function %s(%s): any;\n", exportName, strings.Join(parameters, ", "))
+		} else {
+			fmt.Fprintf(&writer, "  export const %s: any;\n", exportName)
+		}
+	}
+
+	self.modules[name] = writer.String()
+}
+
+func (self *TypeScript) Write(writer io.Writer) error {
+	var builder strings.Builder
+
+	builder.WriteString("// Generated by Puccini from Go types via reflection. Do not edit.\n")
+
+	for _, name := range sortedKeys(self.interfaces) {
+		fmt.Fprintf(&builder, "\n%s\n", self.interfaces[name])
+	}
+
+	if len(self.aliases) > 0 {
+		builder.WriteString("\n")
+		for _, alias := range self.aliases {
+			fmt.Fprintf(&builder, "%s\n", alias)
+		}
+	}
+
+	if len(self.globals) > 0 {
+		builder.WriteString("\n")
+		for _, global := range self.globals {
+			fmt.Fprintf(&builder, "%s\n", global)
+		}
+	}
+
+	moduleNames := sortedKeys(self.modules)
+	for _, name := range moduleNames {
+		fmt.Fprintf(&builder, "\ndeclare module '%s' {\n%s}\n", name, self.modules[name])
+	}
+
+	builder.WriteString("\n")
+	for _, name := range moduleNames {
+		fmt.Fprintf(&builder, "declare function require(id: '%s'): typeof import('%s');\n", name, name)
+	}
+	builder.WriteString("declare function require(id: string): any;\n")
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+//
+// typeScriptKey
+//
+
+type typeScriptKey struct {
+	type_ reflect.Type
+	data  bool
+}
+
+// We must not merge with or shadow these standard TypeScript declarations
+var typeScriptReservedNames = map[string]struct{}{
+	"Array": {}, "Boolean": {}, "Console": {}, "Date": {}, "Error": {}, "Function": {}, "Map": {},
+	"Number": {}, "Object": {}, "Promise": {}, "Set": {}, "String": {}, "Symbol": {},
+}
+
+var errorType = reflect.TypeFor[error]()
+var gojaFunctionCallType = reflect.TypeFor[goja.FunctionCall]()
+
+func (self *TypeScript) addInterface(name string, type_ reflect.Type, data bool) string {
+	for type_.Kind() == reflect.Pointer {
+		type_ = type_.Elem()
+	}
+
+	key := typeScriptKey{type_, data}
+	if name_, ok := self.names[key]; ok {
+		return name_
+	}
+
+	if name == "" {
+		name = type_.Name()
+		_, exists := self.interfaces[name]
+		if _, reserved := typeScriptReservedNames[name]; exists || reserved {
+			// Disambiguate with package name
+			path := strings.Split(type_.PkgPath(), "/")
+			prefix := path[len(path)-1]
+			name = strings.ToUpper(prefix[:1]) + prefix[1:] + name
+		}
+	}
+
+	// Register first to support recursive types
+	self.names[key] = name
+	self.interfaces[name] = ""
+
+	var writer strings.Builder
+
+	switch type_.Kind() {
+	case reflect.Slice, reflect.Array:
+		fmt.Fprintf(&writer, "interface %s extends Array<%s> {\n", name, self.typeOf(type_.Elem(), data))
+	case reflect.Map:
+		fmt.Fprintf(&writer, "interface %s {\n  [key: string]: %s;\n", name, self.typeOf(type_.Elem(), data))
+	default:
+		fmt.Fprintf(&writer, "interface %s {\n", name)
+	}
+
+	if type_.Kind() == reflect.Struct {
+		self.writeFields(&writer, type_, data)
+	}
+
+	if !data {
+		self.writeMethods(&writer, type_)
+	}
+
+	writer.WriteString("}")
+
+	self.interfaces[name] = writer.String()
+
+	return name
+}
+
+func (self *TypeScript) writeFields(writer *strings.Builder, type_ reflect.Type, data bool) {
+	self.writeFieldsExcept(writer, type_, data, make(map[string]struct{}))
+}
+
+// As in Go, fields of embedded structs are shadowed by shallower fields with the same name
+func (self *TypeScript) writeFieldsExcept(writer *strings.Builder, type_ reflect.Type, data bool, seen map[string]struct{}) {
+	var embedded []reflect.Type
+
+	for index := 0; index < type_.NumField(); index++ {
+		field := type_.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldType := field.Type
+
+		if field.Anonymous {
+			// Goja (and JSON) flatten embedded structs
+			embeddedType := fieldType
+			if embeddedType.Kind() == reflect.Pointer {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				embedded = append(embedded, embeddedType)
+				continue
+			}
+		}
+
+		name := util.ToDromedaryCase(field.Name)
+		optional := ""
+		if data {
+			if tag, ok := field.Tag.Lookup("json"); ok {
+				tags := strings.Split(tag, ",")
+				if tags[0] == "-" {
+					continue
+				} else if tags[0] != "" {
+					name = tags[0]
+				}
+				for _, tag_ := range tags[1:] {
+					if tag_ == "omitempty" {
+						optional = "?"
+					}
+				}
+			}
+		}
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		if isTypeScriptInternal(fieldType) {
+			continue
+		}
+
+		fmt.Fprintf(writer, "  %s%s: %s;\n", quotePropertyName(name), optional, self.typeOf(fieldType, data))
+	}
+
+	for _, embeddedType := range embedded {
+		self.writeFieldsExcept(writer, embeddedType, data, seen)
+	}
+}
+
+func (self *TypeScript) writeMethods(writer *strings.Builder, type_ reflect.Type) {
+	methodSet := type_
+	if type_.Kind() != reflect.Interface {
+		methodSet = reflect.PointerTo(type_)
+	}
+
+	for index := 0; index < methodSet.NumMethod(); index++ {
+		method := methodSet.Method(index)
+		if !method.IsExported() || isMarshalingMethod(method.Name) || isTypeScriptInternal(method.Type) {
+			continue
+		}
+
+		// For concrete types the first input is the receiver
+		skip := 1
+		if type_.Kind() == reflect.Interface {
+			skip = 0
+		}
+
+		parameters, result := self.signature(method.Type, skip)
+		fmt.Fprintf(writer, "  %s(%s): %s;\n", util.ToDromedaryCase(method.Name), parameters, result)
+	}
+}
+
+func (self *TypeScript) signature(type_ reflect.Type, skip int) (string, string) {
+	var parameters []string
+
+	inCount := type_.NumIn()
+	if (inCount-skip == 1) && (type_.In(skip) == gojaFunctionCallType) {
+		// Goja will pass the raw arguments
+		parameters = append(parameters, "...args: any[]")
+	} else {
+		for index := skip; index < inCount; index++ {
+			in := type_.In(index)
+			name := fmt.Sprintf("arg%d", index-skip)
+			if type_.IsVariadic() && (index == inCount-1) {
+				parameters = append(parameters, fmt.Sprintf("...%s: %s[]", name, self.typeOf(in.Elem(), false)))
+			} else {
+				parameters = append(parameters, fmt.Sprintf("%s: %s", name, self.typeOf(in, false)))
+			}
+		}
+	}
+
+	// Goja throws a returned error as an exception
+	var results []string
+	for index := 0; index < type_.NumOut(); index++ {
+		if out := type_.Out(index); out != errorType {
+			results = append(results, self.typeOf(out, false))
+		}
+	}
+
+	var result string
+	switch len(results) {
+	case 0:
+		result = "void"
+	case 1:
+		result = results[0]
+	default:
+		result = "[" + strings.Join(results, ", ") + "]"
+	}
+
+	return strings.Join(parameters, ", "), result
+}
+
+func (self *TypeScript) typeOf(type_ reflect.Type, data bool) string {
+	switch type_.Kind() {
+	case reflect.Bool:
+		return "boolean"
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number"
+
+	case reflect.String:
+		return "string"
+
+	case reflect.Pointer:
+		return self.typeOf(type_.Elem(), data)
+
+	case reflect.Interface:
+		if type_ == errorType {
+			return "Error"
+		}
+		if !data && (type_.NumMethod() > 0) && isTypeScriptPackage(type_) {
+			return self.addInterface("", type_, data)
+		}
+		return "any"
+
+	case reflect.Slice, reflect.Array:
+		if !data && (type_.Name() != "") && (reflect.PointerTo(type_).NumMethod() > 0) && isTypeScriptPackage(type_) {
+			return self.addInterface("", type_, data)
+		}
+		return self.typeOf(type_.Elem(), data) + "[]"
+
+	case reflect.Map:
+		return fmt.Sprintf("{ [key: string]: %s }", self.typeOf(type_.Elem(), data))
+
+	case reflect.Struct:
+		if isTypeScriptPackage(type_) {
+			return self.addInterface("", type_, data)
+		}
+		return "any"
+
+	case reflect.Func:
+		parameters, result := self.signature(type_, 0)
+		return fmt.Sprintf("((%s) => %s)", parameters, result)
+	}
+
+	return "any"
+}
+
+// Utils
+
+func isTypeScriptPackage(type_ reflect.Type) bool {
+	path := type_.PkgPath()
+	if path == "" {
+		return false
+	}
+	for _, prefix := range TypeScriptPackages {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Also true for pointers, containers, and functions that refer to internal types
+func isTypeScriptInternal(type_ reflect.Type) bool {
+	switch type_.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Chan:
+		return isTypeScriptInternal(type_.Elem())
+
+	case reflect.Map:
+		return isTypeScriptInternal(type_.Key()) || isTypeScriptInternal(type_.Elem())
+
+	case reflect.Func:
+		for index := 0; index < type_.NumIn(); index++ {
+			if isTypeScriptInternal(type_.In(index)) {
+				return true
+			}
+		}
+		for index := 0; index < type_.NumOut(); index++ {
+			if isTypeScriptInternal(type_.Out(index)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, internalType := range TypeScriptInternalTypes {
+		if type_ == internalType {
+			return true
+		}
+	}
+	return false
+}
+
+func isMarshalingMethod(name string) bool {
+	return strings.HasPrefix(name, "Marshal") || strings.HasPrefix(name, "Unmarshal") || (name == "ToARD")
+}
+
+// Splits on commas that are not within brackets or strings (e.g. in default values)
+func splitParameters(parameters string) []string {
+	var split []string
+	var depth int
+	var quote rune
+	start := 0
+	for index, rune_ := range parameters {
+		switch {
+		case quote != 0:
+			if rune_ == quote {
+				quote = 0
+			}
+		case (rune_ == '\'') || (rune_ == '"') || (rune_ == '`'):
+			quote = rune_
+		case (rune_ == '(') || (rune_ == '[') || (rune_ == '{'):
+			depth++
+		case (rune_ == ')') || (rune_ == ']') || (rune_ == '}'):
+			depth--
+		case (rune_ == ',') && (depth == 0):
+			split = append(split, parameters[start:index])
+			start = index + 1
+		}
+	}
+	return append(split, parameters[start:])
+}
+
+var identifierRe = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+func quotePropertyName(name string) string {
+	if identifierRe.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+func sortedKeys(map_ map[string]string) []string {
+	keys := make([]string, 0, len(map_))
+	for key := range map_ {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
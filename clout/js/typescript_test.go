@@ -0,0 +1,40 @@
+package js
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypeScript(t *testing.T) {
+	typeScript := NewTypeScript()
+	typeScript.AddScriptletGlobals()
+	typeScript.AddScriptletModule("tosca.lib.utils", "exports.join = function(a, b = ',') {};\nexports.separator = ',';\n")
+
+	var writer strings.Builder
+	if err := typeScript.Write(&writer); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	declarations := writer.String()
+
+	for _, declaration := range []string{
+		"declare const clout: CloutAPI;",
+		"declare const problems: Problems | undefined;",
+		"interface CloutAPI {",
+		"  vertexes: { [key: string]: Vertex };",
+		"  newKey(): string;",
+		"  export function join(a?: any, b?: any): any;",
+		"  export const separator: any;",
+		"declare function require(id: 'tosca.lib.utils'): typeof import('tosca.lib.utils');",
+	} {
+		if !strings.Contains(declarations, declaration) {
+			t.Errorf("missing declaration: %s", declaration)
+		}
+	}
+
+	// Types that scriptlets cannot reach
+	for _, internalType := range TypeScriptInternalTypes {
+		if name := internalType.Name(); strings.Contains(declarations, "interface "+name+" ") || strings.Contains(declarations, ": "+name+";") {
+			t.Errorf("internal type declared: %s", name)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tliron/go-ard"
+	cloutpkg "github.com/tliron/go-puccini/clout"
+)
+
+func GetToscaWorkflows(clout *cloutpkg.Clout) map[string]*cloutpkg.Vertex {
+	workflows := make(map[string]*cloutpkg.Vertex)
+	for _, vertex := range clout.Vertexes {
+		if IsTosca(vertex.Metadata, "Workflow") {
+			if name, ok := ard.With(vertex.Properties).Get("name").String(); ok {
+				workflows[name] = vertex
+			}
+		}
+	}
+	return workflows
+}
+
+//
+// WorkflowPlan
+//
+
+// An execution plan for a TOSCA declarative workflow. Steps are topologically sorted such
+// that every step appears after all the steps that can lead to it.
+type WorkflowPlan struct {
+	Name          string                      `json:"name" yaml:"name"`
+	Description   string                      `json:"description" yaml:"description"`
+	Inputs        ard.Value                   `json:"inputs" yaml:"inputs"`
+	Preconditions []*WorkflowPlanPrecondition `json:"preconditions" yaml:"preconditions"`
+	Steps         []*WorkflowPlanStep         `json:"steps" yaml:"steps"`
+}
+
+func NewWorkflowPlan(clout *cloutpkg.Clout, name string) (*WorkflowPlan, error) {
+	workflows := GetToscaWorkflows(clout)
+	if vertex, ok := workflows[name]; ok {
+		return newWorkflowPlan(vertex, make(map[string]bool))
+	} else {
+		return nil, fmt.Errorf("workflow not found: %s", name)
+	}
+}
+
+func newWorkflowPlan(vertex *cloutpkg.Vertex, inlining map[string]bool) (*WorkflowPlan, error) {
+	name, _ := ard.With(vertex.Properties).Get("name").String()
+	description, _ := ard.With(vertex.Properties).Get("description").NilMeansZero().String()
+
+	if inlining[name] {
+		return nil, fmt.Errorf("workflow inlines itself: %s", name)
+	}
+	inlining[name] = true
+	defer delete(inlining, name)
+
+	self := WorkflowPlan{
+		Name:          name,
+		Description:   description,
+		Inputs:        vertex.Properties["inputs"],
+		Preconditions: make([]*WorkflowPlanPrecondition, 0),
+		Steps:         make([]*WorkflowPlanStep, 0),
+	}
+
+	steps := make(map[*cloutpkg.Vertex]*WorkflowPlanStep)
+	var preconditionEdges cloutpkg.Edges
+
+	for _, edge := range vertex.EdgesOut {
+		if IsTosca(edge.Metadata, "WorkflowStep") {
+			if step, err := newWorkflowPlanStep(edge.Target, inlining); err == nil {
+				steps[edge.Target] = step
+			} else {
+				return nil, err
+			}
+		} else if IsTosca(edge.Metadata, "WorkflowPrecondition") {
+			preconditionEdges = append(preconditionEdges, edge)
+		}
+	}
+
+	sortEdgesBySequence(preconditionEdges)
+	for _, edge := range preconditionEdges {
+		self.Preconditions = append(self.Preconditions, newWorkflowPlanPrecondition(edge.Target))
+	}
+
+	// Successors and the number of their unprocessed predecessors
+	successors := make(map[*WorkflowPlanStep][]*WorkflowPlanStep)
+	predecessorCounts := make(map[*WorkflowPlanStep]int)
+	for stepVertex, step := range steps {
+		for _, edge := range stepVertex.EdgesOut {
+			if next, ok := steps[edge.Target]; ok {
+				if IsTosca(edge.Metadata, "OnSuccess") {
+					step.OnSuccess = append(step.OnSuccess, next.Name)
+					next.AfterSuccessOf = append(next.AfterSuccessOf, step.Name)
+				} else if IsTosca(edge.Metadata, "OnFailure") {
+					step.OnFailure = append(step.OnFailure, next.Name)
+					next.AfterFailureOf = append(next.AfterFailureOf, step.Name)
+				} else {
+					continue
+				}
+				successors[step] = append(successors[step], next)
+				predecessorCounts[next]++
+			}
+		}
+	}
+
+	// Kahn's algorithm, by level (sorted by name within each level for a stable result)
+	var level []*WorkflowPlanStep
+	for _, step := range steps {
+		if predecessorCounts[step] == 0 {
+			level = append(level, step)
+		}
+	}
+
+	for index := 0; len(level) > 0; index++ {
+		sort.Slice(level, func(i int, j int) bool {
+			return level[i].Name < level[j].Name
+		})
+
+		var nextLevel []*WorkflowPlanStep
+		for _, step := range level {
+			step.Level = index
+			self.Steps = append(self.Steps, step)
+
+			for _, next := range successors[step] {
+				predecessorCounts[next]--
+				if predecessorCounts[next] == 0 {
+					nextLevel = append(nextLevel, next)
+				}
+			}
+		}
+		level = nextLevel
+	}
+
+	for _, step := range self.Steps {
+		sort.Strings(step.AfterSuccessOf)
+		sort.Strings(step.AfterFailureOf)
+	}
+
+	if len(self.Steps) < len(steps) {
+		return nil, fmt.Errorf("workflow has a cycle in its steps: %s", name)
+	}
+
+	return &self, nil
+}
+
+//
+// WorkflowPlanTarget
+//
+
+type WorkflowPlanTarget struct {
+	NodeTemplate string `json:"nodeTemplate,omitempty" yaml:"nodeTemplate,omitempty"`
+	Group        string `json:"group,omitempty" yaml:"group,omitempty"`
+	Relationship string `json:"relationship,omitempty" yaml:"relationship,omitempty"`
+}
+
+func newWorkflowPlanTarget(vertex *cloutpkg.Vertex) WorkflowPlanTarget {
+	var self WorkflowPlanTarget
+
+	self.Relationship, _ = ard.With(vertex.Properties).Get("targetRelationship").String()
+
+	for _, edge := range vertex.EdgesOut {
+		if IsTosca(edge.Metadata, "NodeTemplateTarget") {
+			self.NodeTemplate, _ = ard.With(edge.Target.Properties).Get("name").String()
+			break
+		} else if IsTosca(edge.Metadata, "GroupTarget") {
+			self.Group, _ = ard.With(edge.Target.Properties).Get("name").String()
+			break
+		}
+	}
+
+	return self
+}
+
+//
+// WorkflowPlanPrecondition
+//
+
+type WorkflowPlanPrecondition struct {
+	Target    WorkflowPlanTarget `json:"target" yaml:"target"`
+	Condition ard.Value          `json:"condition,omitempty" yaml:"condition,omitempty"`
+}
+
+func newWorkflowPlanPrecondition(vertex *cloutpkg.Vertex) *WorkflowPlanPrecondition {
+	return &WorkflowPlanPrecondition{
+		Target:    newWorkflowPlanTarget(vertex),
+		Condition: vertex.Properties["condition"],
+	}
+}
+
+//
+// WorkflowPlanStep
+//
+
+// Steps at the same level do not depend on each other and can be executed in parallel.
+// A step can start after *all* the steps in AfterSuccessOf have succeeded, or after *any*
+// of the steps in AfterFailureOf has failed.
+type WorkflowPlanStep struct {
+	Name           string                  `json:"name" yaml:"name"`
+	Level          int                     `json:"level" yaml:"level"`
+	Target         WorkflowPlanTarget      `json:"target" yaml:"target"`
+	Host           string                  `json:"host,omitempty" yaml:"host,omitempty"`
+	Filter         ard.Value               `json:"filter,omitempty" yaml:"filter,omitempty"`
+	Activities     []*WorkflowPlanActivity `json:"activities" yaml:"activities"`
+	AfterSuccessOf []string                `json:"afterSuccessOf,omitempty" yaml:"afterSuccessOf,omitempty"`
+	AfterFailureOf []string                `json:"afterFailureOf,omitempty" yaml:"afterFailureOf,omitempty"`
+	OnSuccess      []string                `json:"onSuccess,omitempty" yaml:"onSuccess,omitempty"`
+	OnFailure      []string                `json:"onFailure,omitempty" yaml:"onFailure,omitempty"`
+}
+
+func newWorkflowPlanStep(vertex *cloutpkg.Vertex, inlining map[string]bool) (*WorkflowPlanStep, error) {
+	self := WorkflowPlanStep{
+		Target:     newWorkflowPlanTarget(vertex),
+		Filter:     vertex.Properties["filter"],
+		Activities: make([]*WorkflowPlanActivity, 0),
+	}
+
+	self.Name, _ = ard.With(vertex.Properties).Get("name").String()
+	self.Host, _ = ard.With(vertex.Properties).Get("host").String()
+
+	var activityEdges cloutpkg.Edges
+	for _, edge := range vertex.EdgesOut {
+		if IsTosca(edge.Metadata, "WorkflowActivity") {
+			activityEdges = append(activityEdges, edge)
+		}
+	}
+
+	sortEdgesBySequence(activityEdges)
+	for _, edge := range activityEdges {
+		if activity, err := newWorkflowPlanActivity(edge.Target, inlining); err == nil {
+			self.Activities = append(self.Activities, activity)
+		} else {
+			return nil, err
+		}
+	}
+
+	return &self, nil
+}
+
+//
+// WorkflowPlanActivity
+//
+
+// Only one of the fields is set. Inline workflows are expanded in place.
+type WorkflowPlanActivity struct {
+	SetNodeState  string                 `json:"setNodeState,omitempty" yaml:"setNodeState,omitempty"`
+	CallOperation *WorkflowPlanOperation `json:"callOperation,omitempty" yaml:"callOperation,omitempty"`
+	Delegate      string                 `json:"delegate,omitempty" yaml:"delegate,omitempty"`
+	Inline        *WorkflowPlan          `json:"inline,omitempty" yaml:"inline,omitempty"`
+}
+
+func newWorkflowPlanActivity(vertex *cloutpkg.Vertex, inlining map[string]bool) (*WorkflowPlanActivity, error) {
+	var self WorkflowPlanActivity
+
+	if setNodeState, ok := ard.With(vertex.Properties).Get("setNodeState").String(); ok {
+		self.SetNodeState = setNodeState
+	} else if callOperation, ok := ard.With(vertex.Properties).Get("callOperation").StringMap(); ok {
+		self.CallOperation = &WorkflowPlanOperation{Inputs: callOperation["inputs"]}
+		self.CallOperation.Interface, _ = ard.With(callOperation).Get("interface").String()
+		self.CallOperation.Operation, _ = ard.With(callOperation).Get("operation").String()
+	} else {
+		for _, edge := range vertex.EdgesOut {
+			if IsTosca(edge.Metadata, "DelegateWorkflow") {
+				self.Delegate, _ = ard.With(edge.Target.Properties).Get("name").String()
+				break
+			} else if IsTosca(edge.Metadata, "InlineWorkflow") {
+				var err error
+				if self.Inline, err = newWorkflowPlan(edge.Target, inlining); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+	}
+
+	return &self, nil
+}
+
+//
+// WorkflowPlanOperation
+//
+
+type WorkflowPlanOperation struct {
+	Interface string    `json:"interface" yaml:"interface"`
+	Operation string    `json:"operation" yaml:"operation"`
+	Inputs    ard.Value `json:"inputs" yaml:"inputs"`
+}
+
+// Utils
+
+func sortEdgesBySequence(edges cloutpkg.Edges) {
+	sort.SliceStable(edges, func(i int, j int) bool {
+		iSequence, _ := ard.With(edges[i].Properties).Get("sequence").ConvertSimilar().Integer()
+		jSequence, _ := ard.With(edges[j].Properties).Get("sequence").ConvertSimilar().Integer()
+		return iSequence < jSequence
+	})
+}
@@ -0,0 +1,131 @@
+// An example of a program that registers its own grammar with the Puccini parser.
+//
+// Usage:
+//
+//	go run ./examples/go/grammar-plugin examples/go/grammar-plugin/greetings.yaml
+package main
+
+import (
+	contextpkg "context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/grammars"
+	parserpkg "github.com/tliron/go-puccini/tosca/parser"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/go-transcribe"
+	"github.com/tliron/yamlkeys"
+)
+
+// A grammar for files with a "greetings_version" keyword (see greetings.yaml)
+var Grammar = parsing.NewGrammar()
+
+func init() {
+	Grammar.RegisterVersion("greetings_version", "1.0", "")
+	Grammar.RegisterReader("$Root", ReadGreetings)
+
+	// Grammars must be registered before parsing
+	grammars.MustRegister(&Grammar)
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: grammar-plugin [URL]")
+		os.Exit(1)
+	}
+
+	context := contextpkg.TODO()
+
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	workingDir, err := urlContext.NewWorkingDirFileURL()
+	failOnError(err)
+
+	url, err := urlContext.NewValidAnyOrFileURL(context, os.Args[1], []exturl.URL{workingDir})
+	failOnError(err)
+
+	parserContext := parserpkg.NewParser().NewContext()
+	defer parserContext.Repositories.Release()
+	parserContext.URL = url
+
+	serviceTemplate, err := parserContext.Parse(context)
+	if err != nil {
+		if problems := parserContext.GetProblems(); !problems.Empty() {
+			fmt.Fprintln(os.Stderr, problems.ToString(true))
+		}
+		failOnError(err)
+	}
+
+	failOnError(transcribe.NewTranscriber().Write(serviceTemplate))
+}
+
+//
+// Greetings
+//
+
+type Greetings struct {
+	Context *parsing.Context `traverse:"ignore" json:"-" yaml:"-"`
+
+	Messages map[string]string
+}
+
+// ([parsing.Reader] signature)
+func ReadGreetings(context *parsing.Context) parsing.EntityPtr {
+	self := Greetings{
+		Context:  context,
+		Messages: make(map[string]string),
+	}
+
+	context.ValidateUnsupportedFields([]string{"greetings_version", "greetings"})
+
+	if childContext, ok := context.GetRequiredFieldChild("greetings"); ok {
+		if childContext.ValidateType(ard.TypeMap) {
+			for name, data := range childContext.Data.(ard.Map) {
+				name_ := yamlkeys.KeyString(name)
+				if message := childContext.MapChild(name_, data).ReadString(); message != nil {
+					self.Messages[name_] = *message
+				}
+			}
+		}
+	}
+
+	return &self
+}
+
+// ([parsing.Contextual] interface)
+func (self *Greetings) GetContext() *parsing.Context {
+	return self.Context
+}
+
+// ([normal.Normalizable] interface)
+func (self *Greetings) NormalizeServiceTemplate() *normal.ServiceTemplate {
+	normalServiceTemplate := normal.NewServiceTemplate()
+
+	names := make([]string, 0, len(self.Messages))
+	for name := range self.Messages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		normalNodeTemplate := normalServiceTemplate.NewNodeTemplate(name)
+		normalNodeTemplate.Types = normal.NewEntityTypes("Greeting")
+		normalNodeTemplate.Properties["message"] = normal.NewPrimitive(self.Messages[name])
+	}
+
+	return normalServiceTemplate
+}
+
+// Utils
+
+func failOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
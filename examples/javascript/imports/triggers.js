@@ -0,0 +1,41 @@
+// This scriptlet evaluates the policy triggers against a snapshot of attribute values (as
+// might be reported by monitoring) and generates a report of the actions to take
+
+const traversal = require('tosca.lib.traversal');
+const tosca = require('tosca.lib.utils');
+
+// "traversal.coerce" calls all intrinsic functions and validates all constraints
+traversal.coerce();
+
+// Attribute values per node template (can be provided as JSON via "--argument=snapshot=...")
+let snapshot = {
+	server1: {load: 95},
+	server2: {load: 50}
+};
+if (env.arguments.snapshot !== undefined)
+	snapshot = JSON.parse(env.arguments.snapshot);
+
+let report = {};
+
+for (let v in clout.vertexes) {
+	let vertex = clout.vertexes[v];
+
+	// We'll skip vertexes that are not TOSCA policies
+	if (!tosca.isTosca(vertex, 'Policy'))
+		continue;
+
+	let triggers = {};
+	for (let e = 0, l = vertex.edgesOut.size(); e < l; e++) {
+		let edge = vertex.edgesOut[e];
+		if (tosca.isTosca(edge, 'PolicyTrigger')) {
+			let name = edge.target.properties.name;
+			triggers[name] = tosca.evaluateTrigger(vertex, name, snapshot);
+		}
+	}
+
+	report[vertex.properties.name] = triggers;
+}
+
+exports.report = report;
+
+transcribe.output(report);
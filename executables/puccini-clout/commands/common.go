@@ -58,3 +58,12 @@ func LoadClout(context contextpkg.Context, url string, urlContext *exturl.Contex
 	util.FailOnError(err)
 	return clout
 }
+
+// Unlike LoadClout does not fail and does not read from stdin.
+func ReadClout(context contextpkg.Context, url string, urlContext *exturl.Context) (*clout.Clout, error) {
+	if url_, err := urlContext.NewValidAnyOrFileURL(context, url, Bases(urlContext)); err == nil {
+		return cloutpkg.Load(context, url_, inputFormat)
+	} else {
+		return nil, err
+	}
+}
@@ -0,0 +1,291 @@
+package commands
+
+import (
+	"bytes"
+	contextpkg "context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tliron/exturl"
+	problemspkg "github.com/tliron/go-kutil/problems"
+	"github.com/tliron/go-kutil/terminal"
+	"github.com/tliron/go-kutil/util"
+	"github.com/tliron/go-puccini/clout/js"
+)
+
+// Placeholders for output path templates
+var outputTemplatePlaceholders = []string{"{name}", "{base}", "{dir}", "{index}"}
+
+// Executes the scriptlet on all Clouts using a pool of workers. Problems are aggregated
+// per Clout and we exit with an error if any Clout failed.
+func ExecBatch(scriptletName string, urls []string) {
+	if len(breakpoints) > 0 {
+		util.Fail("cannot use the debugger with multiple Clouts")
+	}
+
+	if (output != "") && !IsOutputTemplate(output) {
+		util.Failf("output for multiple Clouts must be a template with at least one of: %s", strings.Join(outputTemplatePlaceholders, ", "))
+	}
+
+	if (traceOutput != "") && !IsOutputTemplate(traceOutput) {
+		util.Failf("trace output for multiple Clouts must be a template with at least one of: %s", strings.Join(outputTemplatePlaceholders, ", "))
+	}
+
+	if format == "" {
+		format = inputFormat
+	}
+
+	jobs_ := jobs
+	if jobs_ < 1 {
+		jobs_ = 1
+	}
+
+	problems := problemspkg.NewProblems(terminal.StderrStylist)
+	programCache := js.NewProgramCache()
+	var failed int
+	var lock sync.Mutex
+
+	indexes := make(chan int)
+	var waitGroup sync.WaitGroup
+	for range jobs_ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for index := range indexes {
+				url := urls[index]
+				cloutProblems := problemspkg.NewProblems(terminal.StderrStylist)
+				var stdout, stderr bytes.Buffer
+
+				err := execBatchItem(scriptletName, url, index, programCache, cloutProblems, &stdout, &stderr)
+
+				lock.Lock()
+				os.Stdout.Write(stdout.Bytes())
+				os.Stderr.Write(stderr.Bytes())
+				if (err != nil) || !cloutProblems.Empty() {
+					failed++
+					if err != nil {
+						problems.ReportFull(0, url, "", err.Error(), -1, -1)
+					}
+					for _, problem := range cloutProblems.Slice() {
+						problems.ReportFull(0, url, problem.Item, problem.Message, problem.Row, problem.Column)
+					}
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+
+	for index := range urls {
+		indexes <- index
+	}
+	close(indexes)
+	waitGroup.Wait()
+
+	if !terminal.Quiet {
+		if failed > 0 {
+			problems.Print(verbose > 0)
+		}
+		terminal.Eprintf("%s %d Clouts (%d failed)\n", terminal.StderrStylist.Heading("executed:"), len(urls), failed)
+	}
+
+	if failed > 0 {
+		util.Exit(1)
+	}
+}
+
+func execBatchItem(scriptletName string, url string, index int, programCache *js.ProgramCache, problems *problemspkg.Problems, stdout *bytes.Buffer, stderr *bytes.Buffer) error {
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	context, cancel := contextpkg.WithTimeout(contextpkg.Background(), time.Duration(timeout*float64(time.Second)))
+	defer cancel()
+
+	clout, err := ReadClout(context, url, urlContext)
+	if err != nil {
+		return err
+	}
+
+	if err := PrepareScriptlet(context, scriptletName, clout, urlContext); err != nil {
+		return err
+	}
+
+	environment := NewExecEnvironment(scriptletName, urlContext, ExpandOutputTemplate(output, url, index))
+	environment.ProgramCache = programCache
+	environment.Stdout = stdout
+	environment.Stderr = stderr
+
+	return Exec(environment, scriptletName, clout, problems, ExpandOutputTemplate(traceOutput, url, index))
+}
+
+func IsOutputTemplate(template string) bool {
+	for _, placeholder := range outputTemplatePlaceholders {
+		if strings.Contains(template, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expands "{name}" (file name without extension), "{base}" (file name), "{dir}" (directory),
+// and "{index}" (position in the list of Clouts).
+func ExpandOutputTemplate(template string, url string, index int) string {
+	if template == "" {
+		return ""
+	}
+
+	url = filepath.ToSlash(url)
+	base := path.Base(url)
+	name := strings.TrimSuffix(base, path.Ext(base))
+	dir := filepath.FromSlash(path.Dir(url))
+
+	return strings.NewReplacer(
+		"{name}", name,
+		"{base}", base,
+		"{dir}", dir,
+		"{index}", strconv.Itoa(index),
+	).Replace(template)
+}
+
+// Expands glob patterns (which also support "**" for any number of directories). Batch is
+// true if there is more than one URL or if a glob was used.
+func ExpandCloutURLs(urls []string) ([]string, bool, error) {
+	var expanded []string
+	var glob bool
+	for _, url := range urls {
+		if IsGlob(url) {
+			glob = true
+			if paths, err := Glob(url); err == nil {
+				if len(paths) == 0 {
+					return nil, false, fmt.Errorf("no Clouts match: %s", url)
+				}
+				expanded = append(expanded, paths...)
+			} else {
+				return nil, false, err
+			}
+		} else {
+			expanded = append(expanded, url)
+		}
+	}
+	return expanded, glob || (len(expanded) > 1), nil
+}
+
+func IsGlob(path string) bool {
+	return !strings.Contains(path, "://") && strings.ContainsAny(path, "*?[")
+}
+
+// As in shells, wildcards (including "**") do not match hidden files and directories
+// (names starting with ".") unless the pattern segment starts with ".".
+func Glob(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		patterns := strings.Split(filepath.ToSlash(pattern), "/")
+		var visible []string
+		for _, path_ := range paths {
+			if !matchesGlobHidden(patterns, strings.Split(filepath.ToSlash(path_), "/")) {
+				visible = append(visible, path_)
+			}
+		}
+		return visible, nil
+	}
+
+	// The root is everything before the first segment with a glob
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	var rootSegments []string
+	for len(segments) > 0 && !IsGlob(segments[0]) {
+		rootSegments = append(rootSegments, segments[0])
+		segments = segments[1:]
+	}
+
+	root := strings.Join(rootSegments, "/")
+	if root == "" {
+		if strings.HasPrefix(pattern, "/") {
+			root = "/"
+		} else {
+			root = "."
+		}
+	}
+	root = filepath.FromSlash(root)
+
+	var paths []string
+	err := filepath.WalkDir(root, func(path_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if (path_ == root) && errors.Is(err, fs.ErrNotExist) {
+				// No matches (like filepath.Glob)
+				return nil
+			}
+			return err
+		}
+		if !entry.IsDir() {
+			if relativePath, err := filepath.Rel(root, path_); err == nil {
+				if matchGlobSegments(segments, strings.Split(filepath.ToSlash(relativePath), "/")) {
+					paths = append(paths, path_)
+				}
+			} else {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return paths, err
+}
+
+func matchGlobSegments(patterns []string, segments []string) bool {
+	if len(patterns) == 0 {
+		return len(segments) == 0
+	}
+
+	if patterns[0] == "**" {
+		// Match zero or more segments
+		for index := 0; index <= len(segments); index++ {
+			if matchGlobSegments(patterns[1:], segments[index:]) {
+				return true
+			}
+			if (index < len(segments)) && isHiddenGlobSegment(segments[index]) {
+				return false
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if isHiddenGlobSegment(segments[0]) && !isHiddenGlobSegment(patterns[0]) {
+		return false
+	}
+
+	if matched, _ := path.Match(patterns[0], segments[0]); matched {
+		return matchGlobSegments(patterns[1:], segments[1:])
+	}
+
+	return false
+}
+
+// Whether a hidden segment was matched by a wildcard. Segments are aligned from the end
+// because the matched path may be cleaned (e.g. without a leading "./").
+func matchesGlobHidden(patterns []string, segments []string) bool {
+	for index := 1; (index <= len(patterns)) && (index <= len(segments)); index++ {
+		if isHiddenGlobSegment(segments[len(segments)-index]) && !isHiddenGlobSegment(patterns[len(patterns)-index]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHiddenGlobSegment(segment string) bool {
+	return strings.HasPrefix(segment, ".") && (segment != ".") && (segment != "..")
+}
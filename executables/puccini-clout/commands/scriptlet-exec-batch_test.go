@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+var expandOutputTemplateTests = []struct {
+	template string
+	url      string
+	index    int
+	expected string
+}{
+	{"", "clouts/a.yaml", 0, ""},
+	{"out/{name}.json", "clouts/a.yaml", 0, "out/a.json"},
+	{"out/{name}.json", "clouts/a.b.yaml", 0, "out/a.b.json"},
+	{"out/{base}", "clouts/a.yaml", 0, "out/a.yaml"},
+	{"{dir}/{name}.out.yaml", "clouts/sub/a.yaml", 0, "clouts/sub/a.out.yaml"},
+	{"{dir}/{name}.out.yaml", "a.yaml", 0, "./a.out.yaml"},
+	{"out/{index}-{name}", "clouts/a.yaml", 12, "out/12-a"},
+	{"out/{name}-{name}", "a", 0, "out/a-a"},
+	{"out/{unknown}", "a.yaml", 0, "out/{unknown}"},
+}
+
+func TestExpandOutputTemplate(t *testing.T) {
+	for _, test := range expandOutputTemplateTests {
+		if expanded := ExpandOutputTemplate(test.template, filepath.FromSlash(test.url), test.index); expanded != filepath.FromSlash(test.expected) {
+			t.Errorf("%q for %q: expected %q, got %q", test.template, test.url, test.expected, expanded)
+		}
+	}
+}
+
+var globTests = []struct {
+	pattern  string
+	expected []string
+}{
+	// "**" at the start
+	{"**/*.yaml", []string{"a.yaml", "sub/b.yaml", "sub/deep/c.yaml"}},
+	{"**/c.yaml", []string{"sub/deep/c.yaml"}},
+
+	// "**" in the middle
+	{"sub/**/*.yaml", []string{"sub/b.yaml", "sub/deep/c.yaml"}},
+	{"sub/**/deep/*.yaml", []string{"sub/deep/c.yaml"}},
+
+	// "**" at the end
+	{"sub/**", []string{"sub/b.yaml", "sub/deep/c.yaml", "sub/deep/d.json"}},
+
+	// Without "**"
+	{"*.yaml", []string{"a.yaml"}},
+	{"sub/*/*.json", []string{"sub/deep/d.json"}},
+
+	// No matches
+	{"**/*.xml", nil},
+	{"none/**/*.yaml", nil},
+	{"*.xml", nil},
+
+	// Hidden files and directories are only matched explicitly
+	{".*.yaml", []string{".hidden.yaml"}},
+	{"**/.*.yaml", []string{".hidden.yaml", "sub/.e.yaml"}},
+	{".git/**", []string{".git/f.yaml"}},
+}
+
+func TestGlob(t *testing.T) {
+	root := t.TempDir()
+	for _, path := range []string{
+		"a.yaml",
+		".hidden.yaml",
+		"sub/b.yaml",
+		"sub/.e.yaml",
+		"sub/deep/c.yaml",
+		"sub/deep/d.json",
+		".git/f.yaml",
+	} {
+		writeTestFile(t, filepath.Join(root, filepath.FromSlash(path)))
+	}
+
+	for _, test := range globTests {
+		paths, err := Glob(filepath.Join(root, filepath.FromSlash(test.pattern)))
+		if err != nil {
+			t.Errorf("%q: %s", test.pattern, err.Error())
+			continue
+		}
+
+		var relativePaths []string
+		for _, path := range paths {
+			if relativePath, err := filepath.Rel(root, path); err == nil {
+				relativePaths = append(relativePaths, filepath.ToSlash(relativePath))
+			} else {
+				t.Fatalf("%s", err.Error())
+			}
+		}
+		slices.Sort(relativePaths)
+
+		if !slices.Equal(relativePaths, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.pattern, test.expected, relativePaths)
+		}
+	}
+}
+
+var matchGlobSegmentsTests = []struct {
+	pattern  []string
+	segments []string
+	expected bool
+}{
+	{[]string{"**"}, nil, true},
+	{[]string{"**"}, []string{"a", "b"}, true},
+	{[]string{"**", "b"}, []string{"b"}, true},
+	{[]string{"**", "b"}, []string{"a", "b"}, true},
+	{[]string{"a", "**", "c"}, []string{"a", "c"}, true},
+	{[]string{"a", "**", "c"}, []string{"a", "b", "b", "c"}, true},
+	{[]string{"a", "**"}, []string{"a"}, true},
+	{[]string{"a", "**", "c"}, []string{"a", "b"}, false},
+	{[]string{"a", "*"}, []string{"a"}, false},
+	{[]string{"a"}, []string{"a", "b"}, false},
+	{[]string{"**", "*"}, []string{".a", "b"}, false},
+	{[]string{"*"}, []string{".a"}, false},
+	{[]string{".*"}, []string{".a"}, true},
+}
+
+func TestMatchGlobSegments(t *testing.T) {
+	for _, test := range matchGlobSegmentsTests {
+		if matched := matchGlobSegments(test.pattern, test.segments); matched != test.expected {
+			t.Errorf("%v for %v: expected %t, got %t", test.pattern, test.segments, test.expected, matched)
+		}
+	}
+}
+
+// Utils
+
+func writeTestFile(t *testing.T, path string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+}
@@ -2,35 +2,61 @@ package commands
 
 import (
 	contextpkg "context"
+	"errors"
+	"os"
+	"runtime"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tliron/commonjs-goja"
 	"github.com/tliron/exturl"
+	problemspkg "github.com/tliron/go-kutil/problems"
 	"github.com/tliron/go-kutil/terminal"
 	"github.com/tliron/go-kutil/util"
 	cloutpkg "github.com/tliron/go-puccini/clout"
 	"github.com/tliron/go-puccini/clout/js"
+	"github.com/tliron/go-transcribe"
 )
 
-var arguments map[string]string
+var (
+	arguments    map[string]string
+	trace        bool
+	traceOutput  string
+	breakpoints  []string
+	jobs         int
+	revealHidden bool
+)
 
 func init() {
 	scriptletCommand.AddCommand(execCommand)
-	execCommand.Flags().StringVarP(&output, "output", "o", "", "output to file or directory (default is stdout)")
+	execCommand.Flags().StringVarP(&output, "output", "o", "", "output to file or directory (default is stdout); for multiple Clouts this is a template")
 	execCommand.Flags().StringToStringVarP(&arguments, "argument", "a", nil, "specify a scriptlet argument (format is key=value)")
+	execCommand.Flags().BoolVarP(&trace, "trace", "t", false, "print a tree of all function call evaluations to stderr")
+	execCommand.Flags().StringVarP(&traceOutput, "trace-output", "", "", "output function call trace to file (uses --format); for multiple Clouts this is a template")
+	execCommand.Flags().StringSliceVarP(&breakpoints, "break", "b", nil, "break into the interactive debugger before calling this function scriptlet (\"*\" for all)")
+	execCommand.Flags().IntVarP(&jobs, "jobs", "j", runtime.NumCPU(), "maximum number of Clouts to process concurrently (compiled scriptlets are cached in memory and shared only by the Clouts of a single invocation)")
+	execCommand.Flags().BoolVar(&revealHidden, "reveal-hidden", false, "do not mask the values of hidden inputs in coerced output")
 }
 
 var execCommand = &cobra.Command{
-	Use:   "exec [NAME or JavaScript PATH or URL] [[Clout PATH or URL]]",
+	Use:   "exec [NAME or JavaScript PATH or URL] [[Clout PATH, URL, or glob] ...]",
 	Short: "Execute JavaScript scriptlet on Clout",
 	Long:  ``,
-	Args:  cobra.RangeArgs(1, 2),
+	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		scriptletName := args[0]
 
+		urls, batch, err := ExpandCloutURLs(args[1:])
+		util.FailOnError(err)
+
+		if batch {
+			ExecBatch(scriptletName, urls)
+			return
+		}
+
 		var url string
-		if len(args) == 2 {
-			url = args[1]
+		if len(urls) == 1 {
+			url = urls[0]
 		}
 
 		urlContext := exturl.NewContext()
@@ -39,30 +65,90 @@ var execCommand = &cobra.Command{
 		context, cancel := contextpkg.WithTimeout(contextpkg.Background(), time.Duration(timeout*float64(time.Second)))
 		util.OnExit(cancel)
 
-		clout := LoadClout(context, url, urlContext)
+		if (len(breakpoints) > 0) && (url == "") {
+			util.Fail("cannot use the debugger when reading Clout from stdin")
+		}
 
-		// Try loading JavaScript from Clout
-		scriptlet, err := js.GetScriptlet(scriptletName, clout)
+		clout := LoadClout(context, url, urlContext)
 
-		if err != nil {
-			// Try loading JavaScript from path or URL
-			scriptletUrl, err := urlContext.NewValidAnyOrFileURL(context, scriptletName, Bases(urlContext))
-			util.FailOnError(err)
+		err = PrepareScriptlet(context, scriptletName, clout, urlContext)
+		util.FailOnError(err)
 
-			scriptlet, err = exturl.ReadString(context, scriptletUrl)
-			util.FailOnError(err)
+		problems := problemspkg.NewProblems(terminal.StderrStylist)
+		environment := NewExecEnvironment(scriptletName, urlContext, output)
+		err = Exec(environment, scriptletName, clout, problems, traceOutput)
+		util.FailOnError(err)
 
-			err = js.SetScriptlet(scriptletName, js.CleanupScriptlet(scriptlet), clout)
-			util.FailOnError(err)
+		if !problems.Empty() {
+			if !terminal.Quiet {
+				problems.Print(verbose > 0)
+			}
+			util.Exit(1)
 		}
-
-		err = Exec(scriptletName, scriptlet, clout, urlContext)
-		util.FailOnError(err)
 	},
 }
 
-func Exec(scriptletName string, scriptlet string, clout *cloutpkg.Clout, urlContext *exturl.Context) error {
+// Makes sure the scriptlet is in the Clout, loading it from a path or URL if necessary.
+func PrepareScriptlet(context contextpkg.Context, scriptletName string, clout *cloutpkg.Clout, urlContext *exturl.Context) error {
+	// Try loading JavaScript from Clout
+	if _, err := js.GetScriptlet(scriptletName, clout); err == nil {
+		return nil
+	}
+
+	// Try loading JavaScript from path or URL
+	scriptletUrl, err := urlContext.NewValidAnyOrFileURL(context, scriptletName, Bases(urlContext))
+	if err != nil {
+		return err
+	}
+
+	scriptlet, err := exturl.ReadString(context, scriptletUrl)
+	if err != nil {
+		return err
+	}
+
+	return js.SetScriptlet(scriptletName, js.CleanupScriptlet(scriptlet), clout)
+}
+
+func NewExecEnvironment(scriptletName string, urlContext *exturl.Context, output string) *js.Environment {
 	environment := js.NewEnvironment(scriptletName, log, arguments, terminal.Quiet, format, strict, pretty, false, output, urlContext)
-	_, err := environment.Require(clout, scriptletName, nil)
+
+	if trace || (traceOutput != "") || (len(breakpoints) > 0) {
+		environment.Trace = js.NewTrace()
+		if len(breakpoints) > 0 {
+			environment.Trace.Debugger = js.NewDebugger(breakpoints, os.Stdin, os.Stderr, terminal.StderrStylist)
+		}
+	}
+
+	return environment
+}
+
+func Exec(environment *js.Environment, scriptletName string, clout *cloutpkg.Clout, problems *problemspkg.Problems, traceOutput string) error {
+	// commonjs.CreateExtensionFunc signature
+	createProblemsExtension := func(jsContext *commonjs.Context) any {
+		return problems
+	}
+
+	// Hidden inputs are masked in the output
+	if !revealHidden {
+		environment.Redactor = js.NewRedactor(clout)
+	}
+
+	_, err := environment.Require(clout, scriptletName, map[string]commonjs.CreateExtensionFunc{"problems": createProblemsExtension})
+
+	if trace && !terminal.Quiet {
+		environment.Trace.Write(environment.Stderr, terminal.StderrStylist)
+	}
+
+	if traceOutput != "" {
+		transcriber := transcribe.Transcriber{
+			File:   traceOutput,
+			Format: format,
+			Strict: strict,
+		}
+		if err_ := transcriber.Write(environment.Trace); err_ != nil {
+			err = errors.Join(err, err_)
+		}
+	}
+
 	return err
 }
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	contextpkg "context"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-kutil/terminal"
+	"github.com/tliron/go-kutil/util"
+	cloutpkg "github.com/tliron/go-puccini/clout"
+	"github.com/tliron/go-puccini/clout/js"
+	"github.com/tliron/go-puccini/normal"
+)
+
+func init() {
+	scriptletCommand.AddCommand(typesCommand)
+	typesCommand.Flags().StringVarP(&output, "output", "o", "", "output to file (default is stdout)")
+}
+
+var typesCommand = &cobra.Command{
+	Use:   "types [[Clout PATH or URL]]",
+	Short: "Generate TypeScript declarations for the JavaScript scriptlet API",
+	Long:  `Generates TypeScript declarations (".d.ts") for the objects injected into JavaScript scriptlets, as well as a module declaration for every scriptlet in the Clout that has exports.`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var url string
+		if len(args) == 1 {
+			url = args[0]
+		}
+
+		urlContext := exturl.NewContext()
+		util.OnExitError(urlContext.Release)
+
+		context, cancel := contextpkg.WithTimeout(contextpkg.Background(), time.Duration(timeout*float64(time.Second)))
+		util.OnExit(cancel)
+
+		clout := LoadClout(context, url, urlContext)
+
+		typeScript := NewTypeScript(clout)
+
+		if output != "" {
+			file, err := os.Create(output)
+			util.FailOnError(err)
+			defer file.Close()
+			util.FailOnError(typeScript.Write(file))
+		} else if !terminal.Quiet {
+			util.FailOnError(typeScript.Write(os.Stdout))
+		}
+	},
+}
+
+func NewTypeScript(clout *cloutpkg.Clout) *js.TypeScript {
+	typeScript := js.NewTypeScript()
+
+	typeScript.AddScriptletGlobals()
+
+	// Coercible value notation (as it appears in the Clout before coercion)
+	// Note that "$meta" would be added as "ValueMeta"
+	typeScript.AddData("FunctionCallNotation", reflect.TypeFor[normal.FunctionCall]())
+	typeScript.AddData("PrimitiveNotation", reflect.TypeFor[normal.Primitive]())
+	typeScript.AddData("ListNotation", reflect.TypeFor[normal.List]())
+	typeScript.AddData("MapNotation", reflect.TypeFor[normal.Map]())
+	typeScript.AddAlias("CoercibleNotation", "PrimitiveNotation | ListNotation | MapNotation | FunctionCallNotation")
+
+	if metadata, err := js.GetScriptletsMetadata(clout); err == nil {
+		addScriptletModules(typeScript, metadata, nil)
+	}
+
+	return typeScript
+}
+
+func addScriptletModules(typeScript *js.TypeScript, value ard.Value, path []string) {
+	switch value_ := value.(type) {
+	case string:
+		typeScript.AddScriptletModule(strings.Join(path, "."), value_)
+
+	case ard.StringMap:
+		for key, value__ := range value_ {
+			addScriptletModules(typeScript, value__, append(path, key))
+		}
+	}
+}
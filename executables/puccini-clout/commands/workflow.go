@@ -0,0 +1,48 @@
+package commands
+
+import (
+	contextpkg "context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-kutil/terminal"
+	"github.com/tliron/go-kutil/util"
+	cloututil "github.com/tliron/go-puccini/clout/util"
+)
+
+func init() {
+	rootCommand.AddCommand(workflowCommand)
+	workflowCommand.Flags().StringVarP(&output, "output", "o", "", "output to file (default is stdout)")
+}
+
+var workflowCommand = &cobra.Command{
+	Use:   "workflow [NAME] [[Clout PATH or URL]]",
+	Short: "Generate an execution plan for a TOSCA workflow in Clout",
+	Long:  ``,
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		workflowName := args[0]
+
+		var url string
+		if len(args) == 2 {
+			url = args[1]
+		}
+
+		urlContext := exturl.NewContext()
+		util.OnExitError(urlContext.Release)
+
+		context, cancel := contextpkg.WithTimeout(contextpkg.Background(), time.Duration(timeout*float64(time.Second)))
+		util.OnExit(cancel)
+
+		clout := LoadClout(context, url, urlContext)
+
+		plan, err := cloututil.NewWorkflowPlan(clout, workflowName)
+		util.FailOnError(err)
+
+		if !terminal.Quiet {
+			err = Transcriber().Write(plan)
+			util.FailOnError(err)
+		}
+	},
+}
@@ -2,12 +2,14 @@ package commands
 
 import (
 	"os"
+	"strings"
 
 	"github.com/tliron/commonlog"
 	"github.com/tliron/exturl"
 	problemspkg "github.com/tliron/go-kutil/problems"
 	"github.com/tliron/go-kutil/terminal"
 	"github.com/tliron/go-kutil/util"
+	"github.com/tliron/go-puccini/tosca/repositories"
 	"github.com/tliron/go-transcribe"
 )
 
@@ -20,10 +22,18 @@ var (
 	template       string
 	inputs         map[string]string
 	inputsUrl      string
+	environments   []string
 	inputValues    = make(map[string]any)
 	problemsFormat string
 	quirks         []string
 	urlMappings    map[string]string
+
+	repositoryUrls        map[string]string
+	repositoryCredentials map[string]string
+
+	profilePaths []string
+
+	cloudFormationSpecifications []string
 )
 
 func Transcriber() *transcribe.Transcriber {
@@ -52,6 +62,20 @@ func Bases(urlContext *exturl.Context, withImportPaths bool) []exturl.URL {
 	return bases
 }
 
+func SetRepositories(repositories_ *repositories.Repositories) {
+	for name, url := range repositoryUrls {
+		repositories_.SetURL(name, url)
+	}
+
+	for name, credentials := range repositoryCredentials {
+		if username, password, ok := strings.Cut(credentials, ":"); ok {
+			repositories_.SetCredentials(name, username, password, "")
+		} else {
+			repositories_.SetCredentials(name, "", "", credentials)
+		}
+	}
+}
+
 func FailOnProblems(problems *problemspkg.Problems) {
 	if !problems.Empty() {
 		if !terminal.Quiet {
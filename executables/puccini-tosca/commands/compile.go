@@ -19,6 +19,10 @@ var (
 	coerce       bool
 	exec         string
 	arguments    map[string]string
+
+	verifyArtifacts bool
+	embedArtifacts  int64
+	revealHidden    bool
 )
 
 func init() {
@@ -27,15 +31,23 @@ func init() {
 	compileCommand.Flags().StringVarP(&template, "template", "t", "", "select service template in CSAR (leave empty for root, or use \"all\", path, or integer index)")
 	compileCommand.Flags().StringToStringVarP(&inputs, "input", "i", nil, "specify input (format is name=value)")
 	compileCommand.Flags().StringVarP(&inputsUrl, "inputs", "n", "", "load inputs from a PATH or URL to YAML content")
+	compileCommand.Flags().StringSliceVar(&environments, "environment", nil, "load a HOT environment from a PATH or URL (can be specified more than once)")
+	compileCommand.Flags().StringSliceVar(&cloudFormationSpecifications, "cloudformation-spec", nil, "load a CloudFormation resource specification from a PATH or URL (can be specified more than once)")
 	compileCommand.Flags().StringVarP(&problemsFormat, "problems-format", "m", "", "problems format (\"yaml\", \"json\", \"xjson\", \"xml\", \"cbor\", \"messagepack\", or \"go\")")
 	compileCommand.Flags().StringSliceVarP(&quirks, "quirk", "x", nil, "parser quirk")
 	compileCommand.Flags().StringToStringVarP(&urlMappings, "map-url", "u", nil, "map a URL (format is from=to)")
+	compileCommand.Flags().StringToStringVar(&repositoryUrls, "repository", nil, "override a repository URL (format is name=URL)")
+	compileCommand.Flags().StringToStringVar(&repositoryCredentials, "repository-credentials", nil, "specify repository credentials (format is name=username:password or name=token)")
+	compileCommand.Flags().StringSliceVar(&profilePaths, "profile-path", nil, "add a directory, CSAR, or file to the profile search path")
 
 	compileCommand.Flags().StringVarP(&output, "output", "o", "", "output Clout to file (leave empty for stdout)")
 	compileCommand.Flags().BoolVarP(&resolve, "resolve", "r", true, "resolves the topology (attempts to satisfy all requirements with capabilities)")
 	compileCommand.Flags().BoolVarP(&coerce, "coerce", "c", false, "coerces all values (calls functions and applies constraints)")
 	compileCommand.Flags().StringVarP(&exec, "exec", "e", "", "execute JavaScript scriptlet")
 	compileCommand.Flags().StringToStringVarP(&arguments, "argument", "a", nil, "used with --exec to specify a scriptlet argument (format is key=value)")
+	compileCommand.Flags().BoolVar(&revealHidden, "reveal-hidden", false, "do not mask the values of hidden inputs (and values derived from them) in output")
+	compileCommand.Flags().BoolVar(&verifyArtifacts, "verify-artifacts", false, "read artifact content to verify declared checksums and compute SHA-256 digests")
+	compileCommand.Flags().Int64Var(&embedArtifacts, "embed-artifacts", 0, "embed artifact content up to this size in bytes in the Clout (implies --verify-artifacts)")
 }
 
 var compileCommand = &cobra.Command{
@@ -70,10 +82,22 @@ func Compile(context contextpkg.Context, url string) {
 	problems := serviceContext.GetProblems()
 	urlContext := serviceContext.Root.GetContext().URL.Context()
 
+	// Artifacts
+	if verifyArtifacts || (embedArtifacts > 0) {
+		serviceTemplate.ReadArtifactContents(context, urlContext, embedArtifacts, problems)
+		FailOnProblems(problems)
+	}
+
 	// Compile
 	clout, err := serviceTemplate.Compile()
 	util.FailOnError(err)
 
+	// Hidden inputs are masked in the output
+	var redactor *js.Redactor
+	if !revealHidden {
+		redactor = js.NewRedactor(clout)
+	}
+
 	execContext := js.ExecContext{
 		Clout:      clout,
 		Problems:   problems,
@@ -82,6 +106,9 @@ func Compile(context contextpkg.Context, url string) {
 		Format:     format,
 		Strict:     strict,
 		Pretty:     pretty,
+
+		// Resolve, coerce, and exec share common scriptlets
+		ProgramCache: js.NewProgramCache(),
 	}
 
 	// Resolve
@@ -97,15 +124,15 @@ func Compile(context contextpkg.Context, url string) {
 	}
 
 	if exec != "" {
-		err = Exec(context, exec, arguments, clout, urlContext)
+		err = Exec(context, exec, arguments, clout, urlContext, execContext.ProgramCache, redactor)
 		util.FailOnError(err)
 	} else if enableOutput && (!terminal.Quiet || (output != "")) {
-		err = Transcriber().Write(clout)
+		err = Transcriber().Write(redactor.Redact(clout))
 		util.FailOnError(err)
 	}
 }
 
-func Exec(context contextpkg.Context, scriptletName string, arguments map[string]string, clout *cloutpkg.Clout, urlContext *exturl.Context) error {
+func Exec(context contextpkg.Context, scriptletName string, arguments map[string]string, clout *cloutpkg.Clout, urlContext *exturl.Context, programCache *js.ProgramCache, redactor *js.Redactor) error {
 	// Try loading JavaScript from Clout
 	scriptlet, err := js.GetScriptlet(scriptletName, clout)
 
@@ -122,6 +149,8 @@ func Exec(context contextpkg.Context, scriptletName string, arguments map[string
 	}
 
 	environment := js.NewEnvironment(scriptletName, log, arguments, terminal.Quiet, format, strict, pretty, false, output, urlContext)
+	environment.ProgramCache = programCache
+	environment.Redactor = redactor
 	_, err = environment.Require(clout, scriptletName, nil)
 	return err
 }
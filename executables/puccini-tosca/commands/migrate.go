@@ -0,0 +1,85 @@
+package commands
+
+import (
+	contextpkg "context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tliron/go-kutil/terminal"
+	"github.com/tliron/go-kutil/util"
+	"github.com/tliron/go-puccini/tosca/migration"
+)
+
+var (
+	migrateTo string
+)
+
+func init() {
+	rootCommand.AddCommand(migrateCommand)
+	migrateCommand.Flags().StringSliceVarP(&importPaths, "path", "b", nil, "specify an import path or base URL")
+	migrateCommand.Flags().StringVarP(&template, "template", "t", "", "select service template in CSAR (leave empty for root, or use path or integer index)")
+	migrateCommand.Flags().StringVarP(&problemsFormat, "problems-format", "m", "", "problems format (\"yaml\", \"json\", \"xjson\", \"xml\", \"cbor\", \"messagepack\", or \"go\")")
+	migrateCommand.Flags().StringSliceVarP(&quirks, "quirk", "x", nil, "parser quirk")
+	migrateCommand.Flags().StringToStringVarP(&urlMappings, "map-url", "u", nil, "map a URL (format is from=to)")
+	migrateCommand.Flags().StringToStringVar(&repositoryUrls, "repository", nil, "override a repository URL (format is name=URL)")
+	migrateCommand.Flags().StringToStringVar(&repositoryCredentials, "repository-credentials", nil, "specify repository credentials (format is name=username:password or name=token)")
+	migrateCommand.Flags().StringSliceVar(&profilePaths, "profile-path", nil, "add a directory, CSAR, or file to the profile search path")
+
+	migrateCommand.Flags().StringVar(&migrateTo, "to", "2.0", "TOSCA version to migrate to (only \"2.0\" is supported)")
+	migrateCommand.Flags().StringVarP(&output, "output", "o", "", "output migrated TOSCA to file (leave empty for stdout)")
+}
+
+var migrateCommand = &cobra.Command{
+	Use:   "migrate [[TOSCA PATH or URL]]",
+	Short: "Migrate TOSCA",
+	Long:  `Migrates a TOSCA 1.x service template to TOSCA 2.0, keeping comments where possible. Anything that cannot be migrated automatically is reported as a problem. Imported files are not migrated.`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var url string
+		if len(args) == 1 {
+			url = args[0]
+		}
+
+		if migrateTo != "2.0" {
+			util.Failf("unsupported --to version: %q", migrateTo)
+		}
+
+		// We only need the types
+		stopAtPhase = 4
+		dumpPhases = nil
+
+		context, cancel := contextpkg.WithTimeout(contextpkg.Background(), time.Duration(timeout*float64(time.Second)))
+		util.OnExit(cancel)
+
+		Migrate(context, url)
+	},
+}
+
+func Migrate(context contextpkg.Context, url string) {
+	parserContext, _ := Parse(context, url)
+	parserContext.MergeProblems()
+	FailOnProblems(parserContext.GetProblems())
+
+	migration_, err := migration.NewMigration(parserContext)
+	util.FailOnError(err)
+
+	document, err := migration_.Migrate(context)
+	util.FailOnError(err)
+
+	var writer io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		util.FailOnError(err)
+		defer file.Close()
+		writer = file
+	}
+
+	if !terminal.Quiet || (output != "") {
+		err = migration.Encode(document, writer)
+		util.FailOnError(err)
+	}
+
+	FailOnProblems(migration_.Problems)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/tliron/go-kutil/terminal"
 	"github.com/tliron/go-kutil/util"
 	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/grammars/cloudformation"
+	"github.com/tliron/go-puccini/tosca/grammars/hot"
 	parserpkg "github.com/tliron/go-puccini/tosca/parser"
 	"github.com/tliron/go-puccini/tosca/parsing"
 	"github.com/tliron/yamlkeys"
@@ -29,9 +31,14 @@ func init() {
 	parseCommand.Flags().StringVarP(&template, "template", "t", "", "select service template in CSAR (leave empty for root, or use path or integer index)")
 	parseCommand.Flags().StringToStringVarP(&inputs, "input", "i", nil, "specify an input (format is name=YAML)")
 	parseCommand.Flags().StringVarP(&inputsUrl, "inputs", "n", "", "load inputs from a PATH or URL to YAML content")
+	parseCommand.Flags().StringSliceVar(&environments, "environment", nil, "load a HOT environment from a PATH or URL (can be specified more than once)")
+	parseCommand.Flags().StringSliceVar(&cloudFormationSpecifications, "cloudformation-spec", nil, "load a CloudFormation resource specification from a PATH or URL (can be specified more than once)")
 	parseCommand.Flags().StringVarP(&problemsFormat, "problems-format", "m", "", "problems format (\"yaml\", \"json\", \"xjson\", \"xml\", \"cbor\", \"messagepack\", or \"go\")")
 	parseCommand.Flags().StringSliceVarP(&quirks, "quirk", "x", nil, "parser quirk")
 	parseCommand.Flags().StringToStringVarP(&urlMappings, "map-url", "u", nil, "map a URL (format is from=to)")
+	parseCommand.Flags().StringToStringVar(&repositoryUrls, "repository", nil, "override a repository URL (format is name=URL)")
+	parseCommand.Flags().StringToStringVar(&repositoryCredentials, "repository-credentials", nil, "specify repository credentials (format is name=username:password or name=token)")
+	parseCommand.Flags().StringSliceVar(&profilePaths, "profile-path", nil, "add a directory, CSAR, or file to the profile search path")
 
 	parseCommand.Flags().Uint32VarP(&stopAtPhase, "stop", "s", 6, "parser phase at which to end")
 	parseCommand.Flags().UintSliceVarP(&dumpPhases, "dump", "d", []uint{6}, "dump phase internals")
@@ -77,6 +84,8 @@ func Parse(context contextpkg.Context, url string) (*parserpkg.Context, *normal.
 	urlContext := exturl.NewContext()
 	util.OnExitError(urlContext.Release)
 
+	environment := ParseEnvironments(context, urlContext)
+	ParseCloudFormationSpecifications(context, urlContext)
 	ParseInputs(context, urlContext)
 
 	// URL mappings
@@ -97,6 +106,11 @@ func Parse(context contextpkg.Context, url string) (*parserpkg.Context, *normal.
 
 	parserContext := parser.NewContext()
 	parserContext.Quirks = parsing.NewQuirks(quirks...)
+	parserContext.Inputs = inputValues
+	parserContext.InputDefaults = environment.ParameterDefaults
+	parserContext.TypeMappings = environment.ResourceRegistry
+	util.OnExitError(parserContext.Repositories.Release)
+	SetRepositories(parserContext.Repositories)
 	parserContext.Stylist = terminal.StdoutStylist
 	if problemsFormat != "" {
 		parserContext.Stylist = terminal.NewStylist(false)
@@ -249,6 +263,37 @@ func ToPrintPhase(phase uint) bool {
 	return false
 }
 
+// Environment parameters are added to the inputs, so they can be overridden by them
+func ParseEnvironments(context contextpkg.Context, urlContext *exturl.Context) *hot.Environment {
+	environment := hot.NewEnvironment()
+
+	for _, environmentUrl := range environments {
+		log.Infof("load environment from %q", environmentUrl)
+
+		url, err := urlContext.NewValidAnyOrFileURL(context, environmentUrl, Bases(urlContext, false))
+		util.FailOnError(err)
+		err = environment.Read(context, url)
+		util.FailOnError(err)
+	}
+
+	for name, value := range environment.Parameters {
+		inputValues[name] = value
+	}
+
+	return environment
+}
+
+func ParseCloudFormationSpecifications(context contextpkg.Context, urlContext *exturl.Context) {
+	for _, specificationUrl := range cloudFormationSpecifications {
+		log.Infof("load CloudFormation resource specification from %q", specificationUrl)
+
+		url, err := urlContext.NewValidAnyOrFileURL(context, specificationUrl, Bases(urlContext, false))
+		util.FailOnError(err)
+		err = cloudformation.DefaultSpecification.Read(context, url)
+		util.FailOnError(err)
+	}
+}
+
 func ParseInputs(context contextpkg.Context, urlContext *exturl.Context) {
 	if inputsUrl != "" {
 		log.Infof("load inputs from %q", inputsUrl)
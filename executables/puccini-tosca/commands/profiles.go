@@ -0,0 +1,55 @@
+package commands
+
+import (
+	contextpkg "context"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tliron/go-kutil/terminal"
+	"github.com/tliron/go-kutil/util"
+	"github.com/tliron/go-puccini/tosca/profiles"
+)
+
+func init() {
+	rootCommand.AddCommand(profilesCommand)
+	profilesCommand.Flags().StringSliceVar(&profilePaths, "profile-path", nil, "add a directory, CSAR, or file to the profile search path")
+}
+
+var profilesCommand = &cobra.Command{
+	Use:   "profiles",
+	Short: "List TOSCA profiles",
+	Long:  `Lists the TOSCA 2.0 profiles available for import by name and version, and detects conflicts (more than one location for the same name and version).`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		context, cancel := contextpkg.WithTimeout(contextpkg.Background(), time.Duration(timeout*float64(time.Second)))
+		util.OnExit(cancel)
+
+		profiles_, err := profiles.DefaultRegistry.List(context)
+		util.FailOnError(err)
+
+		conflicts, err := profiles.DefaultRegistry.Conflicts(context)
+		util.FailOnError(err)
+
+		if format != "" {
+			util.FailOnError(Transcriber().Write(profiles_))
+		} else {
+			for _, profile := range profiles_ {
+				terminal.Printf("%s\t%s\t%s\n", profile.Name, profile.Version, profile.Location)
+			}
+		}
+
+		if len(conflicts) > 0 {
+			if !terminal.Quiet {
+				for _, conflict := range conflicts {
+					locations := make([]string, len(conflict))
+					for index, profile := range conflict {
+						locations[index] = profile.Location
+					}
+					terminal.Eprintf("conflict: %s: %s\n", conflict[0].String(), strings.Join(locations, ", "))
+				}
+			}
+			util.Exit(1)
+		}
+	},
+}
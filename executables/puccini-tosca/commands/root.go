@@ -5,6 +5,7 @@ import (
 	"github.com/tliron/commonlog"
 	"github.com/tliron/go-kutil/terminal"
 	"github.com/tliron/go-kutil/util"
+	"github.com/tliron/go-puccini/tosca/profiles"
 )
 
 var (
@@ -40,6 +41,9 @@ var rootCommand = &cobra.Command{
 		util.InitializeCPUProfiling(cpuProfilePath)
 		util.InitializeColorization(colorize)
 		commonlog.Initialize(verbose, logTo)
+
+		// Profile search path (the "--profile-path" flag is set by some of the commands)
+		profiles.DefaultRegistry.AddSearchPath(profilePaths...)
 	},
 }
 
@@ -19,9 +19,14 @@ func init() {
 	validateCommand.Flags().StringVarP(&template, "template", "t", "", "select service template in CSAR (leave empty for root, or use \"all\", path, or integer index)")
 	validateCommand.Flags().StringToStringVarP(&inputs, "input", "i", nil, "specify input (format is name=value)")
 	validateCommand.Flags().StringVarP(&inputsUrl, "inputs", "n", "", "load inputs from a PATH or URL to YAML content")
+	validateCommand.Flags().StringSliceVar(&environments, "environment", nil, "load a HOT environment from a PATH or URL (can be specified more than once)")
+	validateCommand.Flags().StringSliceVar(&cloudFormationSpecifications, "cloudformation-spec", nil, "load a CloudFormation resource specification from a PATH or URL (can be specified more than once)")
 	validateCommand.Flags().StringVarP(&problemsFormat, "problems-format", "m", "", "problems format (\"yaml\", \"json\", \"xjson\", \"xml\", \"cbor\", \"messagepack\", or \"go\")")
 	validateCommand.Flags().StringSliceVarP(&quirks, "quirk", "x", nil, "parser quirk")
 	validateCommand.Flags().StringToStringVarP(&urlMappings, "map-url", "u", nil, "map a URL (format is from=to)")
+	validateCommand.Flags().StringToStringVar(&repositoryUrls, "repository", nil, "override a repository URL (format is name=URL)")
+	validateCommand.Flags().StringToStringVar(&repositoryCredentials, "repository-credentials", nil, "specify repository credentials (format is name=username:password or name=token)")
+	validateCommand.Flags().StringSliceVar(&profilePaths, "profile-path", nil, "add a directory, CSAR, or file to the profile search path")
 
 	validateCommand.Flags().BoolVarP(&resolve, "resolve", "r", true, "resolves the topology (attempts to satisfy all requirements with capabilities)")
 	validateCommand.Flags().BoolVarP(&validateCoerce, "coerce", "c", true, "coerces all values (calls functions and applies constraints)")
@@ -4,6 +4,7 @@ import (
 	"C"
 	contextpkg "context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/tliron/exturl"
@@ -12,6 +13,7 @@ import (
 	cloutpkg "github.com/tliron/go-puccini/clout"
 	"github.com/tliron/go-puccini/clout/js"
 	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/grammars/hot"
 	parserpkg "github.com/tliron/go-puccini/tosca/parser"
 	"github.com/tliron/go-puccini/tosca/parsing"
 	"github.com/tliron/go-transcribe"
@@ -22,11 +24,35 @@ var parser = parserpkg.NewParser()
 
 //export Compile
 func Compile(url *C.char, inputs *C.char, quirks *C.char, resolve C.char, coerce C.char) *C.char {
+	return compile(C.GoString(url), C.GoString(inputs), C.GoString(quirks), resolve != 0, coerce != 0, nil)
+}
+
+// Options are a YAML map, so that new ones can be supported without changing the C ABI:
+//
+//	revealHidden: do not mask hidden inputs (boolean)
+//	environments: Heat environment file URLs (list of strings)
+//	verifyArtifacts: read artifact content to verify declared checksums and compute SHA-256 digests (boolean)
+//	embedArtifacts: embed artifact content up to this size in bytes in the Clout, implies verifyArtifacts (integer)
+//
+//export CompileWithOptions
+func CompileWithOptions(url *C.char, inputs *C.char, quirks *C.char, resolve C.char, coerce C.char, options *C.char) *C.char {
+	options_, err := decodeOptions(C.GoString(options))
+	if err != nil {
+		return result(nil, nil, err)
+	}
+	return compile(C.GoString(url), C.GoString(inputs), C.GoString(quirks), resolve != 0, coerce != 0, options_)
+}
+
+func compile(url string, inputs string, quirks string, resolve bool, coerce bool, options *compileOptions) *C.char {
 	context := contextpkg.TODO()
 
+	if options == nil {
+		options = new(compileOptions)
+	}
+
 	inputs_ := make(map[string]ard.Value)
 
-	if data, err := yamlkeys.DecodeAll(strings.NewReader(C.GoString(inputs))); err == nil {
+	if data, err := yamlkeys.DecodeAll(strings.NewReader(inputs)); err == nil {
 		for _, data_ := range data {
 			if map_, ok := data_.(ard.Map); ok {
 				for key, value := range map_ {
@@ -42,7 +68,7 @@ func Compile(url *C.char, inputs *C.char, quirks *C.char, resolve C.char, coerce
 
 	var quirks_ parsing.Quirks
 
-	if data, err := yamlkeys.DecodeAll(strings.NewReader(C.GoString(quirks))); err == nil {
+	if data, err := yamlkeys.DecodeAll(strings.NewReader(quirks)); err == nil {
 		for _, data_ := range data {
 			if list, ok := data_.(ard.List); ok {
 				for _, value := range list {
@@ -65,14 +91,32 @@ func Compile(url *C.char, inputs *C.char, quirks *C.char, resolve C.char, coerce
 
 	var url_ exturl.URL
 	var err error
-	if url_, err = urlContext.NewValidAnyOrFileURL(context, C.GoString(url), nil); err != nil {
+	if url_, err = urlContext.NewValidAnyOrFileURL(context, url, nil); err != nil {
 		return result(nil, nil, err)
 	}
 
+	// Environment parameters can be overridden by inputs
+	environment := hot.NewEnvironment()
+	for _, environment_ := range options.environments {
+		var environmentUrl exturl.URL
+		if environmentUrl, err = urlContext.NewValidAnyOrFileURL(context, environment_, nil); err != nil {
+			return result(nil, nil, err)
+		}
+		if err = environment.Read(context, environmentUrl); err != nil {
+			return result(nil, nil, err)
+		}
+	}
+	for name, value := range inputs_ {
+		environment.Parameters[name] = value
+	}
+
 	parserContext := parser.NewContext()
+	defer parserContext.Repositories.Release()
 	parserContext.URL = url_
 	parserContext.Quirks = quirks_
-	parserContext.Inputs = inputs_
+	parserContext.Inputs = environment.Parameters
+	parserContext.InputDefaults = environment.ParameterDefaults
+	parserContext.TypeMappings = environment.ResourceRegistry
 	var normalServiceTemplate *normal.ServiceTemplate
 	if normalServiceTemplate, err = parserContext.Parse(context); err != nil {
 		return result(nil, parserContext.GetProblems(), err)
@@ -80,11 +124,24 @@ func Compile(url *C.char, inputs *C.char, quirks *C.char, resolve C.char, coerce
 
 	problems := parserContext.GetProblems()
 
+	if options.verifyArtifacts || (options.embedArtifacts > 0) {
+		normalServiceTemplate.ReadArtifactContents(context, urlContext, options.embedArtifacts, problems)
+		if !problems.Empty() {
+			return result(nil, problems, nil)
+		}
+	}
+
 	var clout *cloutpkg.Clout
 	if clout, err = normalServiceTemplate.Compile(); err != nil {
 		return result(clout, problems, err)
 	}
 
+	// Hidden inputs are masked in the result
+	var redactor *js.Redactor
+	if !options.revealHidden {
+		redactor = js.NewRedactor(clout)
+	}
+
 	execContext := js.ExecContext{
 		Clout:      clout,
 		Problems:   problems,
@@ -92,23 +149,98 @@ func Compile(url *C.char, inputs *C.char, quirks *C.char, resolve C.char, coerce
 		History:    true,
 		Format:     "yaml",
 		Strict:     true,
+
+		// Per call rather than shared by all calls, so that the cache does not grow
+		// without bound in long-running processes
+		ProgramCache: js.NewProgramCache(),
 	}
 
-	if resolve != 0 {
+	if resolve {
 		execContext.Resolve()
 		if !problems.Empty() {
-			return result(clout, problems, nil)
+			return result(redactor.RedactClout(clout), problems, nil)
 		}
 	}
 
-	if coerce != 0 {
+	if coerce {
 		execContext.Coerce()
 		if !problems.Empty() {
-			return result(clout, problems, nil)
+			return result(redactor.RedactClout(clout), problems, nil)
+		}
+	}
+
+	return result(redactor.RedactClout(clout), problems, nil)
+}
+
+//
+// compileOptions
+//
+
+type compileOptions struct {
+	revealHidden    bool
+	environments    []string
+	verifyArtifacts bool
+	embedArtifacts  int64
+}
+
+func decodeOptions(code string) (*compileOptions, error) {
+	var options compileOptions
+
+	data, err := yamlkeys.DecodeAll(strings.NewReader(code))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, data_ := range data {
+		map_, ok := data_.(ard.Map)
+		if !ok {
+			return nil, errors.New("malformed options")
+		}
+
+		for key, value := range map_ {
+			switch key_ := yamlkeys.KeyString(key); key_ {
+			case "revealHidden":
+				if options.revealHidden, ok = value.(bool); !ok {
+					return nil, errors.New("malformed \"revealHidden\" option")
+				}
+
+			case "environments":
+				if list, ok := value.(ard.List); ok {
+					for _, environment := range list {
+						if environment_, ok := environment.(string); ok {
+							options.environments = append(options.environments, environment_)
+						} else {
+							return nil, errors.New("malformed environment")
+						}
+					}
+				} else {
+					return nil, errors.New("malformed \"environments\" option")
+				}
+
+			case "verifyArtifacts":
+				if options.verifyArtifacts, ok = value.(bool); !ok {
+					return nil, errors.New("malformed \"verifyArtifacts\" option")
+				}
+
+			case "embedArtifacts":
+				switch value_ := value.(type) {
+				case int:
+					options.embedArtifacts = int64(value_)
+				case int64:
+					options.embedArtifacts = value_
+				case uint64:
+					options.embedArtifacts = int64(value_)
+				default:
+					return nil, errors.New("malformed \"embedArtifacts\" option")
+				}
+
+			default:
+				return nil, fmt.Errorf("unsupported option: %q", key_)
+			}
 		}
 	}
 
-	return result(clout, problems, nil)
+	return &options, nil
 }
 
 func result(clout *cloutpkg.Clout, problems *problems.Problems, err error) *C.char {
@@ -1,5 +1,23 @@
 package normal
 
+import (
+	"bytes"
+	contextpkg "context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tliron/exturl"
+)
+
 //
 // Artifact
 //
@@ -18,21 +36,140 @@ type Artifact struct {
 	ChecksumAlgorithm string      `json:"checksumAlgorithm" yaml:"checksumAlgorithm"`
 	Checksum          string      `json:"checksum" yaml:"checksum"`
 	Credential        Value       `json:"credential" yaml:"credential"`
+	SHA256            string      `json:"sha256" yaml:"sha256"`
+	Content           string      `json:"content" yaml:"content"`
+	ContentEncoding   string      `json:"contentEncoding" yaml:"contentEncoding"` // "utf-8" or "base64"
+	Location          *Location   `json:"location" yaml:"location"`
 }
 
-func (self *NodeTemplate) NewArtifact(name string) *Artifact {
+func (self *NodeTemplate) NewArtifact(name string, location *Location) *Artifact {
 	artifact := &Artifact{
 		NodeTemplate: self,
 		Name:         name,
 		Types:        make(EntityTypes),
 		Properties:   make(Values),
+		Location:     location,
 	}
 	self.Artifacts[name] = artifact
 	return artifact
 }
 
+// Reads the artifact's content in order to compute its SHA-256 digest and verify its declared
+// checksum. If there is no declared checksum, the SHA-256 digest will be used for it.
+//
+// Content up to embedLimit bytes is embedded, as is if it is valid UTF-8 and otherwise as base64,
+// so that the Clout can be self-contained. Set embedLimit to 0 to disable embedding.
+func (self *Artifact) ReadContent(context contextpkg.Context, urlContext *exturl.Context, embedLimit int64) error {
+	if self.SourcePath == "" {
+		return nil
+	}
+
+	var declaredHash hash.Hash
+	if self.Checksum != "" {
+		if self.ChecksumAlgorithm == "" {
+			self.ChecksumAlgorithm = "SHA-256"
+		}
+
+		var err error
+		if declaredHash, err = newChecksumHash(self.ChecksumAlgorithm); err != nil {
+			return err
+		}
+	}
+
+	url, err := urlContext.NewValidAnyOrFileURL(context, self.SourcePath, nil)
+	if err != nil {
+		return err
+	}
+
+	reader, err := url.Open(context)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	sha256Hash := sha256.New()
+	writers := []io.Writer{sha256Hash}
+	if declaredHash != nil {
+		writers = append(writers, declaredHash)
+	}
+	var content *limitedBuffer
+	if embedLimit > 0 {
+		content = &limitedBuffer{limit: embedLimit}
+		writers = append(writers, content)
+	}
+
+	if _, err := io.Copy(io.MultiWriter(writers...), reader); err != nil {
+		return err
+	}
+
+	self.SHA256 = hex.EncodeToString(sha256Hash.Sum(nil))
+
+	if declaredHash != nil {
+		if checksum := hex.EncodeToString(declaredHash.Sum(nil)); !strings.EqualFold(checksum, self.Checksum) {
+			return fmt.Errorf("checksum mismatch: declared %s is %q but content's is %q", self.ChecksumAlgorithm, self.Checksum, checksum)
+		}
+	} else {
+		self.ChecksumAlgorithm = "SHA-256"
+		self.Checksum = self.SHA256
+	}
+
+	if (content != nil) && !content.exceeded {
+		content_ := content.Bytes()
+		if utf8.Valid(content_) {
+			self.Content = string(content_)
+			self.ContentEncoding = "utf-8"
+		} else {
+			self.Content = base64.StdEncoding.EncodeToString(content_)
+			self.ContentEncoding = "base64"
+		}
+	}
+
+	return nil
+}
+
 //
 // Artifacts
 //
 
 type Artifacts map[string]*Artifact
+
+// Utils
+
+// Accepts variations such as "SHA-256", "sha256", and "SHA_256"
+func newChecksumHash(algorithm string) (hash.Hash, error) {
+	algorithm_ := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(algorithm))
+	switch algorithm_ {
+	case "sha256":
+		return sha256.New(), nil
+	case "md5":
+		return md5.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "sha384":
+		return sha512.New384(), nil
+	case "sha512":
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm: %q", algorithm)
+	}
+}
+
+// Stops buffering once the limit is exceeded, but never fails writing
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int64
+	exceeded bool
+}
+
+// ([io.Writer] interface)
+func (self *limitedBuffer) Write(p []byte) (int, error) {
+	if !self.exceeded {
+		if int64(self.Len()+len(p)) > self.limit {
+			self.exceeded = true
+			self.Reset()
+		} else {
+			self.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
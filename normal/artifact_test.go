@@ -0,0 +1,146 @@
+package normal
+
+import (
+	contextpkg "context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tliron/exturl"
+)
+
+func TestArtifactChecksum(t *testing.T) {
+	path := writeTestArtifact(t, "artifact.txt", []byte("hello"))
+
+	tests := []struct {
+		name      string
+		algorithm string
+		checksum  string
+		fail      bool
+	}{
+		{"default", "", getTestChecksum(sha256.New(), "hello"), false},
+		{"SHA-256", "SHA-256", getTestChecksum(sha256.New(), "hello"), false},
+		{"upper case checksum", "SHA-256", strings.ToUpper(getTestChecksum(sha256.New(), "hello")), false},
+		{"sha256", "sha256", getTestChecksum(sha256.New(), "hello"), false},
+		{"SHA_256", "SHA_256", getTestChecksum(sha256.New(), "hello"), false},
+		{"MD5", "MD5", getTestChecksum(md5.New(), "hello"), false},
+		{"sha-1", "sha-1", getTestChecksum(sha1.New(), "hello"), false},
+		{"SHA384", "SHA384", getTestChecksum(sha512.New384(), "hello"), false},
+		{"SHA-512", "SHA-512", getTestChecksum(sha512.New(), "hello"), false},
+		{"mismatch", "SHA-256", getTestChecksum(sha256.New(), "goodbye"), true},
+		{"wrong algorithm", "MD5", getTestChecksum(sha256.New(), "hello"), true},
+		{"unsupported algorithm", "CRC32", "00000000", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			artifact := &Artifact{SourcePath: path, ChecksumAlgorithm: test.algorithm, Checksum: test.checksum}
+			err := readTestArtifact(artifact, 0)
+			if test.fail {
+				if err == nil {
+					t.Errorf("expected failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s", err.Error())
+			}
+			if artifact.SHA256 != getTestChecksum(sha256.New(), "hello") {
+				t.Errorf("wrong SHA-256: %q", artifact.SHA256)
+			}
+		})
+	}
+}
+
+func TestArtifactNoChecksum(t *testing.T) {
+	artifact := &Artifact{SourcePath: writeTestArtifact(t, "artifact.txt", []byte("hello"))}
+	if err := readTestArtifact(artifact, 0); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	// The SHA-256 digest becomes the checksum
+	checksum := getTestChecksum(sha256.New(), "hello")
+	if (artifact.ChecksumAlgorithm != "SHA-256") || (artifact.Checksum != checksum) || (artifact.SHA256 != checksum) {
+		t.Errorf("wrong checksum: %s %q", artifact.ChecksumAlgorithm, artifact.Checksum)
+	}
+	if artifact.Content != "" {
+		t.Errorf("embedded without embed limit")
+	}
+}
+
+func TestArtifactEmbed(t *testing.T) {
+	binary := []byte{0xff, 0xfe, 0x00, 0x01}
+
+	tests := []struct {
+		name     string
+		content  []byte
+		limit    int64
+		expected string
+		encoding string
+	}{
+		{"UTF-8", []byte("héllo"), 100, "héllo", "utf-8"},
+		{"binary", binary, 100, base64.StdEncoding.EncodeToString(binary), "base64"},
+		{"at limit", []byte("hello"), 5, "hello", "utf-8"},
+		{"over limit", []byte("hello"), 4, "", ""},
+		{"disabled", []byte("hello"), 0, "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			artifact := &Artifact{SourcePath: writeTestArtifact(t, "artifact", test.content)}
+			if err := readTestArtifact(artifact, test.limit); err != nil {
+				t.Fatalf("%s", err.Error())
+			}
+			if (artifact.Content != test.expected) || (artifact.ContentEncoding != test.encoding) {
+				t.Errorf("expected %q (%s), got %q (%s)", test.expected, test.encoding, artifact.Content, artifact.ContentEncoding)
+			}
+
+			// The digest covers all the content, even if it is not embedded
+			if artifact.SHA256 != getTestChecksum(sha256.New(), string(test.content)) {
+				t.Errorf("wrong SHA-256: %q", artifact.SHA256)
+			}
+		})
+	}
+}
+
+func TestArtifactMissing(t *testing.T) {
+	artifact := &Artifact{SourcePath: filepath.Join(t.TempDir(), "missing")}
+	if err := readTestArtifact(artifact, 0); err == nil {
+		t.Errorf("read missing artifact")
+	}
+
+	// No source path is not an error
+	if err := readTestArtifact(new(Artifact), 0); err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+// Utils
+
+func readTestArtifact(artifact *Artifact, embedLimit int64) error {
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	return artifact.ReadContent(contextpkg.Background(), urlContext, embedLimit)
+}
+
+func writeTestArtifact(t *testing.T, name string, content []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	return path
+}
+
+func getTestChecksum(hash hash.Hash, content string) string {
+	hash.Write([]byte(content))
+	return hex.EncodeToString(hash.Sum(nil))
+}
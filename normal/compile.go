@@ -46,6 +46,10 @@ func (serviceTemplate *ServiceTemplate) Compile() (*cloutpkg.Clout, error) {
 	}}
 	clout.Metadata["history"] = history
 
+	if len(serviceTemplate.InputGroups) > 0 {
+		clout.Metadata["inputGroups"] = serviceTemplate.InputGroups
+	}
+
 	tosca := make(ard.StringMap)
 	tosca["description"] = serviceTemplate.Description
 	if serviceTemplate.Metadata != nil {
@@ -55,6 +59,12 @@ func (serviceTemplate *ServiceTemplate) Compile() (*cloutpkg.Clout, error) {
 	}
 	tosca["inputs"] = serviceTemplate.Inputs
 	tosca["outputs"] = serviceTemplate.Outputs
+	if len(serviceTemplate.Capabilities) > 0 {
+		tosca["capabilities"] = serviceTemplate.Capabilities
+	}
+	if serviceTemplate.UploadResources != nil {
+		tosca["uploadResources"] = serviceTemplate.UploadResources
+	}
 	clout.Properties["tosca"] = tosca
 
 	nodeTemplates := make(map[string]*cloutpkg.Vertex)
@@ -123,6 +133,63 @@ func (serviceTemplate *ServiceTemplate) Compile() (*cloutpkg.Clout, error) {
 		SetMetadata(vertex, "Workflow")
 		vertex.Properties["name"] = workflow.Name
 		vertex.Properties["description"] = workflow.Description
+		if workflow.Metadata != nil {
+			vertex.Properties["metadata"] = workflow.Metadata
+		} else {
+			vertex.Properties["metadata"] = emptyMap
+		}
+		vertex.Properties["implementation"] = workflow.Implementation
+		vertex.Properties["inputs"] = workflow.Inputs
+	}
+
+	// Plugins
+	for _, plugin := range serviceTemplate.Plugins {
+		vertex := clout.NewVertex(cloutpkg.NewKey())
+
+		SetMetadata(vertex, "Plugin")
+		vertex.Properties["name"] = plugin.Name
+		vertex.Properties["properties"] = plugin.Properties
+
+		for _, nodeTemplate := range plugin.NodeTemplates {
+			nodeTemplateVertex := nodeTemplates[nodeTemplate.Name]
+			edge := vertex.NewEdgeTo(nodeTemplateVertex)
+
+			SetMetadata(edge, "PluginUser")
+		}
+
+		for _, workflow := range plugin.Workflows {
+			workflowVertex := workflows[workflow.Name]
+			edge := vertex.NewEdgeTo(workflowVertex)
+
+			SetMetadata(edge, "PluginUser")
+		}
+	}
+
+	// Workflow preconditions
+	for name, workflow := range serviceTemplate.Workflows {
+		vertex := workflows[name]
+
+		for sequence, precondition := range workflow.Preconditions {
+			preconditionVertex := clout.NewVertex(cloutpkg.NewKey())
+
+			SetMetadata(preconditionVertex, "WorkflowPrecondition")
+			preconditionVertex.Properties["targetRelationship"] = precondition.TargetRelationship
+			preconditionVertex.Properties["condition"] = precondition.Condition
+
+			edge := vertex.NewEdgeTo(preconditionVertex)
+			SetMetadata(edge, "WorkflowPrecondition")
+			edge.Properties["sequence"] = sequence
+
+			if precondition.TargetNodeTemplate != nil {
+				nodeTemplateVertex := nodeTemplates[precondition.TargetNodeTemplate.Name]
+				edge = preconditionVertex.NewEdgeTo(nodeTemplateVertex)
+				SetMetadata(edge, "NodeTemplateTarget")
+			} else if precondition.TargetGroup != nil {
+				groupVertex := groups[precondition.TargetGroup.Name]
+				edge = preconditionVertex.NewEdgeTo(groupVertex)
+				SetMetadata(edge, "GroupTarget")
+			}
+		}
 	}
 
 	// Workflow steps
@@ -138,6 +205,9 @@ func (serviceTemplate *ServiceTemplate) Compile() (*cloutpkg.Clout, error) {
 
 			SetMetadata(stepVertex, "WorkflowStep")
 			stepVertex.Properties["name"] = step.Name
+			stepVertex.Properties["targetRelationship"] = step.TargetRelationship
+			stepVertex.Properties["filter"] = step.Filter
+			stepVertex.Properties["host"] = step.Host
 
 			edge := vertex.NewEdgeTo(stepVertex)
 			SetMetadata(edge, "WorkflowStep")
@@ -165,10 +235,12 @@ func (serviceTemplate *ServiceTemplate) Compile() (*cloutpkg.Clout, error) {
 
 				SetMetadata(activityVertex, "WorkflowActivity")
 				if activity.DelegateWorkflow != nil {
+					activityVertex.Properties["delegate"] = activity.DelegateWorkflow.Name
 					workflowVertex := workflows[activity.DelegateWorkflow.Name]
 					edge = activityVertex.NewEdgeTo(workflowVertex)
 					SetMetadata(edge, "DelegateWorkflow")
 				} else if activity.InlineWorkflow != nil {
+					activityVertex.Properties["inline"] = activity.InlineWorkflow.Name
 					workflowVertex := workflows[activity.InlineWorkflow.Name]
 					edge = activityVertex.NewEdgeTo(workflowVertex)
 					SetMetadata(edge, "InlineWorkflow")
@@ -232,7 +304,64 @@ func (serviceTemplate *ServiceTemplate) Compile() (*cloutpkg.Clout, error) {
 			SetMetadata(edge, "GroupTarget")
 		}
 
-		for _, trigger := range policy.Triggers {
+		for sequence, trigger := range policy.Triggers {
+			triggerVertex := clout.NewVertex(cloutpkg.NewKey())
+
+			SetMetadata(triggerVertex, "PolicyTrigger")
+			triggerVertex.Properties["name"] = trigger.Name
+			triggerVertex.Properties["description"] = trigger.Description
+			triggerVertex.Properties["event"] = trigger.Event
+			triggerVertex.Properties["condition"] = trigger.Condition
+			triggerVertex.Properties["types"] = trigger.Types
+			triggerVertex.Properties["parameters"] = trigger.Parameters
+
+			edge := vertex.NewEdgeTo(triggerVertex)
+			SetMetadata(edge, "PolicyTrigger")
+			edge.Properties["sequence"] = sequence
+
+			// Policy trigger actions
+			for sequence, action := range trigger.Actions {
+				actionVertex := clout.NewVertex(cloutpkg.NewKey())
+
+				edge = triggerVertex.NewEdgeTo(actionVertex)
+				SetMetadata(edge, "PolicyTriggerAction")
+				edge.Properties["sequence"] = sequence
+
+				SetMetadata(actionVertex, "PolicyTriggerAction")
+				if action.DelegateWorkflow != nil {
+					actionVertex.Properties["delegate"] = action.DelegateWorkflow.Name
+					workflowVertex := workflows[action.DelegateWorkflow.Name]
+					edge = actionVertex.NewEdgeTo(workflowVertex)
+					SetMetadata(edge, "DelegateWorkflow")
+				} else if action.InlineWorkflow != nil {
+					actionVertex.Properties["inline"] = action.InlineWorkflow.Name
+					workflowVertex := workflows[action.InlineWorkflow.Name]
+					edge = actionVertex.NewEdgeTo(workflowVertex)
+					SetMetadata(edge, "InlineWorkflow")
+				} else if action.SetNodeState != "" {
+					actionVertex.Properties["setNodeState"] = action.SetNodeState
+				} else if action.CallOperation != nil {
+					actionVertex.Properties["callOperation"] = ard.StringMap{
+						"interface": action.CallOperation.Interface,
+						"operation": action.CallOperation.Operation,
+						"inputs":    action.CallOperation.Inputs,
+					}
+				} else if action.Update != nil {
+					actionVertex.Properties["update"] = action.Update
+				}
+
+				for _, nodeTemplate := range action.NodeTemplateTargets {
+					edge = actionVertex.NewEdgeTo(nodeTemplates[nodeTemplate.Name])
+					SetMetadata(edge, "NodeTemplateTarget")
+				}
+
+				for _, group := range action.GroupTargets {
+					edge = actionVertex.NewEdgeTo(groups[group.Name])
+					SetMetadata(edge, "GroupTarget")
+				}
+			}
+
+			// Legacy
 			if trigger.Operation != nil {
 				toVertex := clout.NewVertex(cloutpkg.NewKey())
 
@@ -0,0 +1,26 @@
+package normal
+
+//
+// Condition
+//
+
+// Either an operator ("and", "or", or "not") applied to child conditions, or an assertion,
+// which is a list of validators. If Attribute is set then the validators apply to that
+// attribute of the target. Otherwise (TOSCA 2.0 boolean expressions) they apply to all the
+// attributes of the target as a map.
+type Condition struct {
+	Operator   string        `json:"operator,omitempty" yaml:"operator,omitempty"`
+	Conditions Conditions    `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Attribute  string        `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	Validators FunctionCalls `json:"validators,omitempty" yaml:"validators,omitempty"`
+}
+
+func NewCondition() *Condition {
+	return new(Condition)
+}
+
+//
+// Conditions
+//
+
+type Conditions []*Condition
@@ -29,6 +29,11 @@ func (self *FunctionCall) SetMeta(valueMeta *ValueMeta) {
 	self.ValueMeta = CopyValueMeta(valueMeta)
 }
 
+// Value interface
+func (self *FunctionCall) GetMeta() *ValueMeta {
+	return self.ValueMeta
+}
+
 //
 // FunctionCalls
 //
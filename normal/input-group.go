@@ -0,0 +1,25 @@
+package normal
+
+//
+// InputGroup
+//
+
+type InputGroup struct {
+	Label       string   `json:"label" yaml:"label"`
+	Description string   `json:"description" yaml:"description"`
+	Inputs      []string `json:"inputs" yaml:"inputs"` // in order
+}
+
+func (self *ServiceTemplate) NewInputGroup() *InputGroup {
+	inputGroup := &InputGroup{
+		Inputs: make([]string, 0),
+	}
+	self.InputGroups = append(self.InputGroups, inputGroup)
+	return inputGroup
+}
+
+//
+// InputGroups
+//
+
+type InputGroups []*InputGroup
@@ -25,6 +25,11 @@ func (self *List) SetMeta(valueMeta *ValueMeta) {
 	self.ValueMeta = CopyValueMeta(valueMeta)
 }
 
+// Value interface
+func (self *List) GetMeta() *ValueMeta {
+	return self.ValueMeta
+}
+
 func (self *List) Set(index int, value Value) {
 	self.Entries[index] = value
 }
@@ -25,6 +25,11 @@ func (self *Map) SetMeta(valueMeta *ValueMeta) {
 	self.ValueMeta = CopyValueMeta(valueMeta)
 }
 
+// Value interface
+func (self *Map) GetMeta() *ValueMeta {
+	return self.ValueMeta
+}
+
 func (self *Map) Put(key any, value Value) {
 	self.Entries = self.Entries.AppendWithKey(key, value)
 }
@@ -9,23 +9,27 @@ type Operation struct {
 	PolicyTrigger *PolicyTrigger `json:"-" yaml:"-"`
 	Name          string         `json:"-" yaml:"-"`
 
-	Description    string   `json:"description" yaml:"description"`
-	Implementation string   `json:"implementation" yaml:"implementation"`
-	Dependencies   []string `json:"dependencies" yaml:"dependencies"`
-	Inputs         Values   `json:"inputs" yaml:"inputs"`
-	Outputs        Values   `json:"outputs" yaml:"outputs"`
-	Timeout        int64    `json:"timeout" yaml:"timeout"`
-	Host           string   `json:"host,omitempty" yaml:"host,omitempty"`
+	Description       string               `json:"description" yaml:"description"`
+	Implementation    string               `json:"implementation" yaml:"implementation"`
+	Dependencies      []string             `json:"dependencies" yaml:"dependencies"`
+	Inputs            Values               `json:"inputs" yaml:"inputs"`
+	Outputs           Values               `json:"outputs" yaml:"outputs"`
+	InputDefinitions  ParameterDefinitions `json:"inputDefinitions" yaml:"inputDefinitions"`
+	OutputDefinitions ParameterDefinitions `json:"outputDefinitions" yaml:"outputDefinitions"`
+	Timeout           int64                `json:"timeout" yaml:"timeout"`
+	Host              string               `json:"host,omitempty" yaml:"host,omitempty"`
 }
 
 func (self *Interface) NewOperation(name string) *Operation {
 	operation := &Operation{
-		Interface:    self,
-		Name:         name,
-		Dependencies: make([]string, 0),
-		Inputs:       make(Values),
-		Outputs:      make(Values),
-		Timeout:      -1,
+		Interface:         self,
+		Name:              name,
+		Dependencies:      make([]string, 0),
+		Inputs:            make(Values),
+		Outputs:           make(Values),
+		InputDefinitions:  make(ParameterDefinitions),
+		OutputDefinitions: make(ParameterDefinitions),
+		Timeout:           -1,
 	}
 	self.Operations[name] = operation
 	return operation
@@ -33,10 +37,12 @@ func (self *Interface) NewOperation(name string) *Operation {
 
 func (self *PolicyTrigger) NewOperation() *Operation {
 	self.Operation = &Operation{
-		PolicyTrigger: self,
-		Dependencies:  make([]string, 0),
-		Inputs:        make(Values),
-		Outputs:       make(Values),
+		PolicyTrigger:     self,
+		Dependencies:      make([]string, 0),
+		Inputs:            make(Values),
+		Outputs:           make(Values),
+		InputDefinitions:  make(ParameterDefinitions),
+		OutputDefinitions: make(ParameterDefinitions),
 	}
 	return self.Operation
 }
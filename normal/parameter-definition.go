@@ -0,0 +1,25 @@
+package normal
+
+//
+// ParameterDefinition
+//
+
+// The declaration of an operation input or output. Meta includes the type and validators.
+// Mapping, if set, is the attribute path (starting with the entity name) that the parameter
+// is mapped to.
+type ParameterDefinition struct {
+	Meta     *ValueMeta `json:"meta,omitempty" yaml:"meta,omitempty"`
+	Required bool       `json:"required" yaml:"required"`
+	Default  Value      `json:"default,omitempty" yaml:"default,omitempty"`
+	Mapping  []string   `json:"mapping,omitempty" yaml:"mapping,omitempty"`
+}
+
+func NewParameterDefinition() *ParameterDefinition {
+	return new(ParameterDefinition)
+}
+
+//
+// ParameterDefinitions
+//
+
+type ParameterDefinitions map[string]*ParameterDefinition
@@ -0,0 +1,62 @@
+package normal
+
+//
+// Plugin
+//
+
+type Plugin struct {
+	ServiceTemplate *ServiceTemplate `json:"-" yaml:"-"`
+	Name            string           `json:"-" yaml:"-"`
+
+	Properties Values `json:"properties" yaml:"properties"`
+
+	NodeTemplates []*NodeTemplate `json:"-" yaml:"-"`
+	Workflows     []*Workflow     `json:"-" yaml:"-"`
+}
+
+func (self *ServiceTemplate) NewPlugin(name string) *Plugin {
+	plugin := &Plugin{
+		ServiceTemplate: self,
+		Name:            name,
+		Properties:      make(Values),
+		NodeTemplates:   make([]*NodeTemplate, 0),
+		Workflows:       make([]*Workflow, 0),
+	}
+	self.Plugins[name] = plugin
+	return plugin
+}
+
+//
+// Plugins
+//
+
+type Plugins map[string]*Plugin
+
+//
+// UploadResources
+//
+
+type UploadResources struct {
+	PluginResources []string       `json:"pluginResources" yaml:"pluginResources"`
+	DSLResources    []*DSLResource `json:"dslResources" yaml:"dslResources"`
+	Parameters      Values         `json:"parameters" yaml:"parameters"`
+}
+
+func (self *ServiceTemplate) NewUploadResources() *UploadResources {
+	uploadResources := &UploadResources{
+		PluginResources: make([]string, 0),
+		DSLResources:    make([]*DSLResource, 0),
+		Parameters:      make(Values),
+	}
+	self.UploadResources = uploadResources
+	return uploadResources
+}
+
+//
+// DSLResource
+//
+
+type DSLResource struct {
+	SourcePath      string `json:"sourcePath" yaml:"sourcePath"`
+	DestinationPath string `json:"destinationPath" yaml:"destinationPath"`
+}
@@ -44,23 +44,71 @@ type Policies map[string]*Policy
 //
 
 type PolicyTrigger struct {
-	Policy *Policy `json:"-" yaml:"-"`
+	Policy  *Policy                `json:"-" yaml:"-"`
+	Name    string                 `json:"-" yaml:"-"`
+	Actions []*PolicyTriggerAction `json:"-" yaml:"-"`
 
 	// TOSCA 2.0 specification fields
 	Description string        `json:"description" yaml:"description"`
 	Event       string        `json:"event" yaml:"event"`
 	Condition   *FunctionCall `json:"condition" yaml:"condition"`
 
+	// Cloudify DSL fields
+	Types      EntityTypes `json:"types,omitempty" yaml:"types,omitempty"`
+	Parameters Values      `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+
 	// Legacy fields for backward compatibility
 	EventType string     `json:"eventType" yaml:"eventType"`
 	Operation *Operation `json:"operation" yaml:"operation"`
 	Workflow  *Workflow  `json:"workflow" yaml:"workflow"`
 }
 
-func (self *Policy) NewTrigger() *PolicyTrigger {
+func (self *Policy) NewTrigger(name string) *PolicyTrigger {
 	trigger := &PolicyTrigger{
-		Policy: self,
+		Policy:     self,
+		Name:       name,
+		Actions:    make([]*PolicyTriggerAction, 0),
+		Types:      make(EntityTypes),
+		Parameters: make(Values),
 	}
 	self.Triggers = append(self.Triggers, trigger)
 	return trigger
 }
+
+//
+// PolicyTriggerAction
+//
+
+type PolicyTriggerAction struct {
+	Trigger             *PolicyTrigger  `json:"-" yaml:"-"`
+	NodeTemplateTargets []*NodeTemplate `json:"-" yaml:"-"`
+	GroupTargets        []*Group        `json:"-" yaml:"-"`
+	DelegateWorkflow    *Workflow       `json:"-" yaml:"-"`
+	InlineWorkflow      *Workflow       `json:"-" yaml:"-"`
+
+	SetNodeState  string                      `json:"setNodeState,omitempty" yaml:"setNodeState,omitempty"`
+	CallOperation *PolicyTriggerCallOperation `json:"callOperation,omitempty" yaml:"callOperation,omitempty"`
+	Update        Values                      `json:"update,omitempty" yaml:"update,omitempty"`
+}
+
+func (self *PolicyTrigger) NewAction() *PolicyTriggerAction {
+	action := &PolicyTriggerAction{
+		Trigger:             self,
+		NodeTemplateTargets: make([]*NodeTemplate, 0),
+		GroupTargets:        make([]*Group, 0),
+	}
+	self.Actions = append(self.Actions, action)
+	return action
+}
+
+//
+// PolicyTriggerCallOperation
+//
+
+// Unlike workflow activities, a trigger action can target several node templates and groups,
+// so the operation is identified by name rather than by a specific interface instance.
+type PolicyTriggerCallOperation struct {
+	Interface string `json:"interface" yaml:"interface"`
+	Operation string `json:"operation" yaml:"operation"`
+	Inputs    Values `json:"inputs" yaml:"inputs"`
+}
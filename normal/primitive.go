@@ -28,3 +28,8 @@ func (self *Primitive) SetKey(key Value) {
 func (self *Primitive) SetMeta(valueMeta *ValueMeta) {
 	self.ValueMeta = CopyValueMeta(valueMeta)
 }
+
+// Value interface
+func (self *Primitive) GetMeta() *ValueMeta {
+	return self.ValueMeta
+}
@@ -24,6 +24,7 @@ type Requirement struct {
 	Relationship                   *Relationship
 	Directives                     []string
 	Optional                       bool
+	Allocation                     Values
 	Location                       *Location
 }
 
@@ -50,6 +51,7 @@ type MarshalableRequirement struct {
 	Relationship                   *Relationship      `json:"relationship" yaml:"relationship"`
 	Directives                     []string           `json:"directives" yaml:"directives"`
 	Optional                       bool               `json:"optional" yaml:"optional"`
+	Allocation                     Values             `json:"allocation" yaml:"allocation"`
 	Location                       *Location          `json:"location" yaml:"location"`
 }
 
@@ -82,6 +84,7 @@ func (self *Requirement) Marshalable() any {
 		Relationship:                   self.Relationship,
 		Directives:                     self.Directives,
 		Optional:                       self.Optional,
+		Allocation:                     self.Allocation,
 		Location:                       self.Location,
 	}
 }
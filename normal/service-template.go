@@ -1,6 +1,10 @@
 package normal
 
 import (
+	contextpkg "context"
+
+	"github.com/tliron/exturl"
+	problemspkg "github.com/tliron/go-kutil/problems"
 	"github.com/tliron/go-kutil/reflection"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
@@ -15,7 +19,11 @@ type ServiceTemplate struct {
 	Groups             Groups                      `json:"groups" yaml:"groups"`
 	Policies           Policies                    `json:"policies" yaml:"policies"`
 	Inputs             Values                      `json:"inputs" yaml:"inputs"`
+	InputGroups        InputGroups                 `json:"inputGroups" yaml:"inputGroups"`
 	Outputs            Values                      `json:"outputs" yaml:"outputs"`
+	Capabilities       Values                      `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`       // Cloudify DSL
+	Plugins            Plugins                     `json:"plugins,omitempty" yaml:"plugins,omitempty"`                 // Cloudify DSL
+	UploadResources    *UploadResources            `json:"uploadResources,omitempty" yaml:"uploadResources,omitempty"` // Cloudify DSL
 	Workflows          Workflows                   `json:"workflows" yaml:"workflows"`
 	Substitution       *Substitution               `json:"substitution" yaml:"substitution"`
 	Metadata           map[string]string           `json:"metadata" yaml:"metadata"`
@@ -28,13 +36,31 @@ func NewServiceTemplate() *ServiceTemplate {
 		Groups:             make(Groups),
 		Policies:           make(Policies),
 		Inputs:             make(Values),
+		InputGroups:        make(InputGroups, 0),
 		Outputs:            make(Values),
+		Capabilities:       make(Values),
+		Plugins:            make(Plugins),
 		Workflows:          make(Workflows),
 		Metadata:           make(map[string]string),
 		ScriptletNamespace: parsing.NewScriptletNamespace(),
 	}
 }
 
+// See [Artifact.ReadContent]. Failures are reported as problems.
+func (self *ServiceTemplate) ReadArtifactContents(context contextpkg.Context, urlContext *exturl.Context, embedLimit int64, problems *problemspkg.Problems) {
+	for _, nodeTemplate := range self.NodeTemplates {
+		for _, artifact := range nodeTemplate.Artifacts {
+			if err := artifact.ReadContent(context, urlContext, embedLimit); err != nil {
+				if artifact.Location != nil {
+					problems.ReportFull(1, "Artifacts", artifact.Location.Path, err.Error(), artifact.Location.Row, artifact.Location.Column)
+				} else {
+					problems.ReportFull(1, "Artifacts", "", err.Error(), -1, -1)
+				}
+			}
+		}
+	}
+}
+
 //
 // Normalizable
 //
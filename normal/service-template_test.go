@@ -0,0 +1,54 @@
+package normal
+
+import (
+	contextpkg "context"
+	"crypto/sha256"
+	"path/filepath"
+	"testing"
+
+	"github.com/tliron/exturl"
+	problemspkg "github.com/tliron/go-kutil/problems"
+)
+
+func TestReadArtifactContents(t *testing.T) {
+	serviceTemplate := NewServiceTemplate()
+	nodeTemplate := serviceTemplate.NewNodeTemplate("server")
+
+	good := nodeTemplate.NewArtifact("good", NewLocation("/template.yaml", 10, 7))
+	good.SourcePath = writeTestArtifact(t, "good.txt", []byte("hello"))
+
+	bad := nodeTemplate.NewArtifact("bad", NewLocation("/template.yaml", 20, 7))
+	bad.SourcePath = writeTestArtifact(t, "bad.txt", []byte("hello"))
+	bad.Checksum = getTestChecksum(sha256.New(), "goodbye")
+
+	missing := nodeTemplate.NewArtifact("missing", nil)
+	missing.SourcePath = filepath.Join(t.TempDir(), "missing.txt")
+
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	problems := problemspkg.NewProblems(nil)
+	serviceTemplate.ReadArtifactContents(contextpkg.Background(), urlContext, 100, problems)
+
+	// Failures do not stop other artifacts from being read
+	if (good.Content != "hello") || (good.Checksum != getTestChecksum(sha256.New(), "hello")) {
+		t.Errorf("artifact not read: %q", good.Content)
+	}
+
+	problems_ := problems.Slice()
+	if len(problems_) != 2 {
+		t.Fatalf("expected 2 problems, got %d: %s", len(problems_), problems.String())
+	}
+	for _, problem := range problems_ {
+		if problem.Section != "Artifacts" {
+			t.Errorf("wrong section: %q", problem.Section)
+		}
+		if problem.Row == 20 {
+			if problem.Item != "/template.yaml" {
+				t.Errorf("wrong item: %q", problem.Item)
+			}
+		} else if problem.Row != -1 {
+			t.Errorf("wrong row: %d", problem.Row)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package normal
 type Value interface {
 	SetKey(Value)
 	SetMeta(*ValueMeta)
+	GetMeta() *ValueMeta
 }
 
 //
@@ -8,11 +8,12 @@ type Workflow struct {
 	ServiceTemplate *ServiceTemplate `json:"-" yaml:"-"`
 	Name            string           `json:"-" yaml:"-"`
 
-	Metadata      map[string]string       `json:"metadata" yaml:"metadata"`
-	Description   string                  `json:"description" yaml:"description"`
-	Preconditions []*WorkflowPrecondition `json:"preconditions" yaml:"preconditions"`
-	Steps         WorkflowSteps           `json:"steps" yaml:"steps"`
-	Inputs        Values                  `json:"inputs" yaml:"inputs"`
+	Metadata       map[string]string       `json:"metadata" yaml:"metadata"`
+	Description    string                  `json:"description" yaml:"description"`
+	Implementation string                  `json:"implementation" yaml:"implementation"`
+	Preconditions  []*WorkflowPrecondition `json:"preconditions" yaml:"preconditions"`
+	Steps          WorkflowSteps           `json:"steps" yaml:"steps"`
+	Inputs         Values                  `json:"inputs" yaml:"inputs"`
 }
 
 func (self *ServiceTemplate) NewWorkflow(name string) *Workflow {
@@ -39,9 +40,17 @@ type Workflows map[string]*Workflow
 //
 
 type WorkflowPrecondition struct {
+	Workflow           *Workflow     `json:"-" yaml:"-"`
 	TargetNodeTemplate *NodeTemplate `json:"-" yaml:"-"`
 	TargetGroup        *Group        `json:"-" yaml:"-"`
-	// TODO: Conditions
+	TargetRelationship string        `json:"targetRelationship,omitempty" yaml:"targetRelationship,omitempty"`
+	Condition          *Condition    `json:"condition,omitempty" yaml:"condition,omitempty"`
+}
+
+func (self *Workflow) NewPrecondition() *WorkflowPrecondition {
+	precondition := &WorkflowPrecondition{Workflow: self}
+	self.Preconditions = append(self.Preconditions, precondition)
+	return precondition
 }
 
 //
@@ -51,15 +60,16 @@ type WorkflowPrecondition struct {
 // TODO: JSON/YAML marshalling
 
 type WorkflowStep struct {
-	Workflow           *Workflow     `json:"-" yaml:"-"`
-	Name               string        `json:"-" yaml:"-"`
-	TargetNodeTemplate *NodeTemplate `json:"-" yaml:"-"`
-	TargetGroup        *Group        `json:"-" yaml:"-"`
-	// TODO: Filters
-	OnSuccessSteps []*WorkflowStep     `json:"-" yaml:"-"`
-	OnFailureSteps []*WorkflowStep     `json:"-" yaml:"-"`
-	Activities     []*WorkflowActivity `json:"-" yaml:"-"`
-	Host           string              `json:"-" yaml:"-"`
+	Workflow           *Workflow           `json:"-" yaml:"-"`
+	Name               string              `json:"-" yaml:"-"`
+	TargetNodeTemplate *NodeTemplate       `json:"-" yaml:"-"`
+	TargetGroup        *Group              `json:"-" yaml:"-"`
+	TargetRelationship string              `json:"-" yaml:"-"`
+	Filter             *Condition          `json:"-" yaml:"-"`
+	OnSuccessSteps     []*WorkflowStep     `json:"-" yaml:"-"`
+	OnFailureSteps     []*WorkflowStep     `json:"-" yaml:"-"`
+	Activities         []*WorkflowActivity `json:"-" yaml:"-"`
+	Host               string              `json:"-" yaml:"-"`
 }
 
 func (self *Workflow) NewStep(name string) *WorkflowStep {
@@ -10,10 +10,12 @@ import (
 	"testing"
 
 	"github.com/tliron/exturl"
+	"github.com/tliron/go-ard"
 	cloutpkg "github.com/tliron/go-puccini/clout"
 	"github.com/tliron/go-puccini/clout/js"
 	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parser"
+	"github.com/tliron/go-puccini/tosca/parsing"
 
 	_ "github.com/tliron/commonlog/simple"
 )
@@ -60,7 +62,7 @@ func TestHighConcurrency(t *testing.T) {
 					t.Parallel()
 					context := NewContext(t)
 					defer context.urlContext.Release()
-					context.compile_(t, tf.url, tf.inputs)
+					context.compile_(t, tf.url, tf.inputs, nil)
 				})
 			}
 		})
@@ -75,6 +77,246 @@ func TestJavaScriptErrors(t *testing.T) {
 	context.compileFailure("javascript/err-infinite-loop-v2.yaml", nil)
 }
 
+func TestTrace(t *testing.T) {
+	context := NewContext(t)
+	defer context.urlContext.Release()
+
+	url := context.urlContext.NewFileURL(path.Join(filepath.ToSlash(context.root), "examples", "1.3/functions.yaml"))
+
+	parserContext := context.parser.NewContext()
+	parserContext.URL = url
+	normalServiceTemplate, err := parserContext.Parse(contextpkg.TODO())
+	if err != nil {
+		t.Fatalf("%s\n%s", err.Error(), parserContext.GetProblems().ToString(true))
+	}
+
+	clout, err := normalServiceTemplate.Compile()
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	problems := parserContext.GetProblems()
+	execContext := js.ExecContext{
+		Clout:      clout,
+		Problems:   problems,
+		URLContext: context.urlContext,
+		Format:     "yaml",
+		Trace:      js.NewTrace(),
+	}
+
+	execContext.Resolve()
+	execContext.Coerce()
+	if !problems.Empty() {
+		t.Fatalf("%s", problems.ToString(true))
+	}
+
+	var nested bool
+	for _, record := range execContext.Trace.Records {
+		if record.Scriptlet == "" || record.URL == "" {
+			t.Errorf("incomplete trace record: %+v", record)
+		}
+		if len(record.Children) > 0 {
+			nested = true
+		}
+	}
+
+	if len(execContext.Trace.Records) == 0 {
+		t.Errorf("no trace records")
+	} else if !nested {
+		t.Errorf("no nested trace records")
+	}
+}
+
+func TestProgramCache(t *testing.T) {
+	context := NewContext(t)
+	defer context.urlContext.Release()
+
+	programCache := js.NewProgramCache()
+
+	// The same scriptlets should be compiled only once, even for different Clouts
+	var compilations int64
+	for _, test := range []struct {
+		url    string
+		cached bool
+	}{
+		{"1.3/functions.yaml", false},
+		{"1.3/functions.yaml", true},
+		{"javascript/modules.yaml", false},
+		{"javascript/modules.yaml", true},
+	} {
+		url := context.urlContext.NewFileURL(path.Join(filepath.ToSlash(context.root), "examples", test.url))
+
+		parserContext := context.parser.NewContext()
+		parserContext.URL = url
+		normalServiceTemplate, err := parserContext.Parse(contextpkg.TODO())
+		if err != nil {
+			t.Fatalf("%s\n%s", err.Error(), parserContext.GetProblems().ToString(true))
+		}
+
+		clout, err := normalServiceTemplate.Compile()
+		if err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+
+		problems := parserContext.GetProblems()
+		execContext := js.ExecContext{
+			Clout:        clout,
+			Problems:     problems,
+			URLContext:   context.urlContext,
+			Format:       "yaml",
+			ProgramCache: programCache,
+		}
+
+		execContext.Resolve()
+		execContext.Coerce()
+		if !problems.Empty() {
+			t.Fatalf("%s: %s", test.url, problems.ToString(true))
+		}
+
+		if test.cached {
+			if programCache.Compilations() != compilations {
+				t.Errorf("%s: compiled %d programs, expected all to be cached", test.url, programCache.Compilations()-compilations)
+			}
+		} else if programCache.Compilations() == compilations {
+			t.Errorf("%s: no programs compiled", test.url)
+		}
+
+		compilations = programCache.Compilations()
+	}
+}
+
+func TestWorkflows(t *testing.T) {
+	context := NewContext(t)
+	defer context.urlContext.Release()
+
+	url := context.urlContext.NewFileURL(path.Join(filepath.ToSlash(context.root), "examples", "1.3/generated-workflows.yaml"))
+
+	parserContext := context.parser.NewContext()
+	parserContext.URL = url
+	normalServiceTemplate, err := parserContext.Parse(contextpkg.TODO())
+	if err != nil {
+		t.Fatalf("%s\n%s", err.Error(), parserContext.GetProblems().ToString(true))
+	}
+
+	clout, err := normalServiceTemplate.Compile()
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	problems := parserContext.GetProblems()
+	execContext := js.ExecContext{
+		Clout:      clout,
+		Problems:   problems,
+		URLContext: context.urlContext,
+		Format:     "yaml",
+	}
+
+	execContext.Resolve()
+	execContext.Exec("tosca.workflows", map[string]string{"history": "false"})
+	if !problems.Empty() {
+		t.Fatalf("%s", problems.ToString(true))
+	}
+
+	// Each pair of steps must be ordered (directly or indirectly)
+	for workflowName, orders := range map[string][][2]string{
+		"install": {
+			{"server_create", "server_configure"},
+			{"server_configure", "server_start"},
+			{"server_start", "db_create"},
+			{"db_create", "db_host_0_pre_configure"},
+			{"db_host_0_pre_configure", "db_configure"},
+			{"db_configure", "db_host_0_post_configure"},
+			{"db_host_0_post_configure", "db_start"},
+			{"db_start", "db_host_0_add"},
+			{"server_start", "app_create"},
+			{"db_start", "app_dependency_0_pre_configure"},
+			{"app_dependency_0_pre_configure", "app_configure"},
+			{"app_configure", "app_start"},
+		},
+		"uninstall": {
+			{"app_stop", "app_delete"},
+			{"app_stop", "app_dependency_0_remove"},
+			{"app_dependency_0_remove", "db_stop"},
+			{"app_delete", "server_stop"},
+			{"db_delete", "server_stop"},
+			{"server_stop", "server_delete"},
+		},
+	} {
+		steps := getTestWorkflowSteps(t, clout, workflowName)
+		for _, order := range orders {
+			if !isTestStepBefore(steps, order[0], order[1]) {
+				t.Errorf("%s: step %q does not precede step %q", workflowName, order[0], order[1])
+			}
+			if isTestStepBefore(steps, order[1], order[0]) {
+				t.Errorf("%s: step %q precedes step %q", workflowName, order[1], order[0])
+			}
+		}
+	}
+}
+
+func TestTriggers(t *testing.T) {
+	context := NewContext(t)
+	defer context.urlContext.Release()
+
+	for _, test := range []struct {
+		snapshot string
+		expected map[string][]string // trigger name -> node templates per action
+	}{
+		{"", map[string][]string{"overload": {"server1", "server1"}, "idle": nil}},
+		{`{"server1":{"load":5},"server2":{"load":90}}`, map[string][]string{"overload": {"server2", "server2"}, "idle": {"server1"}}},
+		{`{"server1":{"load":50},"server2":{"load":50}}`, map[string][]string{"overload": nil, "idle": nil}},
+	} {
+		url := context.urlContext.NewFileURL(path.Join(filepath.ToSlash(context.root), "examples", "javascript/triggers.yaml"))
+
+		parserContext := context.parser.NewContext()
+		parserContext.URL = url
+		normalServiceTemplate, err := parserContext.Parse(contextpkg.TODO())
+		if err != nil {
+			t.Fatalf("%s\n%s", err.Error(), parserContext.GetProblems().ToString(true))
+		}
+
+		clout, err := normalServiceTemplate.Compile()
+		if err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+
+		problems := parserContext.GetProblems()
+		execContext := js.ExecContext{
+			Clout:      clout,
+			Problems:   problems,
+			URLContext: context.urlContext,
+			Format:     "yaml",
+		}
+
+		execContext.Resolve()
+		arguments := make(map[string]string)
+		if test.snapshot != "" {
+			arguments["snapshot"] = test.snapshot
+		}
+		exports := execContext.Exec("triggers", arguments)
+		if !problems.Empty() {
+			t.Fatalf("%s", problems.ToString(true))
+		}
+
+		triggers, _ := ard.With(exports.Get("report").Export()).Get("autoscale").StringMap()
+		for triggerName, expected := range test.expected {
+			actions, _ := ard.With(triggers).Get(triggerName).List()
+
+			var nodeTemplateNames []string
+			for _, action := range actions {
+				names, _ := ard.With(action).Get("nodeTemplates").List()
+				for _, name := range names {
+					nodeTemplateNames = append(nodeTemplateNames, name.(string))
+				}
+			}
+
+			if fmt.Sprintf("%v", nodeTemplateNames) != fmt.Sprintf("%v", expected) {
+				t.Errorf("snapshot %q, trigger %q: expected %v, got %v", test.snapshot, triggerName, expected, nodeTemplateNames)
+			}
+		}
+	}
+}
+
 func (self *Context) compileFailure(url string, inputs map[string]any) {
 	if t, ok := self.tb.(*testing.T); ok {
 		t.Run(url, func(t_ *testing.T) {
@@ -175,7 +417,9 @@ func (self *Context) compileAll() {
 	self.compile("1.3/data-types.yaml", nil)
 	self.compile("1.3/descriptions.yaml", nil)
 	self.compile("1.3/dsl-definitions.yaml", nil)
+	self.compileWithQuirks("1.3/etsi-nfv-sol001-vnfd.yaml", nil, parsing.NewQuirks("etsinfv.sol001"))
 	self.compile("1.3/functions.yaml", nil)
+	self.compile("1.3/generated-workflows.yaml", nil)
 	self.compile("1.3/inputs-and-outputs.yaml", map[string]any{"ram": "1 GiB"})
 	self.compile("1.3/interfaces.yaml", nil)
 	self.compile("1.3/metadata.yaml", nil)
@@ -214,6 +458,8 @@ func (self *Context) compileAll() {
 	self.compile("javascript/define.yaml", nil)
 	self.compile("javascript/exec.yaml", nil)
 	self.compile("javascript/functions.yaml", nil)
+	self.compile("javascript/modules.yaml", nil)
+	self.compile("javascript/triggers.yaml", nil)
 
 	self.compile("openstack/hello-world.yaml", nil)
 
@@ -229,22 +475,35 @@ func (self *Context) compileAll() {
 	self.compile("hot/hello-world.yaml", map[string]any{
 		"username": "test",
 	})
+	self.compile("hot/nested-stacks.yaml", nil)
+
+	self.compile("cloudformation/hello-world.yaml", nil)
+
+	self.compile("kubernetes/hello-world.yaml", nil)
+
+	self.compileWithQuirks("compose/compose.yaml", map[string]any{
+		"DB_PASSWORD": "test",
+	}, parsing.Quirks{parsing.QuirkGrammarsCompose})
 }
 
 func (self *Context) compile(url string, inputs map[string]any) {
+	self.compileWithQuirks(url, inputs, nil)
+}
+
+func (self *Context) compileWithQuirks(url string, inputs map[string]any, quirks parsing.Quirks) {
 	if t, ok := self.tb.(*testing.T); ok {
 		t.Run(url, func(t_ *testing.T) {
 			// Running the tests in parallel is not just for speed;
 			// it actually helps us to find concurrency bugs
 			t_.Parallel()
-			self.compile_(t_, url, inputs)
+			self.compile_(t_, url, inputs, quirks)
 		})
 	} else {
-		self.compile_(self.tb, url, inputs)
+		self.compile_(self.tb, url, inputs, quirks)
 	}
 }
 
-func (self *Context) compile_(t testing.TB, url string, inputs map[string]any) {
+func (self *Context) compile_(t testing.TB, url string, inputs map[string]any, quirks parsing.Quirks) {
 	var normalServiceTemplate *normal.ServiceTemplate
 	var clout *cloutpkg.Clout
 	var err error
@@ -254,6 +513,7 @@ func (self *Context) compile_(t testing.TB, url string, inputs map[string]any) {
 	parserContext := self.parser.NewContext()
 	parserContext.URL = url_
 	parserContext.Inputs = inputs
+	parserContext.Quirks = quirks
 	if normalServiceTemplate, err = parserContext.Parse(contextpkg.TODO()); err != nil {
 		t.Errorf("%s\n%s", err.Error(), parserContext.GetProblems().ToString(true))
 		return
@@ -286,3 +546,47 @@ func (self *Context) compile_(t testing.TB, url string, inputs map[string]any) {
 		return
 	}
 }
+
+// Utils
+
+// Returns the names of the next steps per step name
+func getTestWorkflowSteps(t *testing.T, clout *cloutpkg.Clout, workflowName string) map[string][]string {
+	for _, vertex := range clout.Vertexes {
+		if !isTestKind(vertex.Metadata, "Workflow") || (vertex.Properties["name"] != workflowName) {
+			continue
+		}
+
+		steps := make(map[string][]string)
+		for _, edge := range vertex.EdgesOut {
+			if !isTestKind(edge.Metadata, "WorkflowStep") {
+				continue
+			}
+
+			name := edge.Target.Properties["name"].(string)
+			steps[name] = nil
+			for _, stepEdge := range edge.Target.EdgesOut {
+				if isTestKind(stepEdge.Metadata, "OnSuccess") {
+					steps[name] = append(steps[name], stepEdge.Target.Properties["name"].(string))
+				}
+			}
+		}
+		return steps
+	}
+
+	t.Fatalf("no %q workflow", workflowName)
+	return nil
+}
+
+func isTestStepBefore(steps map[string][]string, from string, to string) bool {
+	for _, next := range steps[from] {
+		if (next == to) || isTestStepBefore(steps, next, to) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTestKind(metadata ard.StringMap, kind string) bool {
+	kind_, _ := ard.With(metadata).Get("puccini", "kind").String()
+	return kind_ == kind
+}
@@ -0,0 +1,31 @@
+package cloudformation
+
+import (
+	"github.com/tliron/commonlog"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+var log = commonlog.GetLogger("puccini.grammars.cloudformation")
+var logRender = commonlog.NewScopeLogger(log, "render")
+var logNormalize = commonlog.NewScopeLogger(log, "normalize")
+
+var Grammar = parsing.NewGrammar()
+
+var DefaultScriptletNamespace = parsing.NewScriptletNamespace()
+
+func init() {
+	// https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/format-version-structure.html
+	Grammar.RegisterVersion("AWSTemplateFormatVersion", "2010-09-09", "")
+
+	Grammar.RegisterReader("$Root", ReadTemplate)
+
+	Grammar.RegisterReader("Data", ReadData)
+	Grammar.RegisterReader("Output", ReadOutput)
+	Grammar.RegisterReader("Parameter", ReadParameter)
+	Grammar.RegisterReader("Resource", ReadResource)
+	Grammar.RegisterReader("Template", ReadTemplate)
+	Grammar.RegisterReader("Value", ReadValue)
+
+	DefaultScriptletNamespace.RegisterScriptlets(FunctionScriptlets, nil)
+	DefaultScriptletNamespace.RegisterScriptlets(ConstraintScriptlets, nil)
+}
@@ -0,0 +1,58 @@
+package cloudformation
+
+import (
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/assets/tosca/profiles"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+const constraintPathPrefix = "cloudformation/1.0/js/constraints/"
+
+// Built-in constraint functions
+var ConstraintScriptlets = map[string]string{
+	parsing.MetadataContraintPrefix + "length":          profiles.GetString(constraintPathPrefix + "length.js"),
+	parsing.MetadataContraintPrefix + "range":           profiles.GetString(constraintPathPrefix + "range.js"),
+	parsing.MetadataContraintPrefix + "allowed_values":  profiles.GetString(constraintPathPrefix + "allowed_values.js"),
+	parsing.MetadataContraintPrefix + "allowed_pattern": profiles.GetString(constraintPathPrefix + "allowed_pattern.js"),
+}
+
+//
+// Constraint
+//
+// Parameter constraints are not entities in CloudFormation, rather they are gathered from the
+// parameter's properties ("AllowedValues", "MinLength", etc.)
+//
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/parameters-section-structure.html#parameters-section-structure-properties]
+//
+
+type Constraint struct {
+	Operator  string
+	Arguments ard.List
+}
+
+func NewConstraint(operator string, arguments ...ard.Value) Constraint {
+	return Constraint{
+		Operator:  operator,
+		Arguments: arguments,
+	}
+}
+
+func (self *Constraint) NewFunctionCall(context *parsing.Context) *parsing.FunctionCall {
+	return context.NewFunctionCall(parsing.MetadataContraintPrefix+self.Operator, self.Arguments)
+}
+
+//
+// Constraints
+//
+
+// Note: not pointers, because slices of pointers to structs are treated as entities by the parser
+type Constraints []Constraint
+
+func (self Constraints) Normalize(context *parsing.Context, normalDataType *normal.ValueMeta) {
+	for _, constraint := range self {
+		functionCall := constraint.NewFunctionCall(context)
+		NormalizeFunctionCallArguments(functionCall, context)
+		normalDataType.AddValidator(functionCall)
+	}
+}
@@ -0,0 +1,341 @@
+package cloudformation
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/assets/tosca/profiles"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/yamlkeys"
+)
+
+//
+// Intrinsic functions
+//
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference.html]
+//
+
+const functionPathPrefix = "cloudformation/1.0/js/functions/"
+
+// Intrinsic function name -> scriptlet name
+var FunctionNames = map[string]string{
+	"Condition":        "condition",
+	"Fn::And":          "and",
+	"Fn::Base64":       "base64",
+	"Fn::Cidr":         "cidr",
+	"Fn::Equals":       "equals",
+	"Fn::FindInMap":    "find_in_map",
+	"Fn::GetAtt":       "get_att",
+	"Fn::GetAZs":       "get_azs",
+	"Fn::If":           "if",
+	"Fn::ImportValue":  "import_value",
+	"Fn::Join":         "join",
+	"Fn::Length":       "length",
+	"Fn::Not":          "not",
+	"Fn::Or":           "or",
+	"Fn::Select":       "select",
+	"Fn::Split":        "split",
+	"Fn::Sub":          "sub",
+	"Fn::ToJsonString": "to_json_string",
+	"Ref":              "ref",
+}
+
+var FunctionScriptlets = map[string]string{
+	parsing.MetadataFunctionPrefix + "and":            profiles.GetString(functionPathPrefix + "and.js"),
+	parsing.MetadataFunctionPrefix + "base64":         profiles.GetString(functionPathPrefix + "base64.js"),
+	parsing.MetadataFunctionPrefix + "cidr":           profiles.GetString(functionPathPrefix + "cidr.js"),
+	parsing.MetadataFunctionPrefix + "condition":      profiles.GetString(functionPathPrefix + "condition.js"),
+	parsing.MetadataFunctionPrefix + "equals":         profiles.GetString(functionPathPrefix + "equals.js"),
+	parsing.MetadataFunctionPrefix + "find_in_map":    profiles.GetString(functionPathPrefix + "find_in_map.js"),
+	parsing.MetadataFunctionPrefix + "get_att":        profiles.GetString(functionPathPrefix + "get_att.js"),
+	parsing.MetadataFunctionPrefix + "get_azs":        profiles.GetString(functionPathPrefix + "get_azs.js"),
+	parsing.MetadataFunctionPrefix + "if":             profiles.GetString(functionPathPrefix + "if.js"),
+	parsing.MetadataFunctionPrefix + "import_value":   profiles.GetString(functionPathPrefix + "import_value.js"),
+	parsing.MetadataFunctionPrefix + "join":           profiles.GetString(functionPathPrefix + "join.js"),
+	parsing.MetadataFunctionPrefix + "length":         profiles.GetString(functionPathPrefix + "length.js"),
+	parsing.MetadataFunctionPrefix + "not":            profiles.GetString(functionPathPrefix + "not.js"),
+	parsing.MetadataFunctionPrefix + "or":             profiles.GetString(functionPathPrefix + "or.js"),
+	parsing.MetadataFunctionPrefix + "ref":            profiles.GetString(functionPathPrefix + "ref.js"),
+	parsing.MetadataFunctionPrefix + "select":         profiles.GetString(functionPathPrefix + "select.js"),
+	parsing.MetadataFunctionPrefix + "split":          profiles.GetString(functionPathPrefix + "split.js"),
+	parsing.MetadataFunctionPrefix + "sub":            profiles.GetString(functionPathPrefix + "sub.js"),
+	parsing.MetadataFunctionPrefix + "to_json_string": profiles.GetString(functionPathPrefix + "to_json_string.js"),
+}
+
+// These functions have a single argument, even if it is a list
+var singleArgumentFunctions = []string{"Condition", "Fn::Base64", "Fn::GetAZs", "Fn::ImportValue", "Fn::Length", "Fn::ToJsonString", "Ref"}
+
+func ParseFunctionCall(context *parsing.Context) bool {
+	return parseFunctionCall(context, nil)
+}
+
+func ParseFunctionCalls(context *parsing.Context) bool {
+	return parseFunctionCalls(context, nil)
+}
+
+func parseFunctionCall(context *parsing.Context, conditionNames []string) bool {
+	if _, ok := context.Data.(*parsing.FunctionCall); ok {
+		// It's already a function call
+		return true
+	}
+
+	map_, ok := context.Data.(ard.Map)
+	if !ok || len(map_) != 1 {
+		return false
+	}
+
+	for key, data := range map_ {
+		name := yamlkeys.KeyString(key)
+
+		functionName, ok := FunctionNames[name]
+		if !ok {
+			// Not a function call, despite having the right data structure
+			return false
+		}
+
+		scriptletName := parsing.MetadataFunctionPrefix + functionName
+		if _, ok := context.ScriptletNamespace.Lookup(scriptletName); !ok {
+			return false
+		}
+
+		originalArguments, ok := data.(ard.List)
+		if !ok || isSingleArgumentFunction(name) {
+			originalArguments = ard.List{data}
+		}
+
+		// Arguments may be function calls
+		arguments := make(ard.List, len(originalArguments))
+		for index, argument := range originalArguments {
+			argumentContext := context.Clone(argument)
+			parseFunctionCalls(argumentContext, conditionNames)
+			arguments[index] = argumentContext.Data
+		}
+
+		// These functions refer to other sections of the template, so we append the referred data
+		// as an extra argument
+		switch name {
+		case "Fn::FindInMap":
+			if mapping, ok := getMapping(context, arguments); ok {
+				arguments = append(arguments, mapping)
+			}
+
+		case "Fn::If", "Condition":
+			if condition, ok := getCondition(context, arguments, conditionNames); ok {
+				arguments = append(arguments, condition)
+			}
+		}
+
+		context.Data = context.NewFunctionCall(scriptletName, arguments)
+
+		// We have only one key
+		return true
+	}
+
+	return false
+}
+
+func parseFunctionCalls(context *parsing.Context, conditionNames []string) bool {
+	changed := false
+	if parseFunctionCall(context, conditionNames) {
+		changed = true
+	} else if list, ok := context.Data.(ard.List); ok {
+		for index, value := range list {
+			childContext := context.ListChild(index, value)
+			if parseFunctionCalls(childContext, conditionNames) {
+				changed = true
+			}
+			list[index] = childContext.Data
+		}
+	} else if map_, ok := context.Data.(ard.Map); ok {
+		for key, value := range map_ {
+			childContext := context.MapChild(key, value)
+			if parseFunctionCalls(childContext, conditionNames) {
+				changed = true
+			}
+			yamlkeys.MapPut(map_, key, childContext.Data) // support complex keys
+		}
+	}
+	return changed
+}
+
+func NormalizeFunctionCallArguments(functionCall *parsing.FunctionCall, context *parsing.Context) {
+	for index, argument := range functionCall.Arguments {
+		// Because the same constraint instance may be shared among more than one value, this
+		// func might be called more than once on the same arguments, so we must make sure not
+		// to normalize more than once
+		if _, ok := argument.(normal.Value); !ok {
+			if value, ok := argument.(*Value); ok {
+				functionCall.Arguments[index] = value.Normalize()
+			} else {
+				// Note: this literal value will not have a $type field
+				functionCall.Arguments[index] = NewValue(context.ListChild(index, argument)).Normalize()
+			}
+		}
+	}
+}
+
+//
+// Reference
+//
+
+type Reference struct {
+	Name      string
+	Attribute *string
+}
+
+var subVariableRegexp = regexp.MustCompile(`\$\{([^!}][^}]*)\}`)
+
+// Gathers the names referred to by "Ref", "Fn::GetAtt", and "Fn::Sub" (must be called before
+// normalization)
+func GetReferences(data ard.Value) []Reference {
+	var references []Reference
+
+	switch data_ := data.(type) {
+	case ard.List:
+		for _, element := range data_ {
+			references = append(references, GetReferences(element)...)
+		}
+
+	case ard.Map:
+		for _, value := range data_ {
+			references = append(references, GetReferences(value)...)
+		}
+
+	case *parsing.FunctionCall:
+		switch data_.Name {
+		case parsing.MetadataFunctionPrefix + "ref":
+			if name, ok := getStringArgument(data_, 0); ok {
+				references = append(references, Reference{Name: name})
+			}
+
+		case parsing.MetadataFunctionPrefix + "get_att":
+			if name, ok := getStringArgument(data_, 0); ok {
+				reference := Reference{Name: name}
+				if attribute, ok := getStringArgument(data_, 1); ok {
+					reference.Attribute = &attribute
+				}
+				references = append(references, reference)
+			}
+
+		case parsing.MetadataFunctionPrefix + "sub":
+			if template, ok := getStringArgument(data_, 0); ok {
+				var variables ard.Map
+				if len(data_.Arguments) > 1 {
+					variables, _ = data_.Arguments[1].(ard.Map)
+				}
+
+				for _, match := range subVariableRegexp.FindAllStringSubmatch(template, -1) {
+					name := strings.TrimSpace(match[1])
+					if _, ok := variables[name]; ok {
+						continue
+					}
+
+					if split := strings.SplitN(name, ".", 2); len(split) == 2 {
+						references = append(references, Reference{Name: split[0], Attribute: &split[1]})
+					} else {
+						references = append(references, Reference{Name: name})
+					}
+				}
+			}
+		}
+
+		for _, argument := range data_.Arguments {
+			references = append(references, GetReferences(argument)...)
+		}
+	}
+
+	return references
+}
+
+// Utils
+
+func isSingleArgumentFunction(name string) bool {
+	for _, name_ := range singleArgumentFunctions {
+		if name == name_ {
+			return true
+		}
+	}
+	return false
+}
+
+func getStringArgument(functionCall *parsing.FunctionCall, index int) (string, bool) {
+	if index < len(functionCall.Arguments) {
+		string_, ok := functionCall.Arguments[index].(string)
+		return string_, ok
+	}
+	return "", false
+}
+
+func getRootContext(context *parsing.Context) *parsing.Context {
+	for context.Parent != nil {
+		context = context.Parent
+	}
+	return context
+}
+
+func getSection(context *parsing.Context, section string) (*parsing.Context, ard.Map, bool) {
+	rootContext := getRootContext(context)
+	if map_, ok := rootContext.Data.(ard.Map); ok {
+		if data, ok := map_[section]; ok {
+			if data_, ok := data.(ard.Map); ok {
+				return rootContext.FieldChild(section, data), data_, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+func getMapping(context *parsing.Context, arguments ard.List) (ard.Value, bool) {
+	if len(arguments) == 0 {
+		return nil, false
+	}
+
+	name, ok := arguments[0].(string)
+	if !ok {
+		// Mapping names must be literal
+		context.ListChild(0, arguments[0]).ReportValueWrongType(ard.TypeString)
+		return nil, false
+	}
+
+	if _, mappings, ok := getSection(context, "Mappings"); ok {
+		if mapping, ok := mappings[name]; ok {
+			return ard.Copy(mapping), true
+		}
+	}
+
+	context.ListChild(0, name).ReportUnknown("mapping")
+	return nil, false
+}
+
+func getCondition(context *parsing.Context, arguments ard.List, conditionNames []string) (ard.Value, bool) {
+	if len(arguments) == 0 {
+		return nil, false
+	}
+
+	name, ok := arguments[0].(string)
+	if !ok {
+		// Condition names must be literal
+		context.ListChild(0, arguments[0]).ReportValueWrongType(ard.TypeString)
+		return nil, false
+	}
+
+	for _, name_ := range conditionNames {
+		if name == name_ {
+			context.ListChild(0, name).ReportValueMalformed("condition", "circular reference")
+			return nil, false
+		}
+	}
+
+	if conditionsContext, conditions, ok := getSection(context, "Conditions"); ok {
+		if condition, ok := conditions[name]; ok {
+			conditionContext := conditionsContext.MapChild(name, ard.Copy(condition))
+			parseFunctionCalls(conditionContext, append(conditionNames, name))
+			return conditionContext.Data, true
+		}
+	}
+
+	context.ListChild(0, name).ReportUnknown("condition")
+	return nil, false
+}
@@ -0,0 +1,216 @@
+package cloudformation_test
+
+import (
+	contextpkg "context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-ard"
+	problemspkg "github.com/tliron/go-kutil/problems"
+	cloutpkg "github.com/tliron/go-puccini/clout"
+	"github.com/tliron/go-puccini/clout/js"
+	"github.com/tliron/go-puccini/tosca/parser"
+)
+
+func TestFunctionArguments(t *testing.T) {
+	outputs := compileTestOutputs(t, `
+AWSTemplateFormatVersion: 2010-09-09
+Parameters:
+  Environment:
+    Type: String
+    Default: test
+  Cidrs:
+    Type: CommaDelimitedList
+    Default: 10.0.0.0/16,192.168.0.0/24
+Mappings:
+  RegionMap:
+    region:
+      Ami: ami-1
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+Outputs:
+  Join:
+    Value: !Join [ '-', [ hello, !Ref Environment ] ]
+  Select:
+    Value: !Select [ 1, [ a, b, c ] ]
+  LengthOfList:
+    Value:
+      Fn::Length: [ a, b, c ]
+  LengthOfRef:
+    Value:
+      Fn::Length: !Ref Cidrs
+  Base64:
+    Value: !Base64 hello
+  FindInMap:
+    Value: !FindInMap [ RegionMap, !Ref AWS::Region, Ami ]
+  Sub:
+    Value: !Sub ${AWS::Partition}-${Environment}
+  SubWithVariables:
+    Value: !Sub [ '${Name}-${Environment}', { Name: !Select [ 0, !Split [ '.', a.b ] ] } ]
+  Ref:
+    Value: !Ref Bucket
+`, nil)
+
+	for name, expected := range map[string]ard.Value{
+		"Join":             "hello-test",
+		"Select":           "b",
+		"LengthOfList":     int64(3),
+		"LengthOfRef":      int64(2),
+		"Base64":           "aGVsbG8=",
+		"FindInMap":        "ami-1",
+		"Sub":              "aws-test",
+		"SubWithVariables": "a-test",
+		"Ref":              "Bucket",
+	} {
+		assertTestOutput(t, outputs, name, expected)
+	}
+}
+
+func TestFunctionConditions(t *testing.T) {
+	const template = `
+AWSTemplateFormatVersion: 2010-09-09
+Parameters:
+  Environment:
+    Type: String
+    Default: test
+    AllowedValues: [ test, prod ]
+  Size:
+    Type: Number
+    Default: 1
+Conditions:
+  IsProduction: !Equals [ !Ref Environment, prod ]
+  IsTest: !Not [ !Condition IsProduction ]
+  IsLarge: !Equals [ !Ref Size, 3 ]
+  IsLargeProduction: !And [ !Condition IsProduction, !Condition IsLarge ]
+  IsLargeOrProduction: !Or [ !Condition IsProduction, !Condition IsLarge ]
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+Outputs:
+  IsProduction:
+    Value: !If [ IsProduction, yes, no ]
+  IsTest:
+    Value: !If [ IsTest, yes, no ]
+  IsLargeProduction:
+    Value: !If [ IsLargeProduction, yes, no ]
+  IsLargeOrProduction:
+    Value: !If [ IsLargeOrProduction, yes, no ]
+`
+
+	for _, test := range []struct {
+		inputs   map[string]ard.Value
+		expected map[string]string
+	}{
+		{nil, map[string]string{"IsProduction": "no", "IsTest": "yes", "IsLargeProduction": "no", "IsLargeOrProduction": "no"}},
+		{map[string]ard.Value{"Environment": "prod"}, map[string]string{"IsProduction": "yes", "IsTest": "no", "IsLargeProduction": "no", "IsLargeOrProduction": "yes"}},
+		{map[string]ard.Value{"Size": 3}, map[string]string{"IsProduction": "no", "IsTest": "yes", "IsLargeProduction": "no", "IsLargeOrProduction": "yes"}},
+		{map[string]ard.Value{"Environment": "prod", "Size": 3}, map[string]string{"IsProduction": "yes", "IsTest": "no", "IsLargeProduction": "yes", "IsLargeOrProduction": "yes"}},
+	} {
+		outputs := compileTestOutputs(t, template, test.inputs)
+		for name, expected := range test.expected {
+			assertTestOutput(t, outputs, name, expected)
+		}
+	}
+}
+
+func TestFunctionProblems(t *testing.T) {
+	for name, outputs := range map[string]string{
+		"unknown mapping":     "Value: !FindInMap [ Missing, a, b ]",
+		"non-literal mapping": "Value: !FindInMap [ !Ref AWS::Region, a, b ]",
+		"unknown condition":   "Value: !If [ Missing, yes, no ]",
+		"circular condition":  "Value: !If [ Circular, yes, no ]",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, problems := compileTestTemplate(t, `
+AWSTemplateFormatVersion: 2010-09-09
+Conditions:
+  Circular: !Not [ !Condition Circular ]
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+Outputs:
+  Output:
+    `+outputs+"\n", nil)
+
+			if problems.Empty() {
+				t.Errorf("not reported")
+			}
+		})
+	}
+}
+
+// Utils
+
+// The coerced outputs
+func compileTestOutputs(t *testing.T, content string, inputs map[string]ard.Value) ard.StringMap {
+	t.Helper()
+
+	clout, problems := compileTestTemplate(t, content, inputs)
+	if !problems.Empty() {
+		t.Fatalf("%s", problems.ToString(true))
+	}
+
+	outputs, _ := ard.With(clout.Properties).Get("tosca", "outputs").StringMap()
+	return outputs
+}
+
+// Coerces unless told otherwise
+func compileTestTemplate(t *testing.T, content string, inputs map[string]ard.Value, coerce ...bool) (*cloutpkg.Clout, *problemspkg.Problems) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "template.yaml")
+	if err := os.WriteFile(path, []byte(strings.TrimLeft(content, "\n")), 0600); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	urlContext := exturl.NewContext()
+	t.Cleanup(func() {
+		urlContext.Release()
+	})
+
+	parserContext := parser.NewParser().NewContext()
+	parserContext.URL = urlContext.NewFileURL(path)
+	parserContext.Inputs = inputs
+	normalServiceTemplate, err := parserContext.Parse(contextpkg.TODO())
+	problems := parserContext.GetProblems()
+	if err != nil {
+		if problems.Empty() {
+			t.Fatalf("%s", err.Error())
+		}
+		return nil, problems
+	}
+
+	clout, err := normalServiceTemplate.Compile()
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	if (len(coerce) == 0) || coerce[0] {
+		execContext := js.ExecContext{
+			Clout:      clout,
+			Problems:   problems,
+			URLContext: urlContext,
+			Format:     "yaml",
+		}
+		execContext.Resolve()
+		if problems.Empty() {
+			execContext.Coerce()
+		}
+	}
+
+	return clout, problems
+}
+
+func assertTestOutput(t *testing.T, outputs ard.StringMap, name string, expected ard.Value) {
+	t.Helper()
+
+	if value, ok := outputs[name]; !ok {
+		t.Errorf("%s: missing", name)
+	} else if !ard.Equals(value, expected) {
+		t.Errorf("%s: expected %v (%T), got %v (%T)", name, expected, expected, value, value)
+	}
+}
@@ -0,0 +1,95 @@
+package cloudformation
+
+import (
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+//
+// Output
+//
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/outputs-section-structure.html]
+//
+
+type Output struct {
+	*Entity `name:"output"`
+	Name    string `namespace:""`
+
+	Description *string `read:"Description"`
+	Value       *Value  `read:"Value,Value" mandatory:""`
+	Export      *Data   `read:"Export,Data"`
+	Condition   *string `read:"Condition"`
+}
+
+func NewOutput(context *parsing.Context) *Output {
+	return &Output{
+		Entity: NewEntity(context),
+		Name:   context.Name,
+	}
+}
+
+// ([parsing.Reader] signature)
+func ReadOutput(context *parsing.Context) parsing.EntityPtr {
+	self := NewOutput(context)
+	context.ValidateUnsupportedFields(context.ReadFields(self))
+	return self
+}
+
+// ([parsing.Mappable] interface)
+func (self *Output) GetKey() string {
+	return self.Name
+}
+
+// ([parsing.Renderable] interface)
+func (self *Output) Render() {
+	self.renderOnce.Do(self.render)
+}
+
+func (self *Output) render() {
+	logRender.Debugf("output: %s", self.Name)
+
+	if self.Condition != nil {
+		validateConditionName(self.Context.FieldChild("Condition", *self.Condition), *self.Condition)
+	}
+
+	if self.Value != nil {
+		for _, reference := range GetReferences(self.Value.Context.Data) {
+			LookupReference(self.Value.Context, reference)
+		}
+	}
+}
+
+func (self *Output) Normalize(context *parsing.Context) normal.Value {
+	var value *Value
+	if self.Value != nil {
+		value = self.Value
+	} else {
+		// Outputs should always appear, even if they have no value
+		value = NewValue(context.MapChild(self.Name, nil))
+	}
+
+	normalValue := value.Normalize()
+
+	if self.Description != nil {
+		valueMeta := normal.CopyValueMeta(normalValue.GetMeta())
+		if valueMeta == nil {
+			valueMeta = normal.NewValueMeta()
+		}
+		valueMeta.Description = *self.Description
+		normalValue.SetMeta(valueMeta)
+	}
+
+	return normalValue
+}
+
+//
+// Outputs
+//
+
+type Outputs map[string]*Output
+
+func (self Outputs) Normalize(normalConstrainables normal.Values, context *parsing.Context) {
+	for key, output := range self {
+		normalConstrainables[key] = output.Normalize(context)
+	}
+}
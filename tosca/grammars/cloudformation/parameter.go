@@ -0,0 +1,204 @@
+package cloudformation
+
+import (
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+//
+// Parameter
+//
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/parameters-section-structure.html]
+//
+
+type Parameter struct {
+	*Entity `name:"parameter"`
+	Name    string `namespace:""`
+
+	Type                  *string `read:"Type" mandatory:""`
+	Description           *string `read:"Description"`
+	Default               *Value  `read:"Default,Value"`
+	AllowedPattern        *string `read:"AllowedPattern"`
+	AllowedValues         *Data   `read:"AllowedValues,Data"`
+	ConstraintDescription *string `read:"ConstraintDescription"`
+	NoEcho                *bool
+	MinLength             ard.Value `json:",omitempty" yaml:",omitempty"`
+	MaxLength             ard.Value `json:",omitempty" yaml:",omitempty"`
+	MinValue              ard.Value `json:",omitempty" yaml:",omitempty"`
+	MaxValue              ard.Value `json:",omitempty" yaml:",omitempty"`
+
+	Constraints Constraints `traverse:"ignore" json:"-" yaml:"-"`
+	Value       *Value
+}
+
+func NewParameter(context *parsing.Context) *Parameter {
+	return &Parameter{
+		Entity: NewEntity(context),
+		Name:   context.Name,
+	}
+}
+
+// ([parsing.Reader] signature)
+func ReadParameter(context *parsing.Context) parsing.EntityPtr {
+	self := NewParameter(context)
+	context.ValidateUnsupportedFields(append(context.ReadFields(self), "NoEcho", "MinLength", "MaxLength", "MinValue", "MaxValue"))
+
+	// "NoEcho" is often written as a string
+	if childContext, ok := context.GetFieldChild("NoEcho"); ok {
+		switch data := childContext.Data.(type) {
+		case bool:
+			self.NoEcho = &data
+		case string:
+			noEcho := data == "true"
+			self.NoEcho = &noEcho
+		default:
+			childContext.ReportValueWrongType(ard.TypeBoolean)
+		}
+	}
+
+	// Numeric properties are often written as strings
+	self.MinLength = readNumber(context, "MinLength")
+	self.MaxLength = readNumber(context, "MaxLength")
+	self.MinValue = readNumber(context, "MinValue")
+	self.MaxValue = readNumber(context, "MaxValue")
+
+	self.Constraints = self.newConstraints()
+
+	if self.Type != nil {
+		type_ := *self.Type
+		if IsParameterTypeValid(type_) {
+			if self.Default != nil {
+				self.Default.CoerceParameterType(type_)
+				self.Default.ValidateParameterType(type_)
+				self.Default.Constraints = self.Constraints
+			}
+		} else {
+			context.FieldChild("Type", type_).ReportKeynameUnsupportedValue()
+		}
+	}
+
+	return self
+}
+
+// ([parsing.Mappable] interface)
+func (self *Parameter) GetKey() string {
+	return self.Name
+}
+
+// ([parsing.Renderable] interface)
+func (self *Parameter) Render() {
+	self.renderOnce.Do(self.render)
+}
+
+func (self *Parameter) render() {
+	logRender.Debugf("parameter: %s", self.Name)
+
+	if self.Value != nil {
+		if self.Type != nil {
+			type_ := *self.Type
+			if IsParameterTypeValid(type_) {
+				self.Value.CoerceParameterType(type_)
+				self.Value.ValidateParameterType(type_)
+			}
+			self.Value.Constraints = self.Constraints
+		}
+	}
+}
+
+func (self *Parameter) Normalize(context *parsing.Context) normal.Value {
+	value := self.Value
+	if value == nil {
+		if self.Default != nil {
+			value = self.Default
+		} else {
+			// Parameters should always appear, even if they have no default value
+			value = NewValue(context.MapChild(self.Name, nil))
+		}
+	}
+
+	normalValue := value.Normalize()
+
+	// Attributes used for generating input forms
+	valueMeta := normal.CopyValueMeta(normalValue.GetMeta())
+	if valueMeta == nil {
+		valueMeta = normal.NewValueMeta()
+	}
+	if self.Description != nil {
+		valueMeta.Description = *self.Description
+	}
+	if self.ConstraintDescription != nil {
+		valueMeta.Metadata["constraint_description"] = *self.ConstraintDescription
+	}
+	if (self.NoEcho != nil) && *self.NoEcho {
+		valueMeta.Metadata["hidden"] = "true"
+	}
+	normalValue.SetMeta(valueMeta)
+
+	return normalValue
+}
+
+func (self *Parameter) newConstraints() Constraints {
+	var constraints Constraints
+
+	if self.AllowedValues != nil {
+		if list, ok := self.AllowedValues.Data.(ard.List); ok {
+			constraints = append(constraints, NewConstraint("allowed_values", list...))
+		} else {
+			self.AllowedValues.Context.ReportValueWrongType(ard.TypeList)
+		}
+	}
+
+	if self.AllowedPattern != nil {
+		constraints = append(constraints, NewConstraint("allowed_pattern", *self.AllowedPattern))
+	}
+
+	if limits := newLimits(self.MinLength, self.MaxLength); limits != nil {
+		constraints = append(constraints, NewConstraint("length", limits))
+	}
+
+	if bounds := newLimits(self.MinValue, self.MaxValue); bounds != nil {
+		constraints = append(constraints, NewConstraint("range", bounds))
+	}
+
+	return constraints
+}
+
+//
+// Parameters
+//
+
+type Parameters map[string]*Parameter
+
+func (self Parameters) Normalize(c normal.Values, context *parsing.Context) {
+	for key, parameter := range self {
+		c[key] = parameter.Normalize(context)
+	}
+}
+
+// Utils
+
+func readNumber(context *parsing.Context, name string) ard.Value {
+	if childContext, ok := context.GetFieldChild(name); ok {
+		data := coerceNumber(childContext.Data)
+		if childContext.Clone(data).ValidateType(ard.TypeInteger, ard.TypeFloat) {
+			return data
+		}
+	}
+	return nil
+}
+
+func newLimits(min ard.Value, max ard.Value) ard.Map {
+	if (min == nil) && (max == nil) {
+		return nil
+	}
+
+	limits := make(ard.Map)
+	if min != nil {
+		limits["min"] = min
+	}
+	if max != nil {
+		limits["max"] = max
+	}
+	return limits
+}
@@ -0,0 +1,290 @@
+package cloudformation
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+var DeletionPolicies = []string{
+	"Delete",
+	"Retain",
+	"RetainExceptOnCreate",
+	"Snapshot",
+}
+
+func IsDeletionPolicyValid(policy string) bool {
+	for _, p := range DeletionPolicies {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// Custom resources have no specification
+func IsCustomResourceType(type_ string) bool {
+	return strings.HasPrefix(type_, "Custom::") || (type_ == "AWS::CloudFormation::CustomResource")
+}
+
+//
+// Resource
+//
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/resources-section-structure.html]
+//
+
+type Resource struct {
+	*Entity `name:"resource"`
+	Name    string `namespace:""`
+
+	Type                *string `read:"Type" mandatory:""`
+	Properties          Values  `read:"Properties,Value"`
+	DependsOn           *[]string
+	Condition           *string `read:"Condition"`
+	Metadata            *Data   `read:"Metadata,Data"`
+	CreationPolicy      *Data   `read:"CreationPolicy,Data"`
+	DeletionPolicy      *string `read:"DeletionPolicy"`
+	UpdatePolicy        *Data   `read:"UpdatePolicy,Data"`
+	UpdateReplacePolicy *string `read:"UpdateReplacePolicy"`
+
+	DependsOnResources  Resources `lookup:"DependsOn,DependsOn" traverse:"ignore" json:"-" yaml:"-"`
+	ReferencedResources Resources `traverse:"ignore" json:"-" yaml:"-"`
+}
+
+func NewResource(context *parsing.Context) *Resource {
+	return &Resource{
+		Entity:     NewEntity(context),
+		Name:       context.Name,
+		Properties: make(Values),
+	}
+}
+
+// ([parsing.Reader] signature)
+func ReadResource(context *parsing.Context) parsing.EntityPtr {
+	self := NewResource(context)
+	context.ValidateUnsupportedFields(append(context.ReadFields(self), "DependsOn"))
+
+	if childContext, ok := context.GetFieldChild("DependsOn"); ok {
+		self.DependsOn = childContext.ReadStringOrStringList()
+	}
+
+	if self.Type != nil {
+		if !strings.Contains(*self.Type, "::") {
+			context.FieldChild("Type", *self.Type).ReportKeynameUnsupportedValue()
+		}
+	}
+
+	if self.DeletionPolicy != nil {
+		if !IsDeletionPolicyValid(*self.DeletionPolicy) {
+			context.FieldChild("DeletionPolicy", *self.DeletionPolicy).ReportKeynameUnsupportedValue()
+		}
+	}
+
+	if self.UpdateReplacePolicy != nil {
+		if !IsDeletionPolicyValid(*self.UpdateReplacePolicy) {
+			context.FieldChild("UpdateReplacePolicy", *self.UpdateReplacePolicy).ReportKeynameUnsupportedValue()
+		}
+	}
+
+	return self
+}
+
+// ([parsing.Renderable] interface)
+func (self *Resource) Render() {
+	self.renderOnce.Do(self.render)
+}
+
+func (self *Resource) render() {
+	logRender.Debugf("resource: %s", self.Name)
+
+	if self.Condition != nil {
+		validateConditionName(self.Context.FieldChild("Condition", *self.Condition), *self.Condition)
+	}
+
+	// "Ref", "Fn::GetAtt", and "Fn::Sub" references become relationships
+	for _, property := range self.Properties {
+		for _, reference := range GetReferences(property.Context.Data) {
+			if resource, ok := LookupReference(property.Context, reference); ok {
+				self.addReferencedResource(resource)
+			}
+		}
+	}
+
+	if (self.Type != nil) && !DefaultSpecification.IsEmpty() {
+		self.validateSpecification(*self.Type)
+	}
+}
+
+func (self *Resource) addReferencedResource(resource *Resource) {
+	if resource == self {
+		self.Context.Clone(resource.Name).ReportValueMalformed("reference", "resource refers to itself")
+		return
+	}
+
+	for _, resource_ := range self.ReferencedResources {
+		if resource_ == resource {
+			return
+		}
+	}
+
+	self.ReferencedResources = append(self.ReferencedResources, resource)
+}
+
+func (self *Resource) validateSpecification(type_ string) {
+	if IsCustomResourceType(type_) {
+		return
+	}
+
+	resourceTypeSpecification, ok := DefaultSpecification.ResourceTypes[type_]
+	if !ok {
+		self.Context.FieldChild("Type", type_).ReportUnknown("resource type")
+		return
+	}
+
+	for name, property := range self.Properties {
+		if _, ok := resourceTypeSpecification.Properties[name]; !ok {
+			property.Context.ReportUndeclared("property")
+		}
+	}
+
+	propertiesContext := self.Context.FieldChild("Properties", nil)
+	for name, propertySpecification := range resourceTypeSpecification.Properties {
+		if propertySpecification.Required {
+			if _, ok := self.Properties[name]; !ok {
+				propertiesContext.MapChild(name, nil).ReportValueRequired("property")
+			}
+		}
+	}
+}
+
+// Reports unknown references. Returns the referenced resource, if it is one.
+func LookupReference(context *parsing.Context, reference Reference) (*Resource, bool) {
+	if reference.Attribute == nil {
+		if reference.Name == "AWS::NoValue" {
+			return nil, false
+		}
+
+		if _, ok := context.Namespace.LookupForType(reference.Name, reflect.TypeOf((*Parameter)(nil))); ok {
+			return nil, false
+		}
+	}
+
+	if resource, ok := context.Namespace.LookupForType(reference.Name, reflect.TypeOf((*Resource)(nil))); ok {
+		resource_ := resource.(*Resource)
+
+		if (reference.Attribute != nil) && (resource_.Type != nil) {
+			if resourceTypeSpecification, ok := DefaultSpecification.ResourceTypes[*resource_.Type]; ok {
+				if !isAttributeDeclared(resourceTypeSpecification, *resource_.Type, *reference.Attribute) {
+					context.Clone(reference.Name + "." + *reference.Attribute).ReportUnknown("attribute")
+				}
+			}
+		}
+
+		return resource_, true
+	}
+
+	if reference.Attribute == nil {
+		context.Clone(reference.Name).ReportUnknown("parameter or resource")
+	} else {
+		context.Clone(reference.Name).ReportUnknown("resource")
+	}
+
+	return nil, false
+}
+
+var capabilityTypeName = "Resource"
+var capabilityTypes = normal.NewEntityTypes(capabilityTypeName)
+var dependsOnRelationshipTypes = normal.NewEntityTypes("DependsOn")
+var referenceRelationshipTypes = normal.NewEntityTypes("Reference")
+
+func (self *Resource) Normalize(normalServiceTemplate *normal.ServiceTemplate) *normal.NodeTemplate {
+	logNormalize.Debugf("resource: %s", self.Name)
+
+	normalNodeTemplate := normalServiceTemplate.NewNodeTemplate(self.Name)
+
+	if self.Type != nil {
+		normalNodeTemplate.Types = normal.NewEntityTypes(*self.Type)
+	}
+
+	if self.Condition != nil {
+		normalNodeTemplate.Metadata["cloudformation.condition"] = *self.Condition
+	}
+
+	if self.DeletionPolicy != nil {
+		normalNodeTemplate.Metadata["cloudformation.deletion_policy"] = *self.DeletionPolicy
+	}
+
+	self.Properties.Normalize(normalNodeTemplate.Properties)
+
+	capabilityContext := self.Context.FieldChild("capabilities", nil).MapChild("resource", nil)
+	normalNodeTemplate.NewCapability("resource", normal.NewLocationForContext(capabilityContext)).Types = capabilityTypes
+
+	return normalNodeTemplate
+}
+
+func (self *Resource) NormalizeDependencies(normalServiceTemplate *normal.ServiceTemplate) {
+	logNormalize.Debugf("resource dependencies: %s", self.Name)
+
+	normalNodeTemplate := normalServiceTemplate.NodeTemplates[self.Name]
+	requirementsContext := self.Context.FieldChild("requirements", nil)
+
+	for index, resource := range self.DependsOnResources {
+		normalRequirement := normalNodeTemplate.NewRequirement("depends_on", normal.NewLocationForContext(requirementsContext.ListChild(index, nil)))
+		normalRequirement.NodeTemplate = normalServiceTemplate.NodeTemplates[resource.Name]
+		normalRequirement.CapabilityTypeName = &capabilityTypeName
+
+		normalRelationship := normalRequirement.NewRelationship()
+		normalRelationship.Types = dependsOnRelationshipTypes
+	}
+
+	for index, resource := range self.ReferencedResources {
+		normalRequirement := normalNodeTemplate.NewRequirement("reference", normal.NewLocationForContext(requirementsContext.ListChild(len(self.DependsOnResources)+index, nil)))
+		normalRequirement.NodeTemplate = normalServiceTemplate.NodeTemplates[resource.Name]
+		normalRequirement.CapabilityTypeName = &capabilityTypeName
+
+		normalRelationship := normalRequirement.NewRelationship()
+		normalRelationship.Types = referenceRelationshipTypes
+	}
+}
+
+//
+// Resources
+//
+
+type Resources []*Resource
+
+func (self Resources) Normalize(normalServiceTemplate *normal.ServiceTemplate) {
+	for _, resource := range self {
+		normalServiceTemplate.NodeTemplates[resource.Name] = resource.Normalize(normalServiceTemplate)
+	}
+
+	// Dependencies must be normalized after resources
+	// (because they may reference other resources)
+	for _, resource := range self {
+		resource.NormalizeDependencies(normalServiceTemplate)
+	}
+}
+
+// Utils
+
+func isAttributeDeclared(resourceTypeSpecification *ResourceTypeSpecification, type_ string, attribute string) bool {
+	if _, ok := resourceTypeSpecification.Attributes[attribute]; ok {
+		return true
+	}
+
+	// Nested stack outputs
+	return (type_ == "AWS::CloudFormation::Stack") && strings.HasPrefix(attribute, "Outputs.")
+}
+
+func validateConditionName(context *parsing.Context, name string) bool {
+	if _, conditions, ok := getSection(context, "Conditions"); ok {
+		if _, ok := conditions[name]; ok {
+			return true
+		}
+	}
+
+	context.ReportUnknown("condition")
+	return false
+}
@@ -0,0 +1,92 @@
+package cloudformation
+
+import (
+	contextpkg "context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tliron/commonlog"
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-kutil/util"
+)
+
+//
+// Specification
+//
+// The resource specification published by AWS, which is used to validate resource properties
+// and attributes. Only the "ResourceTypes" section is used.
+//
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/cfn-resource-specification.html]
+//
+
+// When empty (the default) resources are not validated
+var DefaultSpecification = NewSpecification()
+
+type Specification struct {
+	ResourceTypes map[string]*ResourceTypeSpecification `json:"ResourceTypes"`
+}
+
+func NewSpecification() *Specification {
+	return &Specification{
+		ResourceTypes: make(map[string]*ResourceTypeSpecification),
+	}
+}
+
+// Specifications read later override the resource types of specifications read earlier.
+func (self *Specification) Read(context contextpkg.Context, url exturl.URL) error {
+	reader, err := url.Open(context)
+	if err != nil {
+		return err
+	}
+	reader = util.NewContextualReadCloser(context, reader)
+	defer commonlog.CallAndLogWarning(reader.Close, "Specification.Read", log)
+
+	var specification Specification
+	if err := json.NewDecoder(reader).Decode(&specification); err != nil {
+		return fmt.Errorf("%s: %w", url.String(), err)
+	}
+
+	for name, resourceType := range specification.ResourceTypes {
+		self.ResourceTypes[name] = resourceType
+	}
+
+	return nil
+}
+
+func (self *Specification) IsEmpty() bool {
+	return len(self.ResourceTypes) == 0
+}
+
+//
+// ResourceTypeSpecification
+//
+
+type ResourceTypeSpecification struct {
+	Documentation string                             `json:"Documentation"`
+	Properties    map[string]*PropertySpecification  `json:"Properties"`
+	Attributes    map[string]*AttributeSpecification `json:"Attributes"`
+}
+
+//
+// PropertySpecification
+//
+
+type PropertySpecification struct {
+	Documentation     string `json:"Documentation"`
+	Required          bool   `json:"Required"`
+	PrimitiveType     string `json:"PrimitiveType"`
+	Type              string `json:"Type"`
+	ItemType          string `json:"ItemType"`
+	PrimitiveItemType string `json:"PrimitiveItemType"`
+	UpdateType        string `json:"UpdateType"`
+}
+
+//
+// AttributeSpecification
+//
+
+type AttributeSpecification struct {
+	PrimitiveType     string `json:"PrimitiveType"`
+	Type              string `json:"Type"`
+	PrimitiveItemType string `json:"PrimitiveItemType"`
+}
@@ -0,0 +1,146 @@
+package cloudformation_test
+
+import (
+	contextpkg "context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-puccini/tosca/grammars/cloudformation"
+)
+
+const testSpecification = `{
+  "ResourceTypes": {
+    "AWS::S3::Bucket": {
+      "Properties": {
+        "BucketName": { "PrimitiveType": "String", "Required": false },
+        "Tags": { "Type": "List", "ItemType": "Tag", "Required": false }
+      },
+      "Attributes": {
+        "Arn": { "PrimitiveType": "String" }
+      }
+    },
+    "AWS::EC2::VPC": {
+      "Properties": {
+        "CidrBlock": { "PrimitiveType": "String", "Required": true }
+      }
+    }
+  }
+}`
+
+func TestSpecificationRead(t *testing.T) {
+	specification := cloudformation.NewSpecification()
+	if !specification.IsEmpty() {
+		t.Errorf("new specification not empty")
+	}
+
+	readTestSpecification(t, specification, testSpecification)
+	readTestSpecification(t, specification, `{
+  "ResourceTypes": {
+    "AWS::EC2::VPC": {
+      "Properties": {
+        "CidrBlock": { "PrimitiveType": "String", "Required": false }
+      }
+    }
+  },
+  "PropertyTypes": {}
+}`)
+
+	if specification.IsEmpty() {
+		t.Fatalf("specification empty")
+	}
+
+	// Resource types read later override those read earlier
+	if resourceType, ok := specification.ResourceTypes["AWS::EC2::VPC"]; ok {
+		if resourceType.Properties["CidrBlock"].Required {
+			t.Errorf("resource type not overridden")
+		}
+	} else {
+		t.Errorf("resource type missing")
+	}
+
+	if resourceType, ok := specification.ResourceTypes["AWS::S3::Bucket"]; ok {
+		if property := resourceType.Properties["Tags"]; (property == nil) || (property.Type != "List") || (property.ItemType != "Tag") {
+			t.Errorf("property not read")
+		}
+		if attribute := resourceType.Attributes["Arn"]; (attribute == nil) || (attribute.PrimitiveType != "String") {
+			t.Errorf("attribute not read")
+		}
+	} else {
+		t.Errorf("resource type missing")
+	}
+}
+
+func TestSpecificationReadErrors(t *testing.T) {
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	specification := cloudformation.NewSpecification()
+	context := contextpkg.Background()
+
+	if err := specification.Read(context, urlContext.NewFileURL(filepath.Join(t.TempDir(), "missing.json"))); err == nil {
+		t.Errorf("missing file not reported")
+	}
+
+	path := writeTestSpecification(t, `{ "ResourceTypes": [ ] }`)
+	if err := specification.Read(context, urlContext.NewFileURL(path)); err == nil {
+		t.Errorf("malformed specification not reported")
+	} else if !strings.Contains(err.Error(), path) {
+		t.Errorf("error does not mention the URL: %s", err.Error())
+	}
+}
+
+func TestSpecificationValidation(t *testing.T) {
+	specification := cloudformation.NewSpecification()
+	readTestSpecification(t, specification, testSpecification)
+
+	defaultSpecification := cloudformation.DefaultSpecification
+	cloudformation.DefaultSpecification = specification
+	defer func() {
+		cloudformation.DefaultSpecification = defaultSpecification
+	}()
+
+	for name, test := range map[string]struct {
+		resources string
+		valid     bool
+	}{
+		"valid":              {"Bucket:\n    Type: AWS::S3::Bucket\n    Properties:\n      BucketName: hello", true},
+		"custom resource":    {"Custom:\n    Type: Custom::Thing\n    Properties:\n      Anything: hello", true},
+		"known attribute":    {"Bucket:\n    Type: AWS::S3::Bucket\n  Other:\n    Type: AWS::S3::Bucket\n    Properties:\n      BucketName: !GetAtt Bucket.Arn", true},
+		"unknown type":       {"Bucket:\n    Type: AWS::S3::Missing", false},
+		"undeclared":         {"Bucket:\n    Type: AWS::S3::Bucket\n    Properties:\n      Missing: hello", false},
+		"required missing":   {"Vpc:\n    Type: AWS::EC2::VPC", false},
+		"unknown attribute":  {"Bucket:\n    Type: AWS::S3::Bucket\n  Other:\n    Type: AWS::S3::Bucket\n    Properties:\n      BucketName: !GetAtt Bucket.Missing", false},
+		"required satisfied": {"Vpc:\n    Type: AWS::EC2::VPC\n    Properties:\n      CidrBlock: 10.0.0.0/16", true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, problems := compileTestTemplate(t, "AWSTemplateFormatVersion: 2010-09-09\nResources:\n  "+test.resources+"\n", nil, false)
+			if test.valid && !problems.Empty() {
+				t.Errorf("%s", problems.ToString(true))
+			} else if !test.valid && problems.Empty() {
+				t.Errorf("not reported")
+			}
+		})
+	}
+}
+
+// Utils
+
+func readTestSpecification(t *testing.T, specification *cloudformation.Specification, content string) {
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	if err := specification.Read(contextpkg.Background(), urlContext.NewFileURL(writeTestSpecification(t, content))); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+}
+
+func writeTestSpecification(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "specification.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	return path
+}
@@ -0,0 +1,96 @@
+package cloudformation
+
+import (
+	"strings"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/yamlkeys"
+	"gopkg.in/yaml.v3"
+)
+
+//
+// Short form
+//
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference.html]
+//
+
+// The YAML decoder discards local tags, so we recover the short form of intrinsic functions
+// (e.g. "!Ref name") from the YAML nodes and convert them to the full form (e.g. "Ref: name").
+func ApplyShortForm(data ard.Value, locator ard.Locator) ard.Value {
+	if yamlLocator, ok := locator.(*ard.YAMLLocator); ok && (yamlLocator.RootNode != nil) {
+		return applyShortForm(yamlLocator.RootNode, data)
+	}
+	return data
+}
+
+func applyShortForm(node *yaml.Node, data ard.Value) ard.Value {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 1 {
+			return applyShortForm(node.Content[0], data)
+		}
+		return data
+
+	case yaml.MappingNode:
+		if map_, ok := data.(ard.Map); ok {
+			for index := 0; index+1 < len(node.Content); index += 2 {
+				keyNode := node.Content[index]
+				if key, value, ok := findMapEntry(map_, keyNode.Value); ok {
+					map_[key] = applyShortForm(node.Content[index+1], value)
+				}
+			}
+		}
+
+	case yaml.SequenceNode:
+		if list, ok := data.(ard.List); ok && (len(list) == len(node.Content)) {
+			for index, elementNode := range node.Content {
+				list[index] = applyShortForm(elementNode, list[index])
+			}
+		}
+	}
+
+	if name, ok := getShortFormFunctionName(node.Tag); ok {
+		if name == "Fn::GetAtt" {
+			// The short form of "Fn::GetAtt" can be a single string with a "."
+			if data_, ok := data.(string); ok {
+				if split := strings.SplitN(data_, ".", 2); len(split) == 2 {
+					data = ard.List{split[0], split[1]}
+				}
+			}
+		}
+
+		return ard.Map{name: data}
+	}
+
+	return data
+}
+
+// Utils
+
+func getShortFormFunctionName(tag string) (string, bool) {
+	if !strings.HasPrefix(tag, "!") || strings.HasPrefix(tag, "!!") {
+		return "", false
+	}
+
+	switch name := tag[1:]; name {
+	case "Ref", "Condition":
+		return name, true
+	default:
+		return "Fn::" + name, true
+	}
+}
+
+func findMapEntry(map_ ard.Map, name string) (any, ard.Value, bool) {
+	if value, ok := map_[name]; ok {
+		return name, value, true
+	}
+
+	// Non-string keys
+	for key, value := range map_ {
+		if yamlkeys.KeyString(key) == name {
+			return key, value, true
+		}
+	}
+
+	return nil, nil, false
+}
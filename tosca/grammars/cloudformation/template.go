@@ -0,0 +1,171 @@
+package cloudformation
+
+import (
+	"time"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/yamlkeys"
+)
+
+//
+// Template
+//
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/template-anatomy.html]
+//
+
+type Template struct {
+	*Entity `name:"template"`
+
+	AWSTemplateFormatVersion *string
+	Description              *string    `read:"Description"`
+	Metadata                 *Data      `read:"Metadata,Data"`
+	Transform                *Data      `read:"Transform,Data"`
+	Parameters               Parameters `read:"Parameters,Parameter"`
+	Rules                    *Data      `read:"Rules,Data"`
+	Mappings                 *Data      `read:"Mappings,Data"`
+	Conditions               Values     `read:"Conditions,Value"`
+	Resources                Resources  `read:"Resources,Resource" mandatory:""`
+	Outputs                  Outputs    `read:"Outputs,Output"`
+}
+
+func NewTemplate(context *parsing.Context) *Template {
+	self := &Template{
+		Entity:     NewEntity(context),
+		Parameters: make(Parameters),
+		Conditions: make(Values),
+		Outputs:    make(Outputs),
+	}
+
+	self.Context.ImportScriptlet("tosca.lib.utils", "internal:/profiles/common/1.0/js/lib/utils.js")
+	self.Context.ImportScriptlet("tosca.lib.traversal", "internal:/profiles/common/1.0/js/lib/traversal.js")
+	self.Context.ImportScriptlet("tosca.resolve", "internal:/profiles/common/1.0/js/resolve.js")
+	self.Context.ImportScriptlet("tosca.coerce", "internal:/profiles/common/1.0/js/coerce.js")
+
+	// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/pseudo-parameter-reference.html]
+	self.NewPseudoParameter("AWS::AccountId", "String", "account_id")
+	self.NewPseudoParameter("AWS::NotificationARNs", "CommaDelimitedList", ard.List{})
+	self.NewPseudoParameter("AWS::Partition", "String", "aws")
+	self.NewPseudoParameter("AWS::Region", "String", "region")
+	self.NewPseudoParameter("AWS::StackId", "String", "stack_id")
+	self.NewPseudoParameter("AWS::StackName", "String", "stack_name")
+	self.NewPseudoParameter("AWS::URLSuffix", "String", "amazonaws.com")
+
+	return self
+}
+
+// ([parsing.Reader] signature)
+func ReadTemplate(context *parsing.Context) parsing.EntityPtr {
+	// Intrinsic functions may be written in short form (e.g. "!Ref")
+	context.Data = ApplyShortForm(context.Data, context.Locator)
+
+	self := NewTemplate(context)
+	context.ScriptletNamespace.Merge(DefaultScriptletNamespace)
+
+	if versionContext, ok := context.GetFieldChild("AWSTemplateFormatVersion"); ok {
+		switch data := versionContext.Data.(type) {
+		case time.Time:
+			versionContext.Data = data.Format("2006-01-02")
+		}
+
+		if versionContext.Is(ard.TypeString) {
+			self.AWSTemplateFormatVersion = versionContext.ReadString()
+		} else {
+			versionContext.ReportValueWrongType(ard.TypeString, ard.TypeTimestamp)
+		}
+	}
+
+	context.ValidateUnsupportedFields(append(context.ReadFields(self), "AWSTemplateFormatVersion"))
+
+	if self.Transform != nil {
+		// Transforms (macros) are processed by CloudFormation before deployment
+		log.Warning("unsupported template section: Transform")
+	}
+
+	if self.Rules != nil {
+		log.Warning("unsupported template section: Rules")
+	}
+
+	if self.Mappings != nil {
+		validateMappings(self.Mappings.Context)
+	}
+
+	return self
+}
+
+// ([parsing.Renderable] interface)
+func (self *Template) Render() {
+	self.renderOnce.Do(self.render)
+}
+
+func (self *Template) render() {
+	logRender.Debug("template")
+
+	for _, parameter := range self.Parameters {
+		if (parameter.Value == nil) && (parameter.Default == nil) {
+			parameter.Context.ReportValueRequired("parameter")
+		}
+	}
+}
+
+func (self *Template) NewPseudoParameter(name string, type_ string, value ard.Value) {
+	context := self.Context.FieldChild("Parameters", nil).MapChild(name, ard.Map{
+		"Type": type_,
+	})
+	parameter := ReadParameter(context).(*Parameter)
+	parameter.Value = NewValue(context.Clone(value))
+	self.Parameters[name] = parameter
+}
+
+// parsing.HasInputs interface
+func (self *Template) SetInputs(inputs map[string]ard.Value) {
+	context := self.Context.FieldChild("Parameters", nil)
+	for name, data := range inputs {
+		childContext := context.MapChild(name, data)
+		if parameter, ok := self.Parameters[name]; ok {
+			parameter.Value = ReadValue(childContext).(*Value)
+		} else {
+			childContext.ReportUndeclared("parameter")
+		}
+	}
+}
+
+// normal.Normalizable interface
+func (self *Template) NormalizeServiceTemplate() *normal.ServiceTemplate {
+	logNormalize.Debug("template")
+
+	normalServiceTemplate := normal.NewServiceTemplate()
+
+	if self.Description != nil {
+		normalServiceTemplate.Description = *self.Description
+	}
+
+	normalServiceTemplate.ScriptletNamespace = self.Context.ScriptletNamespace
+
+	self.Parameters.Normalize(normalServiceTemplate.Inputs, self.Context.FieldChild("Parameters", nil))
+	self.Outputs.Normalize(normalServiceTemplate.Outputs, self.Context.FieldChild("Outputs", nil))
+	self.Resources.Normalize(normalServiceTemplate)
+
+	return normalServiceTemplate
+}
+
+// Utils
+
+// Mappings are maps of maps of values
+func validateMappings(context *parsing.Context) {
+	if !context.ValidateType(ard.TypeMap) {
+		return
+	}
+
+	for key, mapping := range context.Data.(ard.Map) {
+		mappingContext := context.MapChild(yamlkeys.KeyString(key), mapping)
+		if !mappingContext.ValidateType(ard.TypeMap) {
+			continue
+		}
+
+		for key_, values := range mapping.(ard.Map) {
+			mappingContext.MapChild(yamlkeys.KeyString(key_), values).ValidateType(ard.TypeMap)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package cloudformation_test
+
+import (
+	"testing"
+
+	"github.com/tliron/go-ard"
+)
+
+func TestPseudoParameters(t *testing.T) {
+	clout, problems := compileTestTemplate(t, `
+AWSTemplateFormatVersion: 2010-09-09
+Parameters:
+  Password:
+    Type: String
+    NoEcho: true
+    Default: secret
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+`, nil, false)
+	if !problems.Empty() {
+		t.Fatalf("%s", problems.ToString(true))
+	}
+
+	inputs, _ := ard.With(clout.Properties).Get("tosca", "inputs").StringMap()
+	for name, hidden := range map[string]bool{
+		"Password":       true,
+		"AWS::Region":    false,
+		"AWS::StackName": false,
+	} {
+		hidden_, _ := ard.With(inputs[name]).Get("$meta", "metadata", "hidden").String()
+		if (hidden_ == "true") != hidden {
+			t.Errorf("%s: expected hidden to be %t", name, hidden)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package cloudformation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tliron/go-ard"
+)
+
+//
+// Parameter types
+//
+// [https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/parameters-section-structure.html#parameters-section-structure-properties-type]
+//
+
+var ParameterTypes = []string{
+	"CommaDelimitedList",
+	"List<Number>",
+	"Number",
+	"String",
+}
+
+func IsParameterTypeValid(type_ string) bool {
+	for _, t := range ParameterTypes {
+		if t == type_ {
+			return true
+		}
+	}
+
+	// AWS-specific parameter types and SSM parameter types
+	return strings.HasPrefix(type_, "AWS::") || strings.HasPrefix(type_, "List<AWS::")
+}
+
+func IsListParameterType(type_ string) bool {
+	return (type_ == "CommaDelimitedList") || strings.HasPrefix(type_, "List<")
+}
+
+func (self *Value) ValidateParameterType(type_ string) bool {
+	switch type_ {
+	case "Number":
+		return self.Context.ValidateType(ard.TypeInteger, ard.TypeFloat)
+
+	case "List<Number>":
+		if self.Context.ValidateType(ard.TypeList) {
+			for index, e := range self.Context.Data.(ard.List) {
+				if !self.Context.ListChild(index, e).ValidateType(ard.TypeInteger, ard.TypeFloat) {
+					return false
+				}
+			}
+			return true
+		} else {
+			return false
+		}
+
+	default:
+		if IsListParameterType(type_) {
+			if self.Context.ValidateType(ard.TypeList) {
+				for index, e := range self.Context.Data.(ard.List) {
+					if !self.Context.ListChild(index, e).ValidateType(ard.TypeString) {
+						return false
+					}
+				}
+				return true
+			} else {
+				return false
+			}
+		}
+
+		return self.Context.ValidateType(ard.TypeString)
+	}
+}
+
+// CloudFormation parameter values are always provided as strings, so we convert them to the
+// declared type
+func (self *Value) CoerceParameterType(type_ string) {
+	switch type_ {
+	case "Number":
+		self.Context.Data = coerceNumber(self.Context.Data)
+
+	case "List<Number>":
+		switch data := self.Context.Data.(type) {
+		case string:
+			list := splitCommaDelimitedList(data)
+			for index, e := range list {
+				list[index] = coerceNumber(e)
+			}
+			self.Context.Data = list
+
+		case ard.List:
+			for index, e := range data {
+				data[index] = coerceNumber(e)
+			}
+		}
+
+	default:
+		if IsListParameterType(type_) {
+			if data, ok := self.Context.Data.(string); ok {
+				self.Context.Data = splitCommaDelimitedList(data)
+			}
+		} else {
+			switch data := self.Context.Data.(type) {
+			case int, int64, uint64, float64, bool:
+				self.Context.Data = fmt.Sprintf("%v", data)
+			}
+		}
+	}
+}
+
+// Utils
+
+func coerceNumber(data ard.Value) ard.Value {
+	if string_, ok := data.(string); ok {
+		string_ = strings.TrimSpace(string_)
+		if integer, err := strconv.ParseInt(string_, 10, 64); err == nil {
+			return integer
+		} else if float, err := strconv.ParseFloat(string_, 64); err == nil {
+			return float
+		}
+	}
+	return data
+}
+
+func splitCommaDelimitedList(data string) ard.List {
+	split := strings.Split(data, ",")
+	list := make(ard.List, len(split))
+	for index, s := range split {
+		list[index] = strings.TrimSpace(s)
+	}
+	return list
+}
@@ -68,14 +68,16 @@ func (self *Blueprint) NormalizeServiceTemplate() *normal.ServiceTemplate {
 
 	self.Inputs.Normalize(normalServiceTemplate.Inputs, self.Context.FieldChild("inputs", nil))
 	self.Outputs.Normalize(normalServiceTemplate.Outputs)
+	self.Capabilities.Normalize(normalServiceTemplate.Capabilities)
 	self.NodeTemplates.Normalize(normalServiceTemplate)
+	self.Workflows.Normalize(normalServiceTemplate) // before groups, because group policy triggers may refer to workflows
 	self.Groups.Normalize(normalServiceTemplate)
-	self.Workflows.Normalize(normalServiceTemplate)
 	self.Policies.Normalize(normalServiceTemplate)
+	NormalizePlugins(self.Context, normalServiceTemplate)
 
-	// TODO: normalize plugins
-	// TODO: normalize upload resources
-	// TODO: normalize capabilities
+	if self.UploadResources != nil {
+		self.UploadResources.Normalize(normalServiceTemplate)
+	}
 
 	return normalServiceTemplate
 }
@@ -1,6 +1,8 @@
 package cloudify_v1_3
 
 import (
+	contextpkg "context"
+
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
 
@@ -19,7 +21,7 @@ type File struct {
 	Inputs                  Inputs             `read:"inputs,Input"`
 	NodeTemplates           NodeTemplates      `read:"node_templates,NodeTemplate"`
 	NodeTypes               NodeTypes          `read:"node_types,NodeType" hierarchy:""`
-	Capabilities            []*ValueDefinition `read:"capability,ValueDefinition"`
+	Capabilities            ValueDefinitions   `read:"capabilities,ValueDefinition"`
 	Outputs                 ValueDefinitions   `read:"outputs,ValueDefinition"`
 	RelationshipTypes       RelationshipTypes  `read:"relationships,RelationshipType" hierarchy:""`
 	Plugins                 Plugins            `read:"plugins,Plugin"`
@@ -33,9 +35,10 @@ type File struct {
 
 func NewFile(context *parsing.Context) *File {
 	return &File{
-		Entity:  NewEntity(context),
-		Inputs:  make(Inputs),
-		Outputs: make(ValueDefinitions),
+		Entity:       NewEntity(context),
+		Inputs:       make(Inputs),
+		Outputs:      make(ValueDefinitions),
+		Capabilities: make(ValueDefinitions),
 	}
 }
 
@@ -48,7 +51,7 @@ func ReadFile(context *parsing.Context) parsing.EntityPtr {
 }
 
 // ([parsing.Importer] interface)
-func (self *File) GetImportSpecs() []*parsing.ImportSpec {
+func (self *File) GetImportSpecs(context contextpkg.Context) []*parsing.ImportSpec {
 	var importSpecs = make([]*parsing.ImportSpec, 0, len(self.Imports))
 	for _, import_ := range self.Imports {
 		if importSpec, ok := import_.NewImportSpec(self); ok {
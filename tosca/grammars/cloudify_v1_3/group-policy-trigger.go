@@ -1,17 +1,19 @@
 package cloudify_v1_3
 
 import (
+	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
 
 //
-// GroupPolicyTriggerTrigger
+// GroupPolicyTrigger
 //
 // [https://docs.cloudify.co/5.0.5/developer/blueprints/spec-groups/]
 //
 
 type GroupPolicyTrigger struct {
 	*Entity `name:"group policy trigger"`
+	Name    string
 
 	PolicyTriggerTypeName *string `read:"type" mandatory:""`
 	Parameters            Values  `read:"parameters,Value"`
@@ -22,6 +24,7 @@ type GroupPolicyTrigger struct {
 func NewGroupPolicyTrigger(context *parsing.Context) *GroupPolicyTrigger {
 	return &GroupPolicyTrigger{
 		Entity:     NewEntity(context),
+		Name:       context.Name,
 		Parameters: make(Values),
 	}
 }
@@ -33,8 +36,48 @@ func ReadGroupPolicyTrigger(context *parsing.Context) parsing.EntityPtr {
 	return self
 }
 
+// ([parsing.Mappable] interface)
+func (self *GroupPolicyTrigger) GetKey() string {
+	return self.Name
+}
+
+var executeWorkflowPolicyTriggerTypeName = "cloudify.policies.triggers.execute_workflow"
+
+func (self *GroupPolicyTrigger) Normalize(normalPolicy *normal.Policy) *normal.PolicyTrigger {
+	logNormalize.Debugf("group policy trigger: %s", self.Name)
+
+	normalTrigger := normalPolicy.NewTrigger(self.Name)
+
+	if types, ok := normal.GetEntityTypes(self.Context.Hierarchy, self.PolicyTriggerType); ok {
+		normalTrigger.Types = types
+	}
+
+	self.Parameters.Normalize(normalTrigger.Parameters, "")
+
+	// Delegate to the executed workflow
+	if _, ok := normalTrigger.Types[executeWorkflowPolicyTriggerTypeName]; ok {
+		if workflow, ok := self.Parameters["workflow"]; ok {
+			if workflowName, ok := workflow.Context.Data.(string); ok {
+				// Note: workflows declared in imports are not normalized
+				if normalWorkflow, ok := normalPolicy.ServiceTemplate.Workflows[workflowName]; ok {
+					normalAction := normalTrigger.NewAction()
+					normalAction.DelegateWorkflow = normalWorkflow
+				}
+			}
+		}
+	}
+
+	return normalTrigger
+}
+
 //
 // GroupPolicyTriggers
 //
 
 type GroupPolicyTriggers map[string]*GroupPolicyTrigger
+
+func (self GroupPolicyTriggers) Normalize(normalPolicy *normal.Policy) {
+	for _, trigger := range self {
+		trigger.Normalize(normalPolicy)
+	}
+}
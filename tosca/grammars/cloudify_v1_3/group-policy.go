@@ -1,6 +1,7 @@
 package cloudify_v1_3
 
 import (
+	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
 
@@ -12,6 +13,7 @@ import (
 
 type GroupPolicy struct {
 	*Entity `name:"group policy"`
+	Name    string
 
 	PolicyTypeName *string             `read:"type" mandatory:""`
 	Properties     Values              `read:"properties,Value"`
@@ -23,6 +25,7 @@ type GroupPolicy struct {
 func NewGroupPolicy(context *parsing.Context) *GroupPolicy {
 	return &GroupPolicy{
 		Entity:     NewEntity(context),
+		Name:       context.Name,
 		Properties: make(Values),
 		Triggers:   make(GroupPolicyTriggers),
 	}
@@ -35,8 +38,40 @@ func ReadGroupPolicy(context *parsing.Context) parsing.EntityPtr {
 	return self
 }
 
+// ([parsing.Mappable] interface)
+func (self *GroupPolicy) GetKey() string {
+	return self.Name
+}
+
+// The normal policy is named "<group name>.<policy name>" and targets the group
+func (self *GroupPolicy) Normalize(group *Group, normalServiceTemplate *normal.ServiceTemplate) *normal.Policy {
+	logNormalize.Debugf("group policy: %s.%s", group.Name, self.Name)
+
+	normalPolicy := normalServiceTemplate.NewPolicy(group.Name + "." + self.Name)
+
+	if types, ok := normal.GetEntityTypes(self.Context.Hierarchy, self.PolicyType); ok {
+		normalPolicy.Types = types
+	}
+
+	self.Properties.Normalize(normalPolicy.Properties, "")
+
+	if normalGroup, ok := normalServiceTemplate.Groups[group.Name]; ok {
+		normalPolicy.GroupTargets = append(normalPolicy.GroupTargets, normalGroup)
+	}
+
+	self.Triggers.Normalize(normalPolicy)
+
+	return normalPolicy
+}
+
 //
 // GroupPolicies
 //
 
 type GroupPolicies map[string]*GroupPolicy
+
+func (self GroupPolicies) Normalize(group *Group, normalServiceTemplate *normal.ServiceTemplate) {
+	for _, policy := range self {
+		policy.Normalize(group, normalServiceTemplate)
+	}
+}
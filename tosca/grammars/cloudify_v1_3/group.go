@@ -51,8 +51,7 @@ func (self *Group) Normalize(normalServiceTemplate *normal.ServiceTemplate) *nor
 		}
 	}
 
-	// TODO: normalize policies
-	// TODO: normalize triggers in policies
+	self.Policies.Normalize(self, normalServiceTemplate)
 
 	return normalGroup
 }
@@ -0,0 +1,211 @@
+package cloudify_v1_3_test
+
+import (
+	contextpkg "context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-ard"
+	cloutpkg "github.com/tliron/go-puccini/clout"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parser"
+)
+
+const testBlueprint = `
+tosca_definitions_version: cloudify_dsl_1_3
+
+plugins:
+
+  used:
+    executor: central_deployment_agent
+    install: false
+
+  unused:
+    executor: host_agent
+    install: false
+
+node_templates:
+
+  host:
+    type: cloudify.nodes.Compute
+    interfaces:
+      cloudify.interfaces.lifecycle:
+        create: used.tasks.create
+
+  db:
+    type: cloudify.nodes.DBMS
+    relationships:
+    - type: cloudify.relationships.contained_in
+      target: host
+      target_interfaces:
+        cloudify.interfaces.relationship_lifecycle:
+          establish: used.tasks.establish
+
+  app:
+    type: cloudify.nodes.ApplicationModule
+
+workflows:
+
+  repair: used.workflows.repair
+
+groups:
+
+  host_and_db:
+    members: [ host, db ]
+    policies:
+      failure:
+        type: cloudify.policies.types.host_failure
+        properties:
+          service: [ service ]
+        triggers:
+          heal:
+            type: cloudify.policies.triggers.execute_workflow
+            parameters:
+              workflow: repair
+              workflow_parameters: {}
+`
+
+func TestPluginNodeTemplates(t *testing.T) {
+	_, clout := parseTestBlueprint(t)
+
+	// Operations of node templates and of their relationships, and workflows
+	used := getTestVertex(t, clout, "Plugin", "used")
+	if names := getTestEdgeTargetNames(used, "PluginUser"); !equalTestNames(names, "db", "host", "repair") {
+		t.Errorf("plugin \"used\" has wrong users: %v", names)
+	}
+
+	unused := getTestVertex(t, clout, "Plugin", "unused")
+	if names := getTestEdgeTargetNames(unused, "PluginUser"); len(names) != 0 {
+		t.Errorf("plugin \"unused\" has users: %v", names)
+	}
+}
+
+func TestGroupPolicies(t *testing.T) {
+	normalServiceTemplate, clout := parseTestBlueprint(t)
+
+	normalPolicy, ok := normalServiceTemplate.Policies["host_and_db.failure"]
+	if !ok {
+		t.Fatalf("group policy not normalized")
+	}
+	if _, ok := normalPolicy.Types["cloudify.policies.types.host_failure"]; !ok {
+		t.Errorf("group policy has wrong types: %v", normalPolicy.Types)
+	}
+	if _, ok := normalPolicy.Properties["service"]; !ok {
+		t.Errorf("group policy properties not normalized")
+	}
+
+	policy := getTestVertex(t, clout, "Policy", "host_and_db.failure")
+	if names := getTestEdgeTargetNames(policy, "GroupTarget"); !equalTestNames(names, "host_and_db") {
+		t.Errorf("group policy has wrong targets: %v", names)
+	}
+
+	// Trigger
+	if len(normalPolicy.Triggers) != 1 {
+		t.Fatalf("expected 1 trigger, got %d", len(normalPolicy.Triggers))
+	}
+	normalTrigger := normalPolicy.Triggers[0]
+	if normalTrigger.Name != "heal" {
+		t.Errorf("trigger has wrong name: %s", normalTrigger.Name)
+	}
+	if _, ok := normalTrigger.Types["cloudify.policies.triggers.execute_workflow"]; !ok {
+		t.Errorf("trigger has wrong types: %v", normalTrigger.Types)
+	}
+	if normalTrigger.Event != "" {
+		t.Errorf("trigger has an event: %s", normalTrigger.Event)
+	}
+	if _, ok := normalTrigger.Parameters["workflow"]; !ok {
+		t.Errorf("trigger parameters not normalized")
+	}
+
+	// Delegates to the workflow
+	if (len(normalTrigger.Actions) != 1) || (normalTrigger.Actions[0].DelegateWorkflow == nil) || (normalTrigger.Actions[0].DelegateWorkflow.Name != "repair") {
+		t.Errorf("trigger does not delegate to workflow \"repair\"")
+	}
+}
+
+func TestServiceTemplateWithoutCloudifyFields(t *testing.T) {
+	data, err := json.Marshal(normal.NewServiceTemplate())
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	var serviceTemplate map[string]any
+	if err := json.Unmarshal(data, &serviceTemplate); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	for _, key := range []string{"capabilities", "plugins", "uploadResources"} {
+		if _, ok := serviceTemplate[key]; ok {
+			t.Errorf("empty %q is not omitted", key)
+		}
+	}
+}
+
+// Utils
+
+func parseTestBlueprint(t *testing.T) (*normal.ServiceTemplate, *cloutpkg.Clout) {
+	path := filepath.Join(t.TempDir(), "blueprint.yaml")
+	if err := os.WriteFile(path, []byte(testBlueprint), 0600); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	urlContext := exturl.NewContext()
+	t.Cleanup(func() {
+		urlContext.Release()
+	})
+
+	parserContext := parser.NewParser().NewContext()
+	parserContext.URL = urlContext.NewFileURL(path)
+	normalServiceTemplate, err := parserContext.Parse(contextpkg.TODO())
+	if err != nil {
+		t.Fatalf("%s\n%s", err.Error(), parserContext.GetProblems().ToString(true))
+	}
+
+	clout, err := normalServiceTemplate.Compile()
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	return normalServiceTemplate, clout
+}
+
+func getTestVertex(t *testing.T, clout *cloutpkg.Clout, kind string, name string) *cloutpkg.Vertex {
+	for _, vertex := range clout.Vertexes {
+		if isTestKind(vertex.Metadata, kind) && (vertex.Properties["name"] == name) {
+			return vertex
+		}
+	}
+	t.Fatalf("no %s vertex named %q", kind, name)
+	return nil
+}
+
+func getTestEdgeTargetNames(vertex *cloutpkg.Vertex, kind string) []string {
+	var names []string
+	for _, edge := range vertex.EdgesOut {
+		if isTestKind(edge.Metadata, kind) {
+			names = append(names, edge.Target.Properties["name"].(string))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isTestKind(metadata ard.StringMap, kind string) bool {
+	kind_, _ := ard.With(metadata).Get("puccini", "kind").String()
+	return kind_ == kind
+}
+
+func equalTestNames(names []string, expected ...string) bool {
+	if len(names) != len(expected) {
+		return false
+	}
+	for index, name := range names {
+		if name != expected[index] {
+			return false
+		}
+	}
+	return true
+}
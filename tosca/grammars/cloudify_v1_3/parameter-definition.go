@@ -80,6 +80,18 @@ func (self *ParameterDefinition) render() {
 	}
 }
 
+func (self *ParameterDefinition) Normalize(context *parsing.Context) normal.Value {
+	var value *Value
+	if self.Default != nil {
+		value = self.Default
+	} else {
+		// Parameters should always appear, even if they have no default value
+		value = NewValue(context.MapChild(self.Name, nil))
+	}
+
+	return value.Normalize()
+}
+
 func (self *ParameterDefinition) GetNormalDataType() *normal.ValueMeta {
 	normalDataType := normal.NewValueMeta()
 	if self.Description != nil {
@@ -94,6 +106,12 @@ func (self *ParameterDefinition) GetNormalDataType() *normal.ValueMeta {
 
 type ParameterDefinitions map[string]*ParameterDefinition
 
+func (self ParameterDefinitions) Normalize(normalValues normal.Values, context *parsing.Context) {
+	for key, definition := range self {
+		normalValues[key] = definition.Normalize(context)
+	}
+}
+
 func (self ParameterDefinitions) Inherit(parentDefinitions ParameterDefinitions) {
 	for name, definition := range parentDefinitions {
 		if _, ok := self[name]; !ok {
@@ -1,6 +1,9 @@
 package cloudify_v1_3
 
 import (
+	"strings"
+
+	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
 
@@ -62,8 +65,93 @@ func ReadPlugin(context *parsing.Context) parsing.EntityPtr {
 	return self
 }
 
+func (self *Plugin) Normalize(normalServiceTemplate *normal.ServiceTemplate) *normal.Plugin {
+	logNormalize.Debugf("plugin: %s", self.Name)
+
+	normalPlugin := normalServiceTemplate.NewPlugin(self.Name)
+
+	setPluginProperty(normalPlugin, "executor", self.Executor)
+	setPluginProperty(normalPlugin, "source", self.Source)
+	setPluginProperty(normalPlugin, "install_arguments", self.InstallArguments)
+	if self.Install != nil {
+		normalPlugin.Properties["install"] = normal.NewPrimitive(*self.Install)
+	}
+	setPluginProperty(normalPlugin, "package_name", self.PackageName)
+	setPluginProperty(normalPlugin, "package_version", self.PackageVersion)
+	setPluginProperty(normalPlugin, "supported_platform", self.SupportedPlatform)
+	setPluginProperty(normalPlugin, "distribution", self.Distribution)
+	setPluginProperty(normalPlugin, "distribution_version", self.DistributionVersion)
+	setPluginProperty(normalPlugin, "distribution_release", self.DistributionRelease)
+
+	// Operations and workflows use the plugin via their implementation (e.g. "myplugin.tasks.create")
+
+	for _, normalNodeTemplate := range normalServiceTemplate.NodeTemplates {
+		if usesPlugin(self.Name, normalNodeTemplate) {
+			normalPlugin.NodeTemplates = append(normalPlugin.NodeTemplates, normalNodeTemplate)
+		}
+	}
+
+	for _, normalWorkflow := range normalServiceTemplate.Workflows {
+		if isPluginImplementation(self.Name, normalWorkflow.Implementation) {
+			normalPlugin.Workflows = append(normalPlugin.Workflows, normalWorkflow)
+		}
+	}
+
+	return normalPlugin
+}
+
 //
 // Plugins
 //
 
 type Plugins []*Plugin
+
+// Note: includes plugins declared in imported files
+func NormalizePlugins(context *parsing.Context, normalServiceTemplate *normal.ServiceTemplate) {
+	context.Namespace.Range(func(entityPtr parsing.EntityPtr) bool {
+		if plugin, ok := entityPtr.(*Plugin); ok {
+			if _, ok := normalServiceTemplate.Plugins[plugin.Name]; !ok {
+				plugin.Normalize(normalServiceTemplate)
+			}
+		}
+		return true
+	})
+}
+
+// Utils
+
+func setPluginProperty(normalPlugin *normal.Plugin, name string, value *string) {
+	if value != nil {
+		normalPlugin.Properties[name] = normal.NewPrimitive(*value)
+	}
+}
+
+// Includes the operations of the node template's relationships
+func usesPlugin(name string, normalNodeTemplate *normal.NodeTemplate) bool {
+	if interfacesUsePlugin(name, normalNodeTemplate.Interfaces) {
+		return true
+	}
+
+	for _, normalRequirement := range normalNodeTemplate.Requirements {
+		if (normalRequirement.Relationship != nil) && interfacesUsePlugin(name, normalRequirement.Relationship.Interfaces) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func interfacesUsePlugin(name string, normalInterfaces normal.Interfaces) bool {
+	for _, normalInterface := range normalInterfaces {
+		for _, normalOperation := range normalInterface.Operations {
+			if isPluginImplementation(name, normalOperation.Implementation) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isPluginImplementation(name string, implementation string) bool {
+	return strings.HasPrefix(implementation, name+".")
+}
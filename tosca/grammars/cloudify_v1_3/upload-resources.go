@@ -2,6 +2,7 @@ package cloudify_v1_3
 
 import (
 	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
 
@@ -45,3 +46,28 @@ func ReadUploadResources(context *parsing.Context) parsing.EntityPtr {
 
 	return self
 }
+
+func (self *UploadResources) Normalize(normalServiceTemplate *normal.ServiceTemplate) *normal.UploadResources {
+	logNormalize.Debug("upload resources")
+
+	normalUploadResources := normalServiceTemplate.NewUploadResources()
+
+	if self.PluginResources != nil {
+		normalUploadResources.PluginResources = append(normalUploadResources.PluginResources, *self.PluginResources...)
+	}
+
+	for _, dslResource := range self.DSLResources {
+		normalDSLResource := new(normal.DSLResource)
+		if dslResource.SourcePath != nil {
+			normalDSLResource.SourcePath = *dslResource.SourcePath
+		}
+		if dslResource.DestinationPath != nil {
+			normalDSLResource.DestinationPath = *dslResource.DestinationPath
+		}
+		normalUploadResources.DSLResources = append(normalUploadResources.DSLResources, normalDSLResource)
+	}
+
+	self.Parameters.Normalize(normalUploadResources.Parameters, "")
+
+	return normalUploadResources
+}
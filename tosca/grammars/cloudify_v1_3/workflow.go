@@ -49,10 +49,11 @@ func (self *Workflow) Normalize(normalServiceTemplate *normal.ServiceTemplate) *
 
 	normalWorkflow := normalServiceTemplate.NewWorkflow(self.Name)
 
-	// TODO: mapping
+	if self.Mapping != nil {
+		normalWorkflow.Implementation = *self.Mapping
+	}
 
-	// TODO: support property definitions
-	//self.ParameterDefinitions.Normalize(w.Inputs)
+	self.ParameterDefinitions.Normalize(normalWorkflow.Inputs, self.Context.FieldChild("parameters", nil))
 
 	return normalWorkflow
 }
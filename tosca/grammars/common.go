@@ -9,3 +9,6 @@ var Grammars = make(map[string]map[string]*parsing.Grammar)
 
 // Map of keyword -> version -> internal URL path
 var ImplicitProfilePaths = make(map[string]map[string]string)
+
+// Grammars with a detector, in order of registration
+var DetectableGrammars []*parsing.Grammar
@@ -0,0 +1,43 @@
+package compose
+
+import (
+	"github.com/tliron/commonlog"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+var log = commonlog.GetLogger("puccini.grammars.compose")
+var logRender = commonlog.NewScopeLogger(log, "render")
+var logNormalize = commonlog.NewScopeLogger(log, "normalize")
+
+// Note: Compose files have no version keyword, so this grammar is not detected by version, but by
+// [IsComposeFile]
+var Grammar = parsing.NewGrammar()
+
+var DefaultScriptletNamespace = parsing.NewScriptletNamespace()
+
+func init() {
+	Grammar.RegisterDetector(IsComposeFile)
+
+	Grammar.RegisterReader("$Root", ReadTemplate)
+
+	Grammar.RegisterReader("Data", ReadData)
+	Grammar.RegisterReader("Network", ReadNetwork)
+	Grammar.RegisterReader("Service", ReadService)
+	Grammar.RegisterReader("Template", ReadTemplate)
+	Grammar.RegisterReader("Value", ReadValue)
+	Grammar.RegisterReader("Volume", ReadVolume)
+
+	DefaultScriptletNamespace.RegisterScriptlets(FunctionScriptlets, nil)
+}
+
+// Compose files are too generic to be detected by default, so they are detected only with the
+// "grammars.compose" quirk, in which case they must have "services"
+//
+// ([parsing.GrammarDetector] signature)
+func IsComposeFile(context *parsing.Context) bool {
+	if context.HasQuirk(parsing.QuirkGrammarsCompose) {
+		_, ok := context.GetFieldChild("services")
+		return ok
+	}
+	return false
+}
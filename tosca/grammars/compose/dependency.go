@@ -0,0 +1,119 @@
+package compose
+
+import (
+	"reflect"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/yamlkeys"
+)
+
+//
+// Dependency
+//
+// A service's reference to another service ("depends_on" and "links"), a network ("networks"),
+// or a named volume ("volumes")
+//
+
+type Dependency struct {
+	*Entity `name:"dependency"`
+
+	Requirement string
+	TargetName  string
+	Properties  Values
+
+	Target parsing.EntityPtr `traverse:"ignore" json:"-" yaml:"-"`
+}
+
+func NewDependency(context *parsing.Context, requirement string, targetName string) *Dependency {
+	return &Dependency{
+		Entity:      NewEntity(context),
+		Requirement: requirement,
+		TargetName:  targetName,
+		Properties:  make(Values),
+	}
+}
+
+// ([parsing.Renderable] interface)
+func (self *Dependency) Render() {
+	self.renderOnce.Do(self.render)
+}
+
+func (self *Dependency) render() {
+	var type_ reflect.Type
+	var kind string
+	switch self.Requirement {
+	case "network":
+		type_ = reflect.TypeOf((*Network)(nil))
+		kind = "network"
+	case "volume":
+		type_ = reflect.TypeOf((*Volume)(nil))
+		kind = "volume"
+	default:
+		type_ = reflect.TypeOf((*Service)(nil))
+		kind = "service"
+	}
+
+	if target, ok := self.Context.Namespace.LookupForType(self.TargetName, type_); ok {
+		self.Target = target
+	} else {
+		self.Context.Clone(self.TargetName).ReportUnknown(kind)
+	}
+}
+
+func (self *Dependency) readProperties(context *parsing.Context, keys ...string) {
+	if !context.ValidateType(ard.TypeMap) {
+		return
+	}
+
+	context.ValidateUnsupportedFields(keys)
+	for key, data := range context.Data.(ard.Map) {
+		name := yamlkeys.KeyString(key)
+		self.Properties[name] = ReadValue(context.FieldChild(name, data)).(*Value)
+	}
+}
+
+func (self *Dependency) Normalize(normalNodeTemplate *normal.NodeTemplate, normalServiceTemplate *normal.ServiceTemplate) {
+	var targetName string
+	var capabilityTypeName string
+	var relationshipTypeName string
+	switch target := self.Target.(type) {
+	case *Service:
+		targetName = target.Name
+		capabilityTypeName = ServiceCapabilityType
+		if self.Requirement == "link" {
+			relationshipTypeName = LinkRelationshipType
+		} else {
+			relationshipTypeName = DependsOnRelationshipType
+		}
+
+	case *Network:
+		targetName = target.GetNodeTemplateName()
+		capabilityTypeName = NetworkCapabilityType
+		relationshipTypeName = NetworkRelationshipType
+
+	case *Volume:
+		targetName = target.GetNodeTemplateName()
+		capabilityTypeName = VolumeCapabilityType
+		relationshipTypeName = VolumeRelationshipType
+
+	default:
+		// Unknown target (already reported)
+		return
+	}
+
+	normalRequirement := normalNodeTemplate.NewRequirement(self.Requirement, normal.NewLocationForContext(self.Context))
+	normalRequirement.NodeTemplate = normalServiceTemplate.NodeTemplates[targetName]
+	normalRequirement.CapabilityTypeName = &capabilityTypeName
+
+	normalRelationship := normalRequirement.NewRelationship()
+	normalRelationship.Types = NewEntityTypes(relationshipTypeName)
+	self.Properties.Normalize(normalRelationship.Properties)
+}
+
+//
+// Dependencies
+//
+
+type Dependencies []*Dependency
@@ -0,0 +1,196 @@
+package compose
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/assets/tosca/profiles"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/yamlkeys"
+)
+
+//
+// Interpolation
+//
+// [https://docs.docker.com/reference/compose-file/interpolation/]
+//
+
+const functionPathPrefix = "compose/1.0/js/functions/"
+
+var FunctionScriptlets = map[string]string{
+	parsing.MetadataFunctionPrefix + "interpolate": profiles.GetString(functionPathPrefix + "interpolate.js"),
+}
+
+// Submatches: escape ("$$"), braced name, operator, argument, unbraced name
+var interpolationRegexp = regexp.MustCompile(`\$(?:(\$)|\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?+])([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+//
+// Variable
+//
+
+type Variable struct {
+	Name     string
+	Operator string
+	Argument string
+}
+
+// Variables that must be set (or not empty) in order for interpolation to succeed
+func (self *Variable) IsRequired() bool {
+	return (self.Operator == "?") || (self.Operator == ":?")
+}
+
+func GetVariables(string_ string) []*Variable {
+	var variables []*Variable
+	for _, match := range interpolationRegexp.FindAllStringSubmatch(string_, -1) {
+		switch {
+		case match[2] != "":
+			variables = append(variables, &Variable{
+				Name:     match[2],
+				Operator: match[3],
+				Argument: match[4],
+			})
+
+		case match[5] != "":
+			variables = append(variables, &Variable{Name: match[5]})
+		}
+	}
+	return variables
+}
+
+// Gathers all variables in the data, keyed by name
+func GatherVariables(data ard.Value, variables map[string][]*Variable) {
+	switch data_ := data.(type) {
+	case string:
+		for _, variable := range GetVariables(data_) {
+			variables[variable.Name] = append(variables[variable.Name], variable)
+		}
+
+	case ard.List:
+		for _, element := range data_ {
+			GatherVariables(element, variables)
+		}
+
+	case ard.Map:
+		for _, value := range data_ {
+			GatherVariables(value, variables)
+		}
+	}
+}
+
+// Interpolates the string with the variables, like the "interpolate" function does at runtime.
+// Unset required variables are interpolated as empty strings (they are reported when rendering).
+func Interpolate(string_ string, variables map[string]ard.Value) string {
+	return interpolationRegexp.ReplaceAllStringFunc(string_, func(match string) string {
+		submatch := interpolationRegexp.FindStringSubmatch(match)
+		if submatch[1] != "" {
+			return "$"
+		}
+
+		name := submatch[2]
+		if name == "" {
+			name = submatch[5]
+		}
+		operator := submatch[3]
+		argument := submatch[4]
+
+		var value string
+		value_, ok := variables[name]
+		unset := !ok || (value_ == nil)
+		if !unset {
+			value = yamlkeys.KeyString(value_)
+		}
+		empty := unset || (value == "")
+
+		switch operator {
+		case ":-":
+			if empty {
+				return argument
+			}
+		case "-":
+			if unset {
+				return argument
+			}
+		case ":+":
+			if empty {
+				return ""
+			}
+			return argument
+		case "+":
+			if unset {
+				return ""
+			}
+			return argument
+		}
+
+		return value
+	})
+}
+
+func ParseInterpolation(context *parsing.Context) bool {
+	if _, ok := context.Data.(*parsing.FunctionCall); ok {
+		// It's already a function call
+		return true
+	}
+
+	string_, ok := context.Data.(string)
+	if !ok || !strings.Contains(string_, "$") {
+		return false
+	}
+
+	// Anything left after removing the valid interpolations is malformed
+	if strings.Contains(interpolationRegexp.ReplaceAllString(string_, ""), "${") {
+		context.ReportValueMalformed("interpolation", "invalid \"${\"")
+		return false
+	}
+
+	if len(GetVariables(string_)) == 0 {
+		// Only escapes
+		context.Data = strings.ReplaceAll(string_, "$$", "$")
+		return false
+	}
+
+	context.Data = context.NewFunctionCall(parsing.MetadataFunctionPrefix+"interpolate", ard.List{string_})
+	return true
+}
+
+func ParseInterpolations(context *parsing.Context) bool {
+	changed := false
+	if ParseInterpolation(context) {
+		changed = true
+	} else if list, ok := context.Data.(ard.List); ok {
+		for index, value := range list {
+			childContext := context.ListChild(index, value)
+			if ParseInterpolations(childContext) {
+				changed = true
+			}
+			list[index] = childContext.Data
+		}
+	} else if map_, ok := context.Data.(ard.Map); ok {
+		for key, value := range map_ {
+			childContext := context.MapChild(key, value)
+			if ParseInterpolations(childContext) {
+				changed = true
+			}
+			yamlkeys.MapPut(map_, key, childContext.Data) // support complex keys
+		}
+	}
+	return changed
+}
+
+func NormalizeFunctionCallArguments(functionCall *parsing.FunctionCall, context *parsing.Context) {
+	for index, argument := range functionCall.Arguments {
+		// Because the same function call instance may be shared among more than one value, this
+		// func might be called more than once on the same arguments, so we must make sure not
+		// to normalize more than once
+		if _, ok := argument.(normal.Value); !ok {
+			if value, ok := argument.(*Value); ok {
+				functionCall.Arguments[index] = value.Normalize()
+			} else {
+				// Note: this literal value will not have a $type field
+				functionCall.Arguments[index] = NewValue(context.ListChild(index, argument)).Normalize()
+			}
+		}
+	}
+}
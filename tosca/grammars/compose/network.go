@@ -0,0 +1,83 @@
+package compose
+
+import (
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/yamlkeys"
+)
+
+//
+// Network
+//
+// [https://docs.docker.com/reference/compose-file/networks/]
+//
+
+type Network struct {
+	*Entity `name:"network"`
+	Name    string `namespace:""`
+
+	Properties Values
+}
+
+func NewNetwork(context *parsing.Context) *Network {
+	return &Network{
+		Entity:     NewEntity(context),
+		Name:       context.Name,
+		Properties: make(Values),
+	}
+}
+
+// ([parsing.Reader] signature)
+func ReadNetwork(context *parsing.Context) parsing.EntityPtr {
+	self := NewNetwork(context)
+
+	// A null definition means all defaults
+	if (context.Data != nil) && context.ValidateType(ard.TypeMap) {
+		for key, data := range context.Data.(ard.Map) {
+			name := yamlkeys.KeyString(key)
+			if !IsExtensionKey(name) {
+				self.Properties[name] = ReadValue(context.FieldChild(name, data)).(*Value)
+			}
+		}
+	}
+
+	return self
+}
+
+// ([parsing.Mappable] interface)
+func (self *Network) GetKey() string {
+	return self.Name
+}
+
+// Networks are in a separate namespace from services, so their node template names are prefixed
+func (self *Network) GetNodeTemplateName() string {
+	return "networks." + self.Name
+}
+
+func (self *Network) Normalize(normalServiceTemplate *normal.ServiceTemplate) *normal.NodeTemplate {
+	logNormalize.Debugf("network: %s", self.Name)
+
+	normalNodeTemplate := normalServiceTemplate.NewNodeTemplate(self.GetNodeTemplateName())
+	normalNodeTemplate.Types = NewEntityTypes(NetworkNodeType)
+	normalNodeTemplate.Metadata["compose.name"] = self.Name
+
+	self.Properties.Normalize(normalNodeTemplate.Properties)
+
+	capabilityContext := self.Context.FieldChild("capabilities", nil).MapChild("network", nil)
+	normalNodeTemplate.NewCapability("network", normal.NewLocationForContext(capabilityContext)).Types = NewEntityTypes(NetworkCapabilityType)
+
+	return normalNodeTemplate
+}
+
+//
+// Networks
+//
+
+type Networks []*Network
+
+func (self Networks) Normalize(normalServiceTemplate *normal.ServiceTemplate) {
+	for _, network := range self {
+		normalServiceTemplate.NodeTemplates[network.GetNodeTemplateName()] = network.Normalize(normalServiceTemplate)
+	}
+}
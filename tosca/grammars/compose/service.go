@@ -0,0 +1,523 @@
+package compose
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/yamlkeys"
+)
+
+// [https://docs.docker.com/reference/compose-file/services/]
+var ServiceKeys = []string{
+	"annotations",
+	"attach",
+	"blkio_config",
+	"build",
+	"cap_add",
+	"cap_drop",
+	"cgroup",
+	"cgroup_parent",
+	"command",
+	"configs",
+	"container_name",
+	"cpu_count",
+	"cpu_percent",
+	"cpu_period",
+	"cpu_quota",
+	"cpu_rt_period",
+	"cpu_rt_runtime",
+	"cpu_shares",
+	"cpus",
+	"cpuset",
+	"credential_spec",
+	"depends_on",
+	"deploy",
+	"develop",
+	"device_cgroup_rules",
+	"devices",
+	"dns",
+	"dns_opt",
+	"dns_search",
+	"domainname",
+	"driver_opts",
+	"entrypoint",
+	"env_file",
+	"environment",
+	"expose",
+	"extends",
+	"external_links",
+	"extra_hosts",
+	"gpus",
+	"group_add",
+	"healthcheck",
+	"hostname",
+	"image",
+	"init",
+	"ipc",
+	"isolation",
+	"labels",
+	"label_file",
+	"links",
+	"logging",
+	"mac_address",
+	"mem_limit",
+	"mem_reservation",
+	"mem_swappiness",
+	"memswap_limit",
+	"models",
+	"network_mode",
+	"networks",
+	"oom_kill_disable",
+	"oom_score_adj",
+	"pid",
+	"pids_limit",
+	"platform",
+	"ports",
+	"post_start",
+	"pre_stop",
+	"privileged",
+	"profiles",
+	"provider",
+	"pull_policy",
+	"read_only",
+	"restart",
+	"runtime",
+	"scale",
+	"secrets",
+	"security_opt",
+	"shm_size",
+	"stdin_open",
+	"stop_grace_period",
+	"stop_signal",
+	"storage_opt",
+	"sysctls",
+	"tmpfs",
+	"tty",
+	"ulimits",
+	"use_api_socket",
+	"user",
+	"userns_mode",
+	"uts",
+	"volumes",
+	"volumes_from",
+	"working_dir",
+}
+
+func IsServiceKey(key string) bool {
+	for _, key_ := range ServiceKeys {
+		if key_ == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Extension keys ("x-") can appear at the top level and in most sections
+func IsExtensionKey(key string) bool {
+	return strings.HasPrefix(key, "x-")
+}
+
+// Services that do not specify networks are attached to this network
+const DefaultNetworkName = "default"
+
+//
+// Service
+//
+// [https://docs.docker.com/reference/compose-file/services/]
+//
+
+type Service struct {
+	*Entity `name:"service"`
+	Name    string `namespace:""`
+
+	Properties   Values
+	Dependencies Dependencies `traverse:"ignore" json:"-" yaml:"-"`
+}
+
+func NewService(context *parsing.Context) *Service {
+	return &Service{
+		Entity:     NewEntity(context),
+		Name:       context.Name,
+		Properties: make(Values),
+	}
+}
+
+// ([parsing.Reader] signature)
+func ReadService(context *parsing.Context) parsing.EntityPtr {
+	self := NewService(context)
+
+	if context.Data == nil {
+		// An empty service is allowed (it would likely be completed by "extends" or an override file)
+		context.Data = make(ard.Map)
+	}
+
+	if !context.ValidateType(ard.TypeMap) {
+		return self
+	}
+
+	for key, data := range context.Data.(ard.Map) {
+		name := yamlkeys.KeyString(key)
+		childContext := context.FieldChild(name, data)
+
+		switch name {
+		case "depends_on":
+			self.readDependsOn(childContext)
+
+		case "links":
+			self.readLinks(childContext)
+
+		case "networks":
+			self.readNetworks(childContext)
+
+		case "volumes":
+			self.readVolumes(childContext)
+
+		case "environment":
+			self.Properties[name] = ReadValue(childContext.Clone(readEnvironment(childContext))).(*Value)
+
+		case "ports":
+			self.Properties[name] = ReadValue(childContext.Clone(readPorts(childContext))).(*Value)
+
+		default:
+			if IsServiceKey(name) {
+				self.Properties[name] = ReadValue(childContext).(*Value)
+			} else if !IsExtensionKey(name) {
+				childContext.ReportKeynameUnsupported()
+			}
+		}
+	}
+
+	// Services are attached to the default network unless told otherwise
+	if _, ok := self.Properties["network_mode"]; !ok {
+		if _, ok := context.Data.(ard.Map)["networks"]; !ok {
+			self.Dependencies = append(self.Dependencies, NewDependency(context.FieldChild("networks", nil).MapChild(DefaultNetworkName, nil), "network", DefaultNetworkName))
+		}
+	}
+
+	return self
+}
+
+// ([parsing.Mappable] interface)
+func (self *Service) GetKey() string {
+	return self.Name
+}
+
+// ([parsing.Renderable] interface)
+func (self *Service) Render() {
+	self.renderOnce.Do(self.render)
+}
+
+func (self *Service) render() {
+	logRender.Debugf("service: %s", self.Name)
+
+	for _, dependency := range self.Dependencies {
+		dependency.Render()
+
+		if dependency.Target == self {
+			dependency.Context.ReportValueMalformed("dependency", "service refers to itself")
+		}
+	}
+}
+
+func (self *Service) readDependsOn(context *parsing.Context) {
+	switch data := context.Data.(type) {
+	case ard.List:
+		for index, name := range data {
+			childContext := context.ListChild(index, name)
+			if childContext.ValidateType(ard.TypeString) {
+				self.Dependencies = append(self.Dependencies, NewDependency(childContext, "depends_on", name.(string)))
+			}
+		}
+
+	case ard.Map:
+		for key, data_ := range data {
+			name := yamlkeys.KeyString(key)
+			childContext := context.MapChild(name, data_)
+			dependency := NewDependency(childContext, "depends_on", name)
+			if data_ != nil {
+				dependency.readProperties(childContext, "condition", "restart", "required")
+			}
+			self.Dependencies = append(self.Dependencies, dependency)
+		}
+
+	default:
+		context.ReportValueWrongType(ard.TypeList, ard.TypeMap)
+	}
+}
+
+// Links are "SERVICE" or "SERVICE:ALIAS"
+func (self *Service) readLinks(context *parsing.Context) {
+	if !context.ValidateType(ard.TypeList) {
+		return
+	}
+
+	for index, link := range context.Data.(ard.List) {
+		childContext := context.ListChild(index, link)
+		if !childContext.ValidateType(ard.TypeString) {
+			continue
+		}
+
+		name, alias, hasAlias := strings.Cut(link.(string), ":")
+		dependency := NewDependency(childContext, "link", name)
+		if hasAlias {
+			dependency.Properties["alias"] = NewValue(childContext.FieldChild("alias", alias))
+		}
+		self.Dependencies = append(self.Dependencies, dependency)
+	}
+}
+
+func (self *Service) readNetworks(context *parsing.Context) {
+	switch data := context.Data.(type) {
+	case ard.List:
+		for index, name := range data {
+			childContext := context.ListChild(index, name)
+			if childContext.ValidateType(ard.TypeString) {
+				self.Dependencies = append(self.Dependencies, NewDependency(childContext, "network", name.(string)))
+			}
+		}
+
+	case ard.Map:
+		for key, data_ := range data {
+			name := yamlkeys.KeyString(key)
+			childContext := context.MapChild(name, data_)
+			dependency := NewDependency(childContext, "network", name)
+			if data_ != nil {
+				dependency.readProperties(childContext, "aliases", "driver_opts", "gw_priority", "interface_name", "ipv4_address", "ipv6_address", "link_local_ips", "mac_address", "priority")
+			}
+			self.Dependencies = append(self.Dependencies, dependency)
+		}
+
+	default:
+		context.ReportValueWrongType(ard.TypeList, ard.TypeMap)
+	}
+}
+
+// Volumes are normalized to the long syntax, and named volumes become dependencies
+func (self *Service) readVolumes(context *parsing.Context) {
+	if !context.ValidateType(ard.TypeList) {
+		return
+	}
+
+	volumes := make(ard.List, len(context.Data.(ard.List)))
+	for index, volume := range context.Data.(ard.List) {
+		childContext := context.ListChild(index, volume)
+
+		switch volume_ := volume.(type) {
+		case string:
+			if strings.Contains(volume_, "$") {
+				// Will be interpolated, so we cannot parse it now
+				volumes[index] = volume_
+				continue
+			}
+			volume = parseVolumeShortSyntax(volume_)
+
+		case ard.Map:
+
+		default:
+			childContext.ReportValueWrongType(ard.TypeString, ard.TypeMap)
+			continue
+		}
+
+		volumes[index] = volume
+
+		volume_ := ard.With(volume)
+		if type_, _ := volume_.Get("type").String(); type_ == "volume" {
+			if source, ok := volume_.Get("source").String(); ok && !strings.Contains(source, "$") {
+				dependency := NewDependency(childContext, "volume", source)
+				if target, ok := volume_.Get("target").String(); ok {
+					dependency.Properties["target"] = NewValue(childContext.FieldChild("target", target))
+				}
+				if readOnly, ok := volume_.Get("read_only").Boolean(); ok {
+					dependency.Properties["read_only"] = NewValue(childContext.FieldChild("read_only", readOnly))
+				}
+				self.Dependencies = append(self.Dependencies, dependency)
+			}
+		}
+	}
+
+	self.Properties["volumes"] = ReadValue(context.Clone(volumes)).(*Value)
+}
+
+func (self *Service) Normalize(normalServiceTemplate *normal.ServiceTemplate) *normal.NodeTemplate {
+	logNormalize.Debugf("service: %s", self.Name)
+
+	normalNodeTemplate := normalServiceTemplate.NewNodeTemplate(self.Name)
+	normalNodeTemplate.Types = NewEntityTypes(ServiceNodeType)
+
+	self.Properties.Normalize(normalNodeTemplate.Properties)
+
+	capabilityContext := self.Context.FieldChild("capabilities", nil).MapChild("service", nil)
+	normalNodeTemplate.NewCapability("service", normal.NewLocationForContext(capabilityContext)).Types = NewEntityTypes(ServiceCapabilityType)
+
+	return normalNodeTemplate
+}
+
+func (self *Service) NormalizeDependencies(normalServiceTemplate *normal.ServiceTemplate) {
+	logNormalize.Debugf("service dependencies: %s", self.Name)
+
+	normalNodeTemplate := normalServiceTemplate.NodeTemplates[self.Name]
+	for _, dependency := range self.Dependencies {
+		dependency.Normalize(normalNodeTemplate, normalServiceTemplate)
+	}
+}
+
+//
+// Services
+//
+
+type Services []*Service
+
+func (self Services) Normalize(normalServiceTemplate *normal.ServiceTemplate) {
+	for _, service := range self {
+		normalServiceTemplate.NodeTemplates[service.Name] = service.Normalize(normalServiceTemplate)
+	}
+}
+
+// Dependencies must be normalized after all node templates
+// (because they may reference any of them)
+func (self Services) NormalizeDependencies(normalServiceTemplate *normal.ServiceTemplate) {
+	for _, service := range self {
+		service.NormalizeDependencies(normalServiceTemplate)
+	}
+}
+
+// Utils
+
+// Environment lists ("NAME=VALUE" or "NAME") are normalized to maps
+func readEnvironment(context *parsing.Context) ard.Value {
+	switch data := context.Data.(type) {
+	case ard.List:
+		environment := make(ard.Map)
+		for index, variable := range data {
+			childContext := context.ListChild(index, variable)
+			if childContext.ValidateType(ard.TypeString) {
+				if name, value, ok := strings.Cut(variable.(string), "="); ok {
+					environment[name] = value
+				} else {
+					// Taken from the host environment
+					environment[name] = nil
+				}
+			}
+		}
+		return environment
+
+	case ard.Map:
+		return data
+
+	default:
+		context.ReportValueWrongType(ard.TypeList, ard.TypeMap)
+		return make(ard.Map)
+	}
+}
+
+// Ports are normalized to the long syntax. They are interpolated first (they cannot be parsed
+// otherwise), so changing their variables later will have no effect.
+func readPorts(context *parsing.Context) ard.Value {
+	if !context.ValidateType(ard.TypeList) {
+		return ard.List{}
+	}
+
+	ports := make(ard.List, len(context.Data.(ard.List)))
+	for index, port := range context.Data.(ard.List) {
+		switch port_ := port.(type) {
+		case string:
+			ports[index] = parsePortShortSyntax(Interpolate(port_, context.Inputs))
+
+		case int, int64, uint64:
+			ports[index] = parsePortNumbers(ard.Map{"target": yamlkeys.KeyString(port_)})
+
+		case ard.Map:
+			map_ := make(ard.Map)
+			for key, value := range port_ {
+				if value_, ok := value.(string); ok {
+					value = Interpolate(value_, context.Inputs)
+				}
+				map_[key] = value
+			}
+			ports[index] = parsePortNumbers(map_)
+
+		default:
+			context.ListChild(index, port).ReportValueWrongType(ard.TypeString, ard.TypeInteger, ard.TypeMap)
+			ports[index] = port
+		}
+	}
+	return ports
+}
+
+// "[HOST_IP:][PUBLISHED:]TARGET[/PROTOCOL]"
+// [https://docs.docker.com/reference/compose-file/services/#short-syntax-3]
+func parsePortShortSyntax(port string) ard.Map {
+	port_ := make(ard.Map)
+
+	if port, protocol, ok := strings.Cut(port, "/"); ok {
+		port_["protocol"] = protocol
+		port_["target"] = port
+	} else {
+		port_["target"] = port
+	}
+
+	target := port_["target"].(string)
+	if index := strings.LastIndex(target, ":"); index != -1 {
+		published := target[:index]
+		port_["target"] = target[index+1:]
+		if index := strings.LastIndex(published, ":"); index != -1 {
+			port_["host_ip"] = strings.Trim(published[:index], "[]") // IPv6 addresses are bracketed
+			published = published[index+1:]
+		}
+		if published != "" {
+			port_["published"] = published
+		}
+	}
+
+	return parsePortNumbers(port_)
+}
+
+// Port numbers are integers, but ranges ("START-END") remain strings
+func parsePortNumbers(port ard.Map) ard.Map {
+	for _, key := range []string{"target", "published"} {
+		if number, ok := port[key].(string); ok {
+			if number_, err := strconv.ParseUint(number, 10, 16); err == nil {
+				port[key] = int64(number_)
+			}
+		}
+	}
+	return port
+}
+
+// "[SOURCE:]TARGET[:MODE]"
+// [https://docs.docker.com/reference/compose-file/services/#short-syntax-5]
+func parseVolumeShortSyntax(volume string) ard.Map {
+	volume_ := make(ard.Map)
+
+	split := strings.SplitN(volume, ":", 3)
+	switch len(split) {
+	case 1:
+		// Anonymous volume
+		volume_["type"] = "volume"
+		volume_["target"] = split[0]
+		return volume_
+
+	case 3:
+		for _, mode := range strings.Split(split[2], ",") {
+			if mode == "ro" {
+				volume_["read_only"] = true
+			}
+		}
+	}
+
+	source := split[0]
+	volume_["source"] = source
+	volume_["target"] = split[1]
+
+	// Paths are bind mounts, names are named volumes
+	if strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~") {
+		volume_["type"] = "bind"
+	} else {
+		volume_["type"] = "volume"
+	}
+
+	return volume_
+}
@@ -0,0 +1,212 @@
+package compose
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+//
+// Template
+//
+// [https://docs.docker.com/reference/compose-file/]
+//
+
+type Template struct {
+	*Entity `name:"template"`
+
+	Name     *string  `read:"name"`
+	Services Services `read:"services,Service" mandatory:""`
+	Networks Networks `read:"networks,Network"`
+	Volumes  Volumes  `read:"volumes,Volume"`
+	Configs  *Data    `read:"configs,Data"`
+	Secrets  *Data    `read:"secrets,Data"`
+
+	// Interpolation variables become inputs
+	Variables map[string][]*Variable `traverse:"ignore" json:"-" yaml:"-"`
+	Inputs    Values                 `traverse:"ignore" json:"-" yaml:"-"`
+}
+
+func NewTemplate(context *parsing.Context) *Template {
+	self := &Template{
+		Entity:    NewEntity(context),
+		Variables: make(map[string][]*Variable),
+		Inputs:    make(Values),
+	}
+
+	self.Context.ImportScriptlet("tosca.lib.utils", "internal:/profiles/common/1.0/js/lib/utils.js")
+	self.Context.ImportScriptlet("tosca.lib.traversal", "internal:/profiles/common/1.0/js/lib/traversal.js")
+	self.Context.ImportScriptlet("tosca.resolve", "internal:/profiles/common/1.0/js/resolve.js")
+	self.Context.ImportScriptlet("tosca.coerce", "internal:/profiles/common/1.0/js/coerce.js")
+
+	return self
+}
+
+// ([parsing.Reader] signature)
+func ReadTemplate(context *parsing.Context) parsing.EntityPtr {
+	self := NewTemplate(context)
+	context.ScriptletNamespace.Merge(DefaultScriptletNamespace)
+
+	// Must happen before reading, because reading turns interpolated strings into function calls
+	GatherVariables(context.Data, self.Variables)
+
+	keys := append(context.ReadFields(self), "version")
+	if context.Is(ard.TypeMap) {
+		for key := range context.Data.(ard.Map) {
+			if key_, ok := key.(string); ok && IsExtensionKey(key_) {
+				keys = append(keys, key_)
+			}
+		}
+	}
+	context.ValidateUnsupportedFields(keys)
+
+	if _, ok := context.GetFieldChild("version"); ok {
+		log.Warning("obsolete top-level element: version")
+	}
+
+	self.addDefaultNetwork()
+
+	return self
+}
+
+// ([parsing.Renderable] interface)
+func (self *Template) Render() {
+	self.renderOnce.Do(self.render)
+}
+
+func (self *Template) render() {
+	logRender.Debug("template")
+
+	context := self.Context.FieldChild("variables", nil)
+	for _, name := range self.getVariableNames() {
+		if _, ok := self.Inputs[name]; !ok {
+			for _, variable := range self.Variables[name] {
+				if variable.IsRequired() {
+					context.MapChild(name, nil).ReportValueRequired("variable")
+					break
+				}
+			}
+		}
+	}
+}
+
+// parsing.HasInputs interface
+func (self *Template) SetInputs(inputs map[string]ard.Value) {
+	context := self.Context.FieldChild("variables", nil)
+	for name, data := range inputs {
+		childContext := context.MapChild(name, data)
+		if _, ok := self.Variables[name]; ok {
+			self.Inputs[name] = NewValue(childContext)
+		} else {
+			childContext.ReportUndeclared("variable")
+		}
+	}
+}
+
+// normal.Normalizable interface
+func (self *Template) NormalizeServiceTemplate() *normal.ServiceTemplate {
+	logNormalize.Debug("template")
+
+	normalServiceTemplate := normal.NewServiceTemplate()
+
+	if self.Name != nil {
+		normalServiceTemplate.Metadata["template_name"] = *self.Name
+	}
+
+	normalServiceTemplate.ScriptletNamespace = self.Context.ScriptletNamespace
+
+	self.normalizeInputs(normalServiceTemplate.Inputs)
+	self.Networks.Normalize(normalServiceTemplate)
+	self.Volumes.Normalize(normalServiceTemplate)
+	self.Services.Normalize(normalServiceTemplate)
+	self.Services.NormalizeDependencies(normalServiceTemplate)
+
+	return normalServiceTemplate
+}
+
+// Every variable becomes an input, even if it has no value, so that it can be set later
+func (self *Template) normalizeInputs(normalInputs normal.Values) {
+	context := self.Context.FieldChild("variables", nil)
+	for _, name := range self.getVariableNames() {
+		var normalValue normal.Value
+		if input, ok := self.Inputs[name]; ok {
+			normalValue = input.Normalize()
+		} else {
+			normalValue = NewValue(context.MapChild(name, nil)).Normalize()
+		}
+
+		valueMeta := normal.CopyValueMeta(normalValue.GetMeta())
+		if valueMeta == nil {
+			valueMeta = normal.NewValueMeta()
+		}
+		valueMeta.Description = describeVariable(self.Variables[name])
+		normalValue.SetMeta(valueMeta)
+
+		normalInputs[name] = normalValue
+	}
+}
+
+func (self *Template) getVariableNames() []string {
+	names := make([]string, 0, len(self.Variables))
+	for name := range self.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compose creates a "default" network for services that do not specify networks
+func (self *Template) addDefaultNetwork() {
+	for _, network := range self.Networks {
+		if network.Name == DefaultNetworkName {
+			return
+		}
+	}
+
+	for _, service := range self.Services {
+		for _, dependency := range service.Dependencies {
+			if (dependency.Requirement == "network") && (dependency.TargetName == DefaultNetworkName) {
+				context := self.Context.FieldChild("networks", nil).MapChild(DefaultNetworkName, nil)
+				self.Networks = append(self.Networks, ReadNetwork(context).(*Network))
+				return
+			}
+		}
+	}
+}
+
+// Utils
+
+func describeVariable(variables []*Variable) string {
+	var descriptions []string
+	for _, variable := range variables {
+		var description string
+		switch variable.Operator {
+		case ":-", "-":
+			description = "default: " + variable.Argument
+		case ":?", "?":
+			description = "required"
+			if variable.Argument != "" {
+				description += ": " + variable.Argument
+			}
+		case ":+", "+":
+			description = "alternative: " + variable.Argument
+		}
+
+		if description != "" {
+			found := false
+			for _, description_ := range descriptions {
+				if description_ == description {
+					found = true
+					break
+				}
+			}
+			if !found {
+				descriptions = append(descriptions, description)
+			}
+		}
+	}
+	return strings.Join(descriptions, "; ")
+}
@@ -0,0 +1,67 @@
+package compose
+
+import (
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/assets/tosca/profiles"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/yamlkeys"
+)
+
+//
+// Types
+//
+// Defined in the embedded profile (which can also be imported into TOSCA service templates)
+//
+
+const profilePath = "compose/1.0/profile.yaml"
+
+const (
+	ServiceNodeType           = "compose.nodes.Service"
+	NetworkNodeType           = "compose.nodes.Network"
+	VolumeNodeType            = "compose.nodes.Volume"
+	ServiceCapabilityType     = "compose.capabilities.Service"
+	NetworkCapabilityType     = "compose.capabilities.Network"
+	VolumeCapabilityType      = "compose.capabilities.Volume"
+	DependsOnRelationshipType = "compose.relationships.DependsOn"
+	LinkRelationshipType      = "compose.relationships.Link"
+	NetworkRelationshipType   = "compose.relationships.NetworkAttachment"
+	VolumeRelationshipType    = "compose.relationships.VolumeMount"
+)
+
+// Type name -> parent type name
+var typeParents = readTypeParents()
+
+// Includes the type's ancestors
+func NewEntityTypes(name string) normal.EntityTypes {
+	entityTypes := make(normal.EntityTypes)
+	for name != "" {
+		entityType := normal.NewEntityType(name)
+		entityType.Parent = typeParents[name]
+		entityTypes[name] = entityType
+		name = entityType.Parent
+	}
+	return entityTypes
+}
+
+// Utils
+
+func readTypeParents() map[string]string {
+	typeParents := make(map[string]string)
+
+	data, err := yamlkeys.DecodeString(profiles.GetString(profilePath))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, section := range []string{"capability_types", "relationship_types", "node_types"} {
+		if types, ok := ard.With(data).Get(section).Map(); ok {
+			for name, type_ := range types {
+				if parent, ok := ard.With(type_).Get("derived_from").String(); ok {
+					typeParents[yamlkeys.KeyString(name)] = parent
+				}
+			}
+		}
+	}
+
+	return typeParents
+}
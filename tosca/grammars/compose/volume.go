@@ -0,0 +1,83 @@
+package compose
+
+import (
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/yamlkeys"
+)
+
+//
+// Volume
+//
+// [https://docs.docker.com/reference/compose-file/volumes/]
+//
+
+type Volume struct {
+	*Entity `name:"volume"`
+	Name    string `namespace:""`
+
+	Properties Values
+}
+
+func NewVolume(context *parsing.Context) *Volume {
+	return &Volume{
+		Entity:     NewEntity(context),
+		Name:       context.Name,
+		Properties: make(Values),
+	}
+}
+
+// ([parsing.Reader] signature)
+func ReadVolume(context *parsing.Context) parsing.EntityPtr {
+	self := NewVolume(context)
+
+	// A null definition means all defaults
+	if (context.Data != nil) && context.ValidateType(ard.TypeMap) {
+		for key, data := range context.Data.(ard.Map) {
+			name := yamlkeys.KeyString(key)
+			if !IsExtensionKey(name) {
+				self.Properties[name] = ReadValue(context.FieldChild(name, data)).(*Value)
+			}
+		}
+	}
+
+	return self
+}
+
+// ([parsing.Mappable] interface)
+func (self *Volume) GetKey() string {
+	return self.Name
+}
+
+// Volumes are in a separate namespace from services, so their node template names are prefixed
+func (self *Volume) GetNodeTemplateName() string {
+	return "volumes." + self.Name
+}
+
+func (self *Volume) Normalize(normalServiceTemplate *normal.ServiceTemplate) *normal.NodeTemplate {
+	logNormalize.Debugf("volume: %s", self.Name)
+
+	normalNodeTemplate := normalServiceTemplate.NewNodeTemplate(self.GetNodeTemplateName())
+	normalNodeTemplate.Types = NewEntityTypes(VolumeNodeType)
+	normalNodeTemplate.Metadata["compose.name"] = self.Name
+
+	self.Properties.Normalize(normalNodeTemplate.Properties)
+
+	capabilityContext := self.Context.FieldChild("capabilities", nil).MapChild("volume", nil)
+	normalNodeTemplate.NewCapability("volume", normal.NewLocationForContext(capabilityContext)).Types = NewEntityTypes(VolumeCapabilityType)
+
+	return normalNodeTemplate
+}
+
+//
+// Volumes
+//
+
+type Volumes []*Volume
+
+func (self Volumes) Normalize(normalServiceTemplate *normal.ServiceTemplate) {
+	for _, volume := range self {
+		normalServiceTemplate.NodeTemplates[volume.GetNodeTemplateName()] = volume.Normalize(normalServiceTemplate)
+	}
+}
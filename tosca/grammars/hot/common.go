@@ -36,6 +36,7 @@ func init() {
 	Grammar.RegisterVersion("heat_template_version", "2013-05-23", "") // icehouse
 
 	Grammar.RegisterReader("$Root", ReadTemplate)
+	Grammar.RegisterReader("$File", ReadNestedTemplate)
 
 	Grammar.RegisterReader("Condition", ReadCondition)
 	Grammar.RegisterReader("ConditionDefinition", ReadConditionDefinition)
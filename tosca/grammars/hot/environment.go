@@ -0,0 +1,165 @@
+package hot
+
+import (
+	contextpkg "context"
+	"fmt"
+
+	"github.com/tliron/commonlog"
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-kutil/util"
+	"github.com/tliron/yamlkeys"
+)
+
+//
+// Environment
+//
+// [https://docs.openstack.org/heat/wallaby/template_guide/environment.html]
+//
+
+type Environment struct {
+	Parameters        map[string]ard.Value
+	ParameterDefaults map[string]ard.Value
+	ResourceRegistry  map[string]string
+}
+
+func NewEnvironment() *Environment {
+	return &Environment{
+		Parameters:        make(map[string]ard.Value),
+		ParameterDefaults: make(map[string]ard.Value),
+		ResourceRegistry:  make(map[string]string),
+	}
+}
+
+// Environments read later override the values of environments read earlier, as when providing
+// several environment files to Heat.
+func (self *Environment) Read(context contextpkg.Context, url exturl.URL) error {
+	reader, err := url.Open(context)
+	if err != nil {
+		return err
+	}
+	reader = util.NewContextualReadCloser(context, reader)
+	defer commonlog.CallAndLogWarning(reader.Close, "Environment.Read", log)
+
+	data, err := yamlkeys.DecodeAll(reader)
+	if err != nil {
+		return err
+	}
+
+	for _, data_ := range data {
+		if err := self.Merge(context, data_, url.Base()); err != nil {
+			return fmt.Errorf("%s: %w", url.String(), err)
+		}
+	}
+
+	return nil
+}
+
+// Template paths in the resource registry are relative to the base URL.
+func (self *Environment) Merge(context contextpkg.Context, data ard.Value, base exturl.URL) error {
+	map_, ok := data.(ard.Map)
+	if !ok {
+		return fmt.Errorf("malformed environment: not a map")
+	}
+
+	for key, value := range map_ {
+		switch section := yamlkeys.KeyString(key); section {
+		case "parameters":
+			if err := mergeEnvironmentValues(self.Parameters, section, value); err != nil {
+				return err
+			}
+
+		case "parameter_defaults":
+			if err := mergeEnvironmentValues(self.ParameterDefaults, section, value); err != nil {
+				return err
+			}
+
+		case "resource_registry":
+			if err := self.mergeResourceRegistry(context, value, base); err != nil {
+				return err
+			}
+
+		case "encrypted_param_names", "event_sinks", "parameter_merge_strategies":
+			log.Warningf("unsupported environment section: %s", section)
+
+		default:
+			return fmt.Errorf("malformed environment: unknown section %q", section)
+		}
+	}
+
+	return nil
+}
+
+func (self *Environment) mergeResourceRegistry(context contextpkg.Context, data ard.Value, base exturl.URL) error {
+	if data == nil {
+		// Empty section
+		return nil
+	}
+
+	map_, ok := data.(ard.Map)
+	if !ok {
+		return fmt.Errorf("malformed environment: \"resource_registry\" not a map")
+	}
+
+	// The "base_url" applies to all the mappings in this registry
+	if baseUrl, ok := map_["base_url"]; ok {
+		baseUrl_, ok := baseUrl.(string)
+		if !ok {
+			return fmt.Errorf("malformed environment: \"resource_registry.base_url\" not a string")
+		}
+
+		var err error
+		if base, err = base.Context().NewValidAnyOrFileURL(context, baseUrl_, []exturl.URL{base}); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range map_ {
+		switch from := yamlkeys.KeyString(key); from {
+		case "base_url":
+			// Handled above
+
+		case "resources":
+			log.Warning("unsupported environment section: resource_registry.resources")
+
+		default:
+			to, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("malformed environment: \"resource_registry.%s\" not a string", from)
+			}
+
+			if IsTemplateResourceType(to) {
+				// Make template paths absolute
+				url, err := base.Context().NewValidAnyOrFileURL(context, to, []exturl.URL{base})
+				if err != nil {
+					return err
+				}
+				to = url.String()
+			}
+
+			self.ResourceRegistry[from] = to
+		}
+	}
+
+	return nil
+}
+
+// Utils
+
+func mergeEnvironmentValues(values map[string]ard.Value, section string, data ard.Value) error {
+	if data == nil {
+		// Empty section
+		return nil
+	}
+
+	map_, ok := data.(ard.Map)
+	if !ok {
+		return fmt.Errorf("malformed environment: %q not a map", section)
+	}
+
+	for key, value := range map_ {
+		values[yamlkeys.KeyString(key)] = value
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package hot_test
+
+import (
+	contextpkg "context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/tosca/grammars/hot"
+	"github.com/tliron/yamlkeys"
+)
+
+func TestEnvironmentRead(t *testing.T) {
+	dir := t.TempDir()
+	writeTestEnvironment(t, dir, "first/server.yaml", "heat_template_version: 2021-04-16\n")
+	writeTestEnvironment(t, dir, "second/templates/server.yaml", "heat_template_version: 2021-04-16\n")
+	writeTestEnvironment(t, dir, "first/env.yaml", `
+parameters:
+  flavor: m1.small
+  image: centos7
+parameter_defaults:
+  network: private
+resource_registry:
+  My::Server: server.yaml
+  My::Network: OS::Neutron::Net
+`)
+	writeTestEnvironment(t, dir, "second/env.yaml", `
+parameters:
+  flavor: m1.large
+resource_registry:
+  base_url: templates/
+  My::Server: server.yaml
+`)
+
+	environment := readTestEnvironments(t, filepath.Join(dir, "first/env.yaml"), filepath.Join(dir, "second/env.yaml"))
+
+	// Later environments override earlier ones
+	assertTestValue(t, environment.Parameters, "flavor", "m1.large")
+	assertTestValue(t, environment.Parameters, "image", "centos7")
+	assertTestValue(t, environment.ParameterDefaults, "network", "private")
+
+	// Template paths are relative to the environment file (or to its "base_url")
+	if to := environment.ResourceRegistry["My::Server"]; !strings.HasSuffix(to, "/second/templates/server.yaml") || !strings.HasPrefix(to, "file:") {
+		t.Errorf("template path not made absolute: %s", to)
+	}
+	if to := environment.ResourceRegistry["My::Network"]; to != "OS::Neutron::Net" {
+		t.Errorf("resource type mapped to %q", to)
+	}
+}
+
+func TestEnvironmentMerge(t *testing.T) {
+	environment := hot.NewEnvironment()
+	context := contextpkg.Background()
+
+	mergeTestEnvironment(t, environment, "parameters:\n  count: 1\n")
+	mergeTestEnvironment(t, environment, "parameters:\n  count: 2\nparameter_defaults:\n  count: 3\n")
+	mergeTestEnvironment(t, environment, "parameters:\nresource_registry:\n")
+
+	// Parameters and parameter defaults are kept apart
+	assertTestValue(t, environment.Parameters, "count", 2)
+	assertTestValue(t, environment.ParameterDefaults, "count", 3)
+
+	for _, data := range []string{
+		"- not a map\n",
+		"unknown: {}\n",
+		"parameters: [ not, a, map ]\n",
+		"resource_registry: [ not, a, map ]\n",
+		"resource_registry:\n  My::Type: [ not, a, string ]\n",
+	} {
+		if err := environment.Merge(context, decodeTestEnvironment(t, data), nil); err == nil {
+			t.Errorf("malformed environment not reported:\n%s", data)
+		}
+	}
+}
+
+func TestMapResourceType(t *testing.T) {
+	environment := hot.NewEnvironment()
+	mergeTestEnvironment(t, environment, `
+resource_registry:
+  OS::Networking::*: OS::Neutron::*
+  OS::Networking::FloatingIP: OS::Nova::FloatingIP
+  My::*: OS::Heat::None
+  My::Special::*: OS::Heat::Value
+`)
+
+	for type_, expected := range map[string]string{
+		// Exact mappings take precedence over wildcards
+		"OS::Networking::FloatingIP": "OS::Nova::FloatingIP",
+
+		// A wildcard target is replaced by the rest of the type
+		"OS::Networking::Net":  "OS::Neutron::Net",
+		"OS::Networking::Port": "OS::Neutron::Port",
+
+		// The longest wildcard prefix wins
+		"My::Server":        "OS::Heat::None",
+		"My::Special::Port": "OS::Heat::Value",
+
+		// Unmapped
+		"OS::Nova::Server": "OS::Nova::Server",
+	} {
+		if mapped := hot.MapResourceType(type_, environment.ResourceRegistry); mapped != expected {
+			t.Errorf("%s: expected %q, got %q", type_, expected, mapped)
+		}
+	}
+}
+
+// Utils
+
+func readTestEnvironments(t *testing.T, paths ...string) *hot.Environment {
+	urlContext := exturl.NewContext()
+	t.Cleanup(func() {
+		urlContext.Release()
+	})
+
+	environment := hot.NewEnvironment()
+	for _, path := range paths {
+		if err := environment.Read(contextpkg.Background(), urlContext.NewFileURL(path)); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+	}
+	return environment
+}
+
+func mergeTestEnvironment(t *testing.T, environment *hot.Environment, data string) {
+	if err := environment.Merge(contextpkg.Background(), decodeTestEnvironment(t, data), nil); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+}
+
+func decodeTestEnvironment(t *testing.T, data string) ard.Value {
+	data_, err := yamlkeys.DecodeAll(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	return data_[0]
+}
+
+func writeTestEnvironment(t *testing.T, dir string, path string, content string) {
+	path = filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+}
+
+func assertTestValue(t *testing.T, values map[string]ard.Value, name string, expected ard.Value) {
+	t.Helper()
+
+	if value, ok := values[name]; !ok {
+		t.Errorf("%q not merged", name)
+	} else if value != expected {
+		t.Errorf("%q: expected %v, got %v", name, expected, value)
+	}
+}
@@ -1,6 +1,7 @@
 package hot
 
 import (
+	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
 
@@ -15,7 +16,7 @@ type ParameterGroup struct {
 
 	Label       *string   `read:"label"`
 	Description *string   `read:"description"`
-	Parameters  []*string `read:"parameters"`
+	Parameters  *[]string `read:"parameters"`
 }
 
 func NewParameterGroup(context *parsing.Context) *ParameterGroup {
@@ -29,8 +30,41 @@ func ReadParameterGroup(context *parsing.Context) parsing.EntityPtr {
 	return self
 }
 
+func (self *ParameterGroup) Normalize(template *Template, normalServiceTemplate *normal.ServiceTemplate) *normal.InputGroup {
+	normalInputGroup := normalServiceTemplate.NewInputGroup()
+
+	if self.Label != nil {
+		normalInputGroup.Label = *self.Label
+	}
+
+	if self.Description != nil {
+		normalInputGroup.Description = *self.Description
+	}
+
+	if self.Parameters != nil {
+		parametersContext := self.Context.FieldChild("parameters", nil)
+		for index, name := range *self.Parameters {
+			if _, ok := template.Parameters[name]; ok {
+				normalInputGroup.Inputs = append(normalInputGroup.Inputs, name)
+			} else {
+				parametersContext.ListChild(index, name).ReportUnknown("parameter")
+			}
+		}
+	}
+
+	return normalInputGroup
+}
+
 //
 // ParameterGroups
 //
 
 type ParameterGroups []*ParameterGroup
+
+func (self ParameterGroups) Normalize(template *Template, normalServiceTemplate *normal.ServiceTemplate) {
+	logNormalize.Debug("parameter groups")
+
+	for _, parameterGroup := range self {
+		parameterGroup.Normalize(template, normalServiceTemplate)
+	}
+}
@@ -1,6 +1,9 @@
 package hot
 
 import (
+	"strings"
+
+	"github.com/tliron/go-ard"
 	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
@@ -39,6 +42,11 @@ func ReadParameter(context *parsing.Context) parsing.EntityPtr {
 	self := NewParameter(context)
 	context.ValidateUnsupportedFields(context.ReadFields(self))
 
+	// Environment "parameter_defaults" override the template's defaults
+	if data, ok := context.InputDefaults[self.Name]; ok {
+		self.Default = ReadValue(context.FieldChild("default", ard.Copy(data))).(*Value)
+	}
+
 	if self.Type != nil {
 		type_ := *self.Type
 		if IsParameterTypeValid(type_) {
@@ -77,8 +85,6 @@ func (self *Parameter) render() {
 			}
 			self.Value.Constraints = self.Constraints
 		}
-	} else if self.Default == nil {
-		self.Context.ReportValueRequired("parameter")
 	}
 }
 
@@ -93,8 +99,32 @@ func (self *Parameter) Normalize(context *parsing.Context) normal.Value {
 			value = NewValue(context.MapChild(self.Name, nil))
 		}
 	}
-	// TODO: normalize Hidden, Mutable, etc.
-	return value.Normalize()
+
+	normalValue := value.Normalize()
+
+	// Attributes used for generating input forms
+	valueMeta := normal.CopyValueMeta(normalValue.GetMeta())
+	if valueMeta == nil {
+		valueMeta = normal.NewValueMeta()
+	}
+	if self.Label != nil {
+		valueMeta.Metadata["label"] = *self.Label
+	}
+	if self.Description != nil {
+		valueMeta.Description = *self.Description
+	}
+	if (self.Hidden != nil) && *self.Hidden {
+		valueMeta.Metadata["hidden"] = "true"
+	}
+	if (self.Immutable != nil) && *self.Immutable {
+		valueMeta.Metadata["immutable"] = "true"
+	}
+	if (self.Tags != nil) && (len(*self.Tags) > 0) {
+		valueMeta.Metadata["tags"] = strings.Join(*self.Tags, ",")
+	}
+	normalValue.SetMeta(valueMeta)
+
+	return normalValue
 }
 
 //
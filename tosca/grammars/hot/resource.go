@@ -1,6 +1,13 @@
 package hot
 
 import (
+	contextpkg "context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-ard"
 	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
@@ -40,8 +47,11 @@ type Resource struct {
 	ExternalID     *string    `read:"external_id"`
 	Condition      *Condition `read:"condition,Condition"`
 
-	ToscaType          *string   `json:"-" yaml:"-"`
-	DependsOnResources Resources `lookup:"depends_on,DependsOn" traverse:"ignore" json:"-" yaml:"-"`
+	ToscaType          *string    `json:"-" yaml:"-"`
+	TemplatePath       *string    `json:"-" yaml:"-"`
+	TemplateURL        exturl.URL `traverse:"ignore" json:"-" yaml:"-"`
+	DependsOnResources Resources  `lookup:"depends_on,DependsOn" traverse:"ignore" json:"-" yaml:"-"`
+	Members            Resources  `traverse:"ignore" json:"-" yaml:"-"`
 }
 
 func NewResource(context *parsing.Context) *Resource {
@@ -62,8 +72,14 @@ func ReadResource(context *parsing.Context) parsing.EntityPtr {
 	}
 
 	if self.Type != nil {
-		if type_, ok := ResourceTypes[*self.Type]; ok {
-			self.ToscaType = &type_
+		type_ := MapResourceType(*self.Type, context.TypeMappings)
+		if IsTemplateResourceType(type_) {
+			// Nested stack
+			toscaType := ResourceTypes[stackResourceType]
+			self.ToscaType = &toscaType
+			self.TemplatePath = &type_
+		} else if toscaType, ok := ResourceTypes[type_]; ok {
+			self.ToscaType = &toscaType
 		} else {
 			context.FieldChild("type", *self.Type).ReportKeynameUnsupportedValue()
 		}
@@ -80,9 +96,181 @@ func ReadResource(context *parsing.Context) parsing.EntityPtr {
 	return self
 }
 
+// ([parsing.Renderable] interface)
+func (self *Resource) Render() {
+	self.renderOnce.Do(self.render)
+}
+
+func (self *Resource) render() {
+	logRender.Debugf("resource: %s", self.Name)
+
+	if self.TemplateURL == nil {
+		return
+	}
+
+	// Properties are the nested template's parameters
+	parameters := self.GetTemplateParameters()
+
+	for name, property := range self.Properties {
+		if parameter, ok := parameters[name]; ok {
+			if _, ok := property.Context.Data.(*parsing.FunctionCall); !ok && (parameter.Type != nil) {
+				type_ := *parameter.Type
+				if IsParameterTypeValid(type_) {
+					property.CoerceParameterType(type_)
+					property.ValidateParameterType(type_)
+				}
+			}
+			property.Constraints = parameter.Constraints
+		} else {
+			property.Context.ReportUndeclared("parameter")
+		}
+	}
+
+	propertiesContext := self.Context.FieldChild("properties", nil)
+	for name, parameter := range parameters {
+		if _, ok := self.Properties[name]; !ok && (parameter.Default == nil) {
+			propertiesContext.MapChild(name, nil).ReportValueRequired("property")
+		}
+	}
+}
+
+func (self *Resource) NewImportSpec(context contextpkg.Context) (*parsing.ImportSpec, bool) {
+	if self.TemplatePath == nil {
+		return nil, false
+	}
+
+	base := self.Context.URL.Base()
+	bases := append([]exturl.URL{base}, self.Context.Bases...)
+	url, err := base.Context().NewValidAnyOrFileURL(context, *self.TemplatePath, bases)
+	if err != nil {
+		self.Context.FieldChild("type", *self.Type).ReportError(err)
+		return nil, false
+	}
+
+	self.TemplateURL = url
+
+	importSpec := &parsing.ImportSpec{
+		URL:             url,
+		NameTransformer: newNestedNameTransformer(self.Name),
+		Implicit:        false,
+	}
+	return importSpec, true
+}
+
+// The non-pseudo parameters of the nested template, which are merged into our namespace
+func (self *Resource) GetTemplateParameters() map[string]*Parameter {
+	parameters := make(map[string]*Parameter)
+	if self.TemplateURL == nil {
+		return parameters
+	}
+
+	key := self.TemplateURL.Key()
+	self.Context.Namespace.Range(func(entityPtr parsing.EntityPtr) bool {
+		if parameter, ok := entityPtr.(*Parameter); ok {
+			if (parameter.Context.URL.Key() == key) && !strings.HasPrefix(parameter.Name, "OS::") {
+				parameters[parameter.Name] = parameter
+			}
+		}
+		return true
+	})
+
+	return parameters
+}
+
+func (self *Resource) IsResourceGroup() bool {
+	return (self.ToscaType != nil) && (*self.ToscaType == ResourceTypes[resourceGroupResourceType])
+}
+
+// Creates a resource for each member of an "OS::Heat::ResourceGroup"
+//
+// [https://docs.openstack.org/heat/wallaby/template_guide/openstack.html#OS::Heat::ResourceGroup]
+func (self *Resource) ExpandResourceGroup(template *Template) Resources {
+	count, ok := self.getResourceGroupCount(template)
+	if !ok {
+		log.Warningf("cannot expand resource group: %s", self.Name)
+		return nil
+	}
+
+	resourceDefinition, ok := self.Properties["resource_def"]
+	if !ok {
+		self.Context.FieldChild("properties", nil).MapChild("resource_def", nil).ReportValueRequired("property")
+		return nil
+	}
+	if !resourceDefinition.Context.ValidateType(ard.TypeMap) {
+		return nil
+	}
+
+	indexVar := "%index%"
+	if indexVar_, ok := self.Properties["index_var"]; ok {
+		if indexVar__, ok := indexVar_.Context.Data.(string); ok {
+			indexVar = indexVar__
+		}
+	}
+
+	resourcesContext := self.Context.Parent
+	members := make(Resources, count)
+	for index := 0; index < count; index++ {
+		data := replaceIndexVar(resourceDefinition.Context.Data, indexVar, strconv.Itoa(index))
+		memberContext := resourcesContext.MapChild(fmt.Sprintf("%s.%d", self.Name, index), data)
+		members[index] = ReadResource(memberContext).(*Resource)
+	}
+
+	self.Members = members
+	return members
+}
+
+func (self *Resource) getResourceGroupCount(template *Template) (int, bool) {
+	count, ok := self.Properties["count"]
+	if !ok {
+		// Default
+		return 1, true
+	}
+
+	data := count.Context.Data
+
+	// We can expand the count of literal parameters
+	if functionCall, ok := data.(*parsing.FunctionCall); ok {
+		if (functionCall.Name != parsing.MetadataFunctionPrefix+"get_param") || (len(functionCall.Arguments) != 1) {
+			return 0, false
+		}
+
+		name, ok := functionCall.Arguments[0].(string)
+		if !ok {
+			return 0, false
+		}
+
+		parameter, ok := template.Parameters[name]
+		if !ok {
+			return 0, false
+		}
+
+		// Inputs are only set for the root template (the parameters of nested templates are
+		// provided by the properties of the resources that use them)
+		if parameter.Value != nil {
+			data = parameter.Value.Context.Data
+		} else if input, ok := template.Context.Inputs[name]; ok && !template.Nested {
+			data = input
+		} else if parameter.Default != nil {
+			data = parameter.Default.Context.Data
+		} else {
+			return 0, false
+		}
+	}
+
+	if count_, ok := ard.With(data).ConvertSimilar().Integer(); ok && (count_ >= 0) {
+		return int(count_), true
+	}
+
+	return 0, false
+}
+
+var stackResourceType = "OS::Heat::Stack"
+var resourceGroupResourceType = "OS::Heat::ResourceGroup"
+
 var capabilityTypeName = "Resource"
 var capabilityTypes = normal.NewEntityTypes(capabilityTypeName)
 var relationshipTypes = normal.NewEntityTypes("DependsOn")
+var memberRelationshipTypes = normal.NewEntityTypes("Member")
 
 func (self *Resource) Normalize(normalServiceTemplate *normal.ServiceTemplate) *normal.NodeTemplate {
 	logNormalize.Debugf("resource: %s", self.Name)
@@ -93,6 +281,10 @@ func (self *Resource) Normalize(normalServiceTemplate *normal.ServiceTemplate) *
 		normalNodeTemplate.Types = normal.NewEntityTypes(*self.ToscaType)
 	}
 
+	if self.TemplateURL != nil {
+		normalNodeTemplate.Metadata["hot.template"] = self.TemplateURL.String()
+	}
+
 	self.Properties.Normalize(normalNodeTemplate.Properties)
 
 	capabilityContext := self.Context.FieldChild("capabilities", nil).MapChild("resource", nil)
@@ -115,6 +307,15 @@ func (self *Resource) NormalizeDependencies(normalServiceTemplate *normal.Servic
 		normalRelationship := normalRequirement.NewRelationship()
 		normalRelationship.Types = relationshipTypes
 	}
+
+	for index, member := range self.Members {
+		normalRequirement := normalNodeTemplate.NewRequirement("member", normal.NewLocationForContext(requirementsContext.ListChild(len(self.DependsOnResources)+index, nil)))
+		normalRequirement.NodeTemplate = normalServiceTemplate.NodeTemplates[member.Name]
+		normalRequirement.CapabilityTypeName = &capabilityTypeName
+
+		normalRelationship := normalRequirement.NewRelationship()
+		normalRelationship.Types = memberRelationshipTypes
+	}
 }
 
 //
@@ -134,3 +335,42 @@ func (self Resources) Normalize(normalServiceTemplate *normal.ServiceTemplate) {
 		resource.NormalizeDependencies(normalServiceTemplate)
 	}
 }
+
+// Utils
+
+func newNestedNameTransformer(resourceName string) parsing.NameTransformer {
+	return func(name string, entityPtr parsing.EntityPtr) []string {
+		return []string{fmt.Sprintf("%s::%s", resourceName, name)}
+	}
+}
+
+func replaceIndexVar(data ard.Value, indexVar string, index string) ard.Value {
+	switch data_ := data.(type) {
+	case string:
+		return strings.ReplaceAll(data_, indexVar, index)
+
+	case ard.List:
+		list := make(ard.List, len(data_))
+		for index_, element := range data_ {
+			list[index_] = replaceIndexVar(element, indexVar, index)
+		}
+		return list
+
+	case ard.Map:
+		map_ := make(ard.Map)
+		for key, value := range data_ {
+			map_[key] = replaceIndexVar(value, indexVar, index)
+		}
+		return map_
+
+	case *parsing.FunctionCall:
+		arguments := make([]any, len(data_.Arguments))
+		for index_, argument := range data_.Arguments {
+			arguments[index_] = replaceIndexVar(argument, indexVar, index)
+		}
+		return parsing.NewFunctionCall(data_.Name, arguments, data_.URL, data_.Row, data_.Column, data_.Path)
+
+	default:
+		return data
+	}
+}
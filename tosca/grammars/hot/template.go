@@ -1,6 +1,7 @@
 package hot
 
 import (
+	contextpkg "context"
 	"time"
 
 	"github.com/tliron/go-ard"
@@ -24,6 +25,8 @@ type Template struct {
 	Resources            Resources            `read:"resources,Resource"`
 	Outputs              Outputs              `read:"outputs,Output"`
 	ConditionDefinitions ConditionDefinitions `read:"conditions,ConditionDefinition"`
+
+	Nested bool `json:"-" yaml:"-"`
 }
 
 func NewTemplate(context *parsing.Context) *Template {
@@ -47,7 +50,17 @@ func NewTemplate(context *parsing.Context) *Template {
 
 // ([parsing.Reader] signature)
 func ReadTemplate(context *parsing.Context) parsing.EntityPtr {
+	return readTemplate(context, false)
+}
+
+// ([parsing.Reader] signature)
+func ReadNestedTemplate(context *parsing.Context) parsing.EntityPtr {
+	return readTemplate(context, true)
+}
+
+func readTemplate(context *parsing.Context, nested bool) *Template {
 	self := NewTemplate(context)
+	self.Nested = nested
 	context.ScriptletNamespace.Merge(DefaultScriptletNamespace)
 
 	if heatTemplateVersionContext, ok := context.GetFieldChild("heat_template_version"); ok {
@@ -66,13 +79,40 @@ func ReadTemplate(context *parsing.Context) parsing.EntityPtr {
 	}
 
 	context.ValidateUnsupportedFields(append(context.ReadFields(self), "heat_template_version"))
+
+	// Members are expanded before imports so that their nested templates are imported, too
+	if context.HasQuirk(parsing.QuirkResourceGroupsExpand) {
+		for _, resource := range self.Resources {
+			if resource.IsResourceGroup() {
+				self.Resources = append(self.Resources, resource.ExpandResourceGroup(self)...)
+			}
+		}
+	}
+
 	return self
 }
 
+// ([parsing.Renderable] interface)
+func (self *Template) Render() {
+	self.renderOnce.Do(self.render)
+}
+
+func (self *Template) render() {
+	logRender.Debug("template")
+
+	// The parameters of nested templates are validated by the resources that use them
+	if !self.Nested {
+		for _, parameter := range self.Parameters {
+			if (parameter.Value == nil) && (parameter.Default == nil) {
+				parameter.Context.ReportValueRequired("parameter")
+			}
+		}
+	}
+}
+
 func (self *Template) NewPseudoParameter(name string, value string) {
 	context := self.Context.FieldChild("parameters", nil).MapChild(name, ard.Map{
 		"type":      "string",
-		"hidden":    true,
 		"immutable": true,
 	})
 	parameter := ReadParameter(context).(*Parameter)
@@ -81,8 +121,13 @@ func (self *Template) NewPseudoParameter(name string, value string) {
 }
 
 // ([parsing.Importer] interface)
-func (self *Template) GetImportSpecs() []*parsing.ImportSpec {
+func (self *Template) GetImportSpecs(context contextpkg.Context) []*parsing.ImportSpec {
 	var importSpecs []*parsing.ImportSpec
+	for _, resource := range self.Resources {
+		if importSpec, ok := resource.NewImportSpec(context); ok {
+			importSpecs = append(importSpecs, importSpec)
+		}
+	}
 	return importSpecs
 }
 
@@ -112,10 +157,9 @@ func (self *Template) NormalizeServiceTemplate() *normal.ServiceTemplate {
 	normalServiceTemplate.ScriptletNamespace = self.Context.ScriptletNamespace
 
 	self.Parameters.Normalize(normalServiceTemplate.Inputs, self.Context.FieldChild("parameters", nil))
+	self.ParameterGroups.Normalize(self, normalServiceTemplate)
 	self.Outputs.Normalize(normalServiceTemplate.Outputs, self.Context.FieldChild("outputs", nil))
 	self.Resources.Normalize(normalServiceTemplate)
 
-	// TODO: normalize ParameterGroups
-
 	return normalServiceTemplate
 }
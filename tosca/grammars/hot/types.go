@@ -1,6 +1,7 @@
 package hot
 
 import (
+	"path"
 	"strings"
 
 	"github.com/tliron/go-ard"
@@ -250,3 +251,52 @@ var ResourceTypes = map[string]string{
 	// Zun
 	"OS::Zun::Container": "openstack:zun.Container",
 }
+
+// Applies "resource_registry" type mappings. Mapping keys ending in "*" match by prefix, in which
+// case a mapped value ending in "*" receives the remainder of the type name.
+//
+// [https://docs.openstack.org/heat/wallaby/template_guide/environment.html#define-a-new-resource-type]
+func MapResourceType(type_ string, typeMappings map[string]string) string {
+	if mapped, ok := typeMappings[type_]; ok {
+		return mapped
+	}
+
+	var prefix string
+	var mapped string
+	var found bool
+	for from, to := range typeMappings {
+		if strings.HasSuffix(from, "*") {
+			from = from[:len(from)-1]
+			if strings.HasPrefix(type_, from) && (!found || (len(from) > len(prefix))) {
+				prefix = from
+				mapped = to
+				found = true
+			}
+		}
+	}
+
+	if found {
+		if strings.HasSuffix(mapped, "*") {
+			return mapped[:len(mapped)-1] + type_[len(prefix):]
+		}
+		return mapped
+	}
+
+	return type_
+}
+
+// Resource types that are paths or URLs of templates are nested stacks.
+//
+// [https://docs.openstack.org/heat/wallaby/template_guide/composition.html]
+func IsTemplateResourceType(type_ string) bool {
+	if strings.Contains(type_, "://") {
+		return true
+	}
+
+	switch path.Ext(type_) {
+	case ".yaml", ".yml", ".template":
+		return true
+	}
+
+	return false
+}
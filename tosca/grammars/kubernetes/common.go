@@ -0,0 +1,40 @@
+package kubernetes
+
+import (
+	"github.com/tliron/commonlog"
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+var log = commonlog.GetLogger("puccini.grammars.kubernetes")
+var logRender = commonlog.NewScopeLogger(log, "render")
+var logNormalize = commonlog.NewScopeLogger(log, "normalize")
+
+// Note: Kubernetes manifests have many "apiVersion" values, so this grammar is not detected by
+// version, but by [IsManifest]
+var Grammar = parsing.NewGrammar()
+
+func init() {
+	Grammar.RegisterDetector(IsManifest)
+	Grammar.RegisterMultipleDocuments()
+
+	Grammar.RegisterReader("$Root", ReadTemplate)
+
+	Grammar.RegisterReader("Resource", ReadResource)
+	Grammar.RegisterReader("Template", ReadTemplate)
+	Grammar.RegisterReader("Value", ReadValue)
+}
+
+// Kubernetes manifests have both "apiVersion" and "kind"
+//
+// ([parsing.GrammarDetector] signature)
+func IsManifest(context *parsing.Context) bool {
+	if map_, ok := context.Data.(ard.Map); ok {
+		if _, ok := map_["apiVersion"].(string); ok {
+			if _, ok := map_["kind"].(string); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package kubernetes
+
+import (
+	"reflect"
+
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+//
+// Reference
+//
+// A resource's reference to another resource by kind and name (e.g. a pod's volume referring to a
+// ConfigMap), or a resource selected by labels (e.g. a Service's selector)
+//
+
+type Reference struct {
+	*Entity `name:"reference"`
+
+	Requirement      string
+	RelationshipType string
+	Kind             string
+	TargetName       string
+	Optional         bool
+
+	Target *Resource `traverse:"ignore" json:"-" yaml:"-"`
+}
+
+func NewReference(context *parsing.Context, requirement string, relationshipType string, kind string, targetName string) *Reference {
+	return &Reference{
+		Entity:           NewEntity(context),
+		Requirement:      requirement,
+		RelationshipType: relationshipType,
+		Kind:             kind,
+		TargetName:       targetName,
+	}
+}
+
+// Referenced resources are often created separately (e.g. Secrets), so we only warn if they are
+// not found
+func (self *Reference) Resolve(namespace string) bool {
+	name := GetResourceName(namespace, self.Kind, self.TargetName)
+	if resource, ok := self.Context.Namespace.LookupForType(name, reflect.TypeOf((*Resource)(nil))); ok {
+		self.Target = resource.(*Resource)
+		return true
+	}
+
+	if !self.Optional {
+		log.Warningf("%s: %s not found: %s", self.Context.Path, self.Kind, name)
+	}
+
+	return false
+}
+
+func (self *Reference) Normalize(normalNodeTemplate *normal.NodeTemplate, normalServiceTemplate *normal.ServiceTemplate) {
+	if self.Target == nil {
+		return
+	}
+
+	normalRequirement := normalNodeTemplate.NewRequirement(self.Requirement, normal.NewLocationForContext(self.Context))
+	normalRequirement.NodeTemplate = normalServiceTemplate.NodeTemplates[self.Target.Name]
+	normalRequirement.CapabilityTypeName = &capabilityTypeName
+
+	normalRelationship := normalRequirement.NewRelationship()
+	normalRelationship.Types = normal.NewEntityTypes(self.RelationshipType)
+}
+
+//
+// References
+//
+
+type References []*Reference
@@ -0,0 +1,446 @@
+package kubernetes
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/yamlkeys"
+)
+
+// Path to the pod template in resources that have one (the Pod itself is a pod template)
+var PodTemplatePaths = map[string][]any{
+	"Pod":                   {},
+	"Deployment":            {"spec", "template"},
+	"StatefulSet":           {"spec", "template"},
+	"DaemonSet":             {"spec", "template"},
+	"ReplicaSet":            {"spec", "template"},
+	"ReplicationController": {"spec", "template"},
+	"Job":                   {"spec", "template"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template"},
+}
+
+// "[NAMESPACE/]kind/NAME"
+func GetResourceName(namespace string, kind string, name string) string {
+	name = strings.ToLower(kind) + "/" + name
+	if namespace != "" {
+		name = namespace + "/" + name
+	}
+	return name
+}
+
+//
+// Resource
+//
+// [https://kubernetes.io/docs/concepts/overview/working-with-objects/]
+//
+
+type Resource struct {
+	*Entity `name:"resource"`
+	Name    string `namespace:""`
+
+	APIVersion      string
+	Kind            string
+	ObjectName      string
+	ObjectNamespace string
+	Properties      Values
+
+	// Only for resources that have a pod template
+	PodLabels Labels `json:",omitempty" yaml:",omitempty"`
+
+	Selector          *Selector        `traverse:"ignore" json:"-" yaml:"-"`
+	SelectorContext   *parsing.Context `traverse:"ignore" json:"-" yaml:"-"`
+	SelectedResources []*Resource      `traverse:"ignore" json:"-" yaml:"-"`
+	References        References       `traverse:"ignore" json:"-" yaml:"-"`
+}
+
+func NewResource(context *parsing.Context) *Resource {
+	return &Resource{
+		Entity:     NewEntity(context),
+		Properties: make(Values),
+	}
+}
+
+// ([parsing.Reader] signature)
+func ReadResource(context *parsing.Context) parsing.EntityPtr {
+	self := NewResource(context)
+
+	if !context.ValidateType(ard.TypeMap) {
+		return self
+	}
+
+	if childContext, ok := context.GetRequiredFieldChild("apiVersion"); ok {
+		if apiVersion := childContext.ReadString(); apiVersion != nil {
+			self.APIVersion = *apiVersion
+		}
+	}
+
+	if childContext, ok := context.GetRequiredFieldChild("kind"); ok {
+		if kind := childContext.ReadString(); kind != nil {
+			self.Kind = *kind
+		}
+	}
+
+	if childContext, ok := context.GetRequiredFieldChild("metadata"); ok {
+		self.readMetadata(childContext)
+	}
+
+	for key, data := range context.Data.(ard.Map) {
+		name := yamlkeys.KeyString(key)
+		if (name != "apiVersion") && (name != "kind") {
+			self.Properties[name] = ReadValue(context.FieldChild(name, data)).(*Value)
+		}
+	}
+
+	self.Name = GetResourceName(self.ObjectNamespace, self.Kind, self.ObjectName)
+
+	if path, ok := PodTemplatePaths[self.Kind]; ok {
+		if podTemplateContext, ok := getChild(context, path...); ok {
+			self.readPodTemplate(podTemplateContext)
+		}
+	}
+
+	switch self.Kind {
+	case "Service":
+		if selectorContext, ok := getChild(context, "spec", "selector"); ok {
+			// Services without a selector have their endpoints managed separately
+			self.setSelector(selectorContext, ReadMapSelector(selectorContext))
+		}
+
+	case "NetworkPolicy":
+		if selectorContext, ok := getChild(context, "spec", "podSelector"); ok {
+			self.setSelector(selectorContext, ReadLabelSelector(selectorContext))
+		}
+
+	case "PodDisruptionBudget":
+		if selectorContext, ok := getChild(context, "spec", "selector"); ok {
+			self.setSelector(selectorContext, ReadLabelSelector(selectorContext))
+		}
+
+	case "Ingress":
+		self.readIngress(context)
+
+	case "HorizontalPodAutoscaler":
+		if scaleTargetContext, ok := getChild(context, "spec", "scaleTargetRef"); ok {
+			if kindContext, ok := scaleTargetContext.GetRequiredFieldChild("kind"); ok {
+				if kind := kindContext.ReadString(); kind != nil {
+					self.addReference(scaleTargetContext, "scale_target", "ScaleTarget", *kind, "name")
+				}
+			}
+		}
+	}
+
+	return self
+}
+
+// ([parsing.Mappable] interface)
+func (self *Resource) GetKey() string {
+	return self.Name
+}
+
+// ([parsing.Renderable] interface)
+func (self *Resource) Render() {
+	self.renderOnce.Do(self.render)
+}
+
+func (self *Resource) render() {
+	logRender.Debugf("resource: %s", self.Name)
+
+	for _, reference := range self.References {
+		reference.Resolve(self.ObjectNamespace)
+	}
+
+	if self.Selector != nil {
+		self.Context.Namespace.Range(func(entityPtr parsing.EntityPtr) bool {
+			if resource, ok := entityPtr.(*Resource); ok {
+				if (resource.ObjectNamespace == self.ObjectNamespace) && (resource.PodLabels != nil) && self.Selector.Matches(resource.PodLabels) {
+					self.SelectedResources = append(self.SelectedResources, resource)
+				}
+			}
+			return true
+		})
+
+		sort.Slice(self.SelectedResources, func(i int, j int) bool {
+			return self.SelectedResources[i].Name < self.SelectedResources[j].Name
+		})
+	}
+}
+
+func (self *Resource) readMetadata(context *parsing.Context) {
+	if !context.ValidateType(ard.TypeMap) {
+		return
+	}
+
+	if childContext, ok := context.GetFieldChild("name"); ok {
+		if name := childContext.ReadString(); name != nil {
+			self.ObjectName = *name
+		}
+	} else if childContext, ok := context.GetFieldChild("generateName"); ok {
+		// The actual name will be generated by Kubernetes
+		if name := childContext.ReadString(); name != nil {
+			self.ObjectName = *name
+		}
+	} else {
+		context.FieldChild("name", nil).ReportKeynameMissing()
+	}
+
+	if childContext, ok := context.GetFieldChild("namespace"); ok {
+		if namespace := childContext.ReadString(); namespace != nil {
+			self.ObjectNamespace = *namespace
+		}
+	}
+}
+
+// [https://kubernetes.io/docs/concepts/workloads/pods/#pod-templates]
+func (self *Resource) readPodTemplate(context *parsing.Context) {
+	if labelsContext, ok := getChild(context, "metadata", "labels"); ok {
+		self.PodLabels = ReadLabels(labelsContext)
+	} else {
+		self.PodLabels = make(Labels)
+	}
+
+	// The workload's selector must match its own pod template
+	if selectorContext, ok := getChild(self.Context, "spec", "selector"); ok && (self.Kind != "Pod") {
+		var selector *Selector
+		if self.Kind == "ReplicationController" {
+			selector = ReadMapSelector(selectorContext)
+		} else {
+			selector = ReadLabelSelector(selectorContext)
+		}
+
+		if !selector.IsEmpty() && !selector.Matches(self.PodLabels) {
+			selectorContext.ReportValueInvalid("selector", "does not match the pod template labels")
+		}
+	}
+
+	specContext, ok := getChild(context, "spec")
+	if !ok {
+		return
+	}
+
+	// [https://kubernetes.io/docs/concepts/storage/volumes/]
+	forEachChild(specContext, "volumes", func(volumeContext *parsing.Context) {
+		if childContext, ok := getChild(volumeContext, "configMap"); ok {
+			self.addReference(childContext, "volume", "Volume", "ConfigMap", "name")
+		}
+
+		if childContext, ok := getChild(volumeContext, "secret"); ok {
+			self.addReference(childContext, "volume", "Volume", "Secret", "secretName")
+		}
+
+		if childContext, ok := getChild(volumeContext, "persistentVolumeClaim"); ok {
+			self.addReference(childContext, "volume", "Volume", "PersistentVolumeClaim", "claimName")
+		}
+
+		if projectedContext, ok := getChild(volumeContext, "projected"); ok {
+			forEachChild(projectedContext, "sources", func(sourceContext *parsing.Context) {
+				if childContext, ok := getChild(sourceContext, "configMap"); ok {
+					self.addReference(childContext, "volume", "Volume", "ConfigMap", "name")
+				}
+
+				if childContext, ok := getChild(sourceContext, "secret"); ok {
+					self.addReference(childContext, "volume", "Volume", "Secret", "name")
+				}
+			})
+		}
+	})
+
+	// [https://kubernetes.io/docs/tasks/inject-data-application/distribute-credentials-secure/]
+	for _, containers := range []string{"initContainers", "containers"} {
+		forEachChild(specContext, containers, func(containerContext *parsing.Context) {
+			forEachChild(containerContext, "envFrom", func(envFromContext *parsing.Context) {
+				if childContext, ok := getChild(envFromContext, "configMapRef"); ok {
+					self.addReference(childContext, "environment", "Environment", "ConfigMap", "name")
+				}
+
+				if childContext, ok := getChild(envFromContext, "secretRef"); ok {
+					self.addReference(childContext, "environment", "Environment", "Secret", "name")
+				}
+			})
+
+			forEachChild(containerContext, "env", func(envContext *parsing.Context) {
+				if childContext, ok := getChild(envContext, "valueFrom", "configMapKeyRef"); ok {
+					self.addReference(childContext, "environment", "Environment", "ConfigMap", "name")
+				}
+
+				if childContext, ok := getChild(envContext, "valueFrom", "secretKeyRef"); ok {
+					self.addReference(childContext, "environment", "Environment", "Secret", "name")
+				}
+			})
+		})
+	}
+
+	forEachChild(specContext, "imagePullSecrets", func(secretContext *parsing.Context) {
+		self.addReference(secretContext, "image_pull_secret", "ImagePullSecret", "Secret", "name")
+	})
+
+	if _, ok := getChild(specContext, "serviceAccountName"); ok {
+		// The "default" service account is usually not in the manifests
+		self.addReference(specContext, "service_account", "ServiceAccount", "ServiceAccount", "serviceAccountName").Optional = true
+	}
+}
+
+// [https://kubernetes.io/docs/concepts/services-networking/ingress/]
+func (self *Resource) readIngress(context *parsing.Context) {
+	specContext, ok := getChild(context, "spec")
+	if !ok {
+		return
+	}
+
+	readBackend := func(backendContext *parsing.Context) {
+		if serviceContext, ok := getChild(backendContext, "service"); ok {
+			self.addReference(serviceContext, "backend", "Backend", "Service", "name")
+		} else if _, ok := getChild(backendContext, "serviceName"); ok {
+			// Before networking.k8s.io/v1
+			self.addReference(backendContext, "backend", "Backend", "Service", "serviceName")
+		}
+	}
+
+	for _, backend := range []string{"defaultBackend", "backend"} {
+		if backendContext, ok := getChild(specContext, backend); ok {
+			readBackend(backendContext)
+		}
+	}
+
+	forEachChild(specContext, "rules", func(ruleContext *parsing.Context) {
+		if httpContext, ok := getChild(ruleContext, "http"); ok {
+			forEachChild(httpContext, "paths", func(pathContext *parsing.Context) {
+				if backendContext, ok := getChild(pathContext, "backend"); ok {
+					readBackend(backendContext)
+				}
+			})
+		}
+	})
+
+	forEachChild(specContext, "tls", func(tlsContext *parsing.Context) {
+		if _, ok := getChild(tlsContext, "secretName"); ok {
+			self.addReference(tlsContext, "tls", "TLS", "Secret", "secretName")
+		}
+	})
+}
+
+func (self *Resource) setSelector(context *parsing.Context, selector *Selector) {
+	self.Selector = selector
+	self.SelectorContext = context
+}
+
+// The referred name is in the nameKey field of the context
+func (self *Resource) addReference(context *parsing.Context, requirement string, relationshipType string, kind string, nameKey string) *Reference {
+	var reference *Reference
+	if nameContext, ok := context.GetRequiredFieldChild(nameKey); ok {
+		if name := nameContext.ReadString(); name != nil {
+			reference = NewReference(nameContext, requirement, relationshipType, kind, *name)
+			if optional, ok := ard.With(context.Data).Get("optional").Boolean(); ok {
+				reference.Optional = optional
+			}
+			self.References = append(self.References, reference)
+			return reference
+		}
+	}
+
+	// Unused (already reported)
+	return NewReference(context, requirement, relationshipType, kind, "")
+}
+
+var capabilityTypeName = "Resource"
+var capabilityTypes = normal.NewEntityTypes(capabilityTypeName)
+
+func (self *Resource) Normalize(normalServiceTemplate *normal.ServiceTemplate) *normal.NodeTemplate {
+	logNormalize.Debugf("resource: %s", self.Name)
+
+	normalNodeTemplate := normalServiceTemplate.NewNodeTemplate(self.Name)
+	normalNodeTemplate.Types = normal.NewEntityTypes(self.Kind)
+
+	normalNodeTemplate.Metadata["kubernetes.api_version"] = self.APIVersion
+	normalNodeTemplate.Metadata["kubernetes.kind"] = self.Kind
+	normalNodeTemplate.Metadata["kubernetes.name"] = self.ObjectName
+	if self.ObjectNamespace != "" {
+		normalNodeTemplate.Metadata["kubernetes.namespace"] = self.ObjectNamespace
+	}
+
+	self.Properties.Normalize(normalNodeTemplate.Properties)
+
+	capabilityContext := self.Context.FieldChild("capabilities", nil).MapChild("resource", nil)
+	normalNodeTemplate.NewCapability("resource", normal.NewLocationForContext(capabilityContext)).Types = capabilityTypes
+
+	return normalNodeTemplate
+}
+
+var selectorRelationshipTypes = normal.NewEntityTypes("Selector")
+
+func (self *Resource) NormalizeDependencies(normalServiceTemplate *normal.ServiceTemplate) {
+	logNormalize.Debugf("resource dependencies: %s", self.Name)
+
+	normalNodeTemplate := normalServiceTemplate.NodeTemplates[self.Name]
+
+	for _, resource := range self.SelectedResources {
+		normalRequirement := normalNodeTemplate.NewRequirement("selector", normal.NewLocationForContext(self.SelectorContext))
+		normalRequirement.NodeTemplate = normalServiceTemplate.NodeTemplates[resource.Name]
+		normalRequirement.CapabilityTypeName = &capabilityTypeName
+
+		normalRelationship := normalRequirement.NewRelationship()
+		normalRelationship.Types = selectorRelationshipTypes
+	}
+
+	for _, reference := range self.References {
+		reference.Normalize(normalNodeTemplate, normalServiceTemplate)
+	}
+}
+
+//
+// Resources
+//
+
+type Resources []*Resource
+
+func (self Resources) Normalize(normalServiceTemplate *normal.ServiceTemplate) {
+	for _, resource := range self {
+		normalServiceTemplate.NodeTemplates[resource.Name] = resource.Normalize(normalServiceTemplate)
+	}
+
+	// Dependencies must be normalized after resources
+	// (because they may reference other resources)
+	for _, resource := range self {
+		resource.NormalizeDependencies(normalServiceTemplate)
+	}
+}
+
+// Utils
+
+// Path elements are field names (string) or list indexes (int)
+func getChild(context *parsing.Context, path ...any) (*parsing.Context, bool) {
+	for _, element := range path {
+		switch element_ := element.(type) {
+		case string:
+			map_, ok := context.Data.(ard.Map)
+			if !ok {
+				return nil, false
+			}
+			data, ok := map_[element_]
+			if !ok {
+				return nil, false
+			}
+			context = context.FieldChild(element_, data)
+
+		case int:
+			list, ok := context.Data.(ard.List)
+			if !ok || (element_ >= len(list)) {
+				return nil, false
+			}
+			context = context.ListChild(element_, list[element_])
+		}
+	}
+	return context, true
+}
+
+func forEachChild(context *parsing.Context, key string, f func(*parsing.Context)) {
+	if listContext, ok := getChild(context, key); ok {
+		if listContext.ValidateType(ard.TypeList) {
+			for index, data := range listContext.Data.(ard.List) {
+				f(listContext.ListChild(index, data))
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package kubernetes
+
+import (
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/yamlkeys"
+)
+
+type Labels map[string]string
+
+// Label keys and values should be strings, but we will accept any primitive
+func ReadLabels(context *parsing.Context) Labels {
+	labels := make(Labels)
+	if context.ValidateType(ard.TypeMap) {
+		for key, value := range context.Data.(ard.Map) {
+			labels[yamlkeys.KeyString(key)] = yamlkeys.KeyString(value)
+		}
+	}
+	return labels
+}
+
+//
+// Selector
+//
+// [https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#label-selectors]
+//
+
+type Selector struct {
+	MatchLabels      Labels
+	MatchExpressions []SelectorExpression
+}
+
+// Services use a plain map of labels
+func ReadMapSelector(context *parsing.Context) *Selector {
+	return &Selector{MatchLabels: ReadLabels(context)}
+}
+
+// Most other resources use a LabelSelector ("matchLabels" and "matchExpressions")
+func ReadLabelSelector(context *parsing.Context) *Selector {
+	self := Selector{MatchLabels: make(Labels)}
+
+	if !context.ValidateType(ard.TypeMap) {
+		return &self
+	}
+
+	context.ValidateUnsupportedFields([]string{"matchLabels", "matchExpressions"})
+
+	if childContext, ok := context.GetFieldChild("matchLabels"); ok {
+		self.MatchLabels = ReadLabels(childContext)
+	}
+
+	if childContext, ok := context.GetFieldChild("matchExpressions"); ok {
+		if childContext.ValidateType(ard.TypeList) {
+			for index, expression := range childContext.Data.(ard.List) {
+				if expression_, ok := ReadSelectorExpression(childContext.ListChild(index, expression)); ok {
+					self.MatchExpressions = append(self.MatchExpressions, expression_)
+				}
+			}
+		}
+	}
+
+	return &self
+}
+
+// An empty LabelSelector matches everything
+func (self *Selector) Matches(labels Labels) bool {
+	for key, value := range self.MatchLabels {
+		if value_, ok := labels[key]; !ok || (value_ != value) {
+			return false
+		}
+	}
+
+	for _, expression := range self.MatchExpressions {
+		if !expression.Matches(labels) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (self *Selector) IsEmpty() bool {
+	return (len(self.MatchLabels) == 0) && (len(self.MatchExpressions) == 0)
+}
+
+//
+// SelectorExpression
+//
+
+type SelectorExpression struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+func ReadSelectorExpression(context *parsing.Context) (SelectorExpression, bool) {
+	var self SelectorExpression
+
+	if !context.ValidateType(ard.TypeMap) {
+		return self, false
+	}
+
+	context.ValidateUnsupportedFields([]string{"key", "operator", "values"})
+
+	if childContext, ok := context.GetRequiredFieldChild("key"); ok {
+		if key := childContext.ReadString(); key != nil {
+			self.Key = *key
+		}
+	}
+
+	if childContext, ok := context.GetRequiredFieldChild("operator"); ok {
+		if operator := childContext.ReadString(); operator != nil {
+			switch *operator {
+			case "In", "NotIn", "Exists", "DoesNotExist":
+				self.Operator = *operator
+			default:
+				childContext.ReportKeynameUnsupportedValue()
+				return self, false
+			}
+		}
+	}
+
+	if childContext, ok := context.GetFieldChild("values"); ok {
+		if values := childContext.ReadStringList(); values != nil {
+			self.Values = *values
+		}
+	}
+
+	return self, (self.Key != "") && (self.Operator != "")
+}
+
+func (self SelectorExpression) Matches(labels Labels) bool {
+	value, ok := labels[self.Key]
+
+	switch self.Operator {
+	case "In":
+		return ok && self.hasValue(value)
+	case "NotIn":
+		return !ok || !self.hasValue(value)
+	case "Exists":
+		return ok
+	case "DoesNotExist":
+		return !ok
+	}
+
+	return false
+}
+
+func (self SelectorExpression) hasValue(value string) bool {
+	for _, value_ := range self.Values {
+		if value_ == value {
+			return true
+		}
+	}
+	return false
+}
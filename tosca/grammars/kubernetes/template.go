@@ -0,0 +1,98 @@
+package kubernetes
+
+import (
+	"strings"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+//
+// Template
+//
+// All the resources in a manifest file, which may have more than one YAML document
+//
+// [https://kubernetes.io/docs/concepts/overview/working-with-objects/object-management/]
+//
+
+type Template struct {
+	*Entity `name:"template"`
+
+	Resources Resources
+}
+
+func NewTemplate(context *parsing.Context) *Template {
+	self := &Template{
+		Entity: NewEntity(context),
+	}
+
+	self.Context.ImportScriptlet("tosca.lib.utils", "internal:/profiles/common/1.0/js/lib/utils.js")
+	self.Context.ImportScriptlet("tosca.lib.traversal", "internal:/profiles/common/1.0/js/lib/traversal.js")
+	self.Context.ImportScriptlet("tosca.resolve", "internal:/profiles/common/1.0/js/resolve.js")
+	self.Context.ImportScriptlet("tosca.coerce", "internal:/profiles/common/1.0/js/coerce.js")
+
+	return self
+}
+
+// ([parsing.Reader] signature)
+func ReadTemplate(context *parsing.Context) parsing.EntityPtr {
+	self := NewTemplate(context)
+
+	// Each document is a resource (or a list of resources)
+	documentsContext := context.Clone(context.Documents)
+	documentsContext.Locator = context.DocumentsLocator
+	if documentsContext.Data == nil {
+		// Not read from a file
+		documentsContext.Data = ard.List{context.Data}
+		documentsContext.Locator = nil
+	}
+
+	for index, document := range documentsContext.Data.(ard.List) {
+		if document == nil {
+			// Empty documents are allowed
+			continue
+		}
+
+		self.readResource(documentsContext.ListChild(index, document))
+	}
+
+	// Resource names must be unique
+	resources := make(map[string]*Resource)
+	for _, resource := range self.Resources {
+		if _, ok := resources[resource.Name]; ok {
+			resource.Context.ReportDuplicateMapKey(resource.Name)
+		} else {
+			resources[resource.Name] = resource
+		}
+	}
+
+	return self
+}
+
+func (self *Template) readResource(context *parsing.Context) {
+	// Lists ("kind: List" or "kind: ConfigMapList", etc.) contain resources in "items"
+	if kind, ok := ard.With(context.Data).Get("kind").String(); ok && (kind == "List" || strings.HasSuffix(kind, "List")) {
+		if itemsContext, ok := context.GetFieldChild("items"); ok && itemsContext.ValidateType(ard.TypeList) {
+			for index, item := range itemsContext.Data.(ard.List) {
+				self.readResource(itemsContext.ListChild(index, item))
+			}
+		}
+		return
+	}
+
+	self.Resources = append(self.Resources, ReadResource(context).(*Resource))
+}
+
+// normal.Normalizable interface
+func (self *Template) NormalizeServiceTemplate() *normal.ServiceTemplate {
+	logNormalize.Debug("template")
+
+	normalServiceTemplate := normal.NewServiceTemplate()
+
+	normalServiceTemplate.ScriptletNamespace = self.Context.ScriptletNamespace
+
+	self.Resources.Normalize(normalServiceTemplate)
+
+	return normalServiceTemplate
+}
@@ -28,6 +28,12 @@ func GetGrammar(context *parsing.Context) (*parsing.Grammar, *parsing.Context) {
 		} else {
 			return nil, versionContext
 		}
+	} else {
+		for _, grammar := range DetectableGrammars {
+			if grammar.Detector(context) {
+				return grammar, nil
+			}
+		}
 	}
 	return nil, nil
 }
@@ -44,8 +50,8 @@ func DetectGrammarVersion(context *parsing.Context) (*parsing.Context, *string)
 
 	for keyword := range Grammars {
 		if versionContext, ok = context.GetFieldChild(keyword); ok {
-			if keyword == "heat_template_version" {
-				// Hack to allow HOT to use YAML !!timestamp values
+			if (keyword == "heat_template_version") || (keyword == "AWSTemplateFormatVersion") {
+				// Hack to allow HOT and CloudFormation to use YAML !!timestamp values
 
 				if versionContext.Is(ard.TypeString) {
 					return versionContext, versionContext.ReadString()
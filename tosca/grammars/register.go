@@ -0,0 +1,139 @@
+package grammars
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/go-puccini/tosca/profiles"
+)
+
+var registered = make(map[*parsing.Grammar]struct{})
+var registerLock sync.Mutex
+
+// Registers a grammar, including out-of-tree grammars, so that the parser can detect it either by
+// its version keywords (see [parsing.Grammar.RegisterVersion]) or by its detector (see
+// [parsing.Grammar.RegisterDetector]). Its profiles (see [parsing.Grammar.RegisterProfiles]) are
+// made available as internal URLs and indexed by [profiles.DefaultRegistry].
+//
+// Grammars must be registered before parsing, usually in an init function.
+//
+// Returns an error if the grammar cannot be registered, e.g. if one of its versions or profile
+// files conflicts with an already registered grammar. Nothing is registered in that case, so a
+// corrected grammar can be registered later.
+func Register(grammar *parsing.Grammar) error {
+	registerLock.Lock()
+	defer registerLock.Unlock()
+
+	if _, ok := registered[grammar]; ok {
+		return errors.New("grammar already registered")
+	}
+
+	if _, ok := grammar.Readers["$Root"]; !ok {
+		return errors.New("grammar does not have a \"$Root\" reader")
+	}
+
+	if (len(grammar.Versions) == 0) && (grammar.Detector == nil) {
+		return errors.New("grammar has neither versions nor a detector")
+	}
+
+	for keyword, versions := range grammar.Versions {
+		for index, version := range versions {
+			if _, ok := Grammars[keyword][version.Version]; ok {
+				return fmt.Errorf("grammar version conflict: %s = %s", keyword, version.Version)
+			}
+
+			for _, version_ := range versions[:index] {
+				if version_.Version == version.Version {
+					return fmt.Errorf("grammar version conflict: %s = %s", keyword, version.Version)
+				}
+			}
+		}
+	}
+
+	if grammar.Profiles != nil {
+		if err := registerProfiles(grammar.Profiles); err != nil {
+			return err
+		}
+
+		profiles.DefaultRegistry.AddFS(grammar.Profiles)
+	}
+
+	for keyword, versions := range grammar.Versions {
+		grammars, ok := Grammars[keyword]
+		if !ok {
+			grammars = make(map[string]*parsing.Grammar)
+			Grammars[keyword] = grammars
+		}
+
+		for _, version := range versions {
+			grammars[version.Version] = grammar
+
+			if version.ImplicitProfilePath != "" {
+				paths, ok := ImplicitProfilePaths[keyword]
+				if !ok {
+					paths = make(map[string]string)
+					ImplicitProfilePaths[keyword] = paths
+				}
+
+				paths[version.Version] = version.ImplicitProfilePath
+			}
+		}
+	}
+
+	if grammar.Detector != nil {
+		DetectableGrammars = append(DetectableGrammars, grammar)
+	}
+
+	registered[grammar] = struct{}{}
+
+	return nil
+}
+
+// Like [Register] but panics on error.
+func MustRegister(grammar *parsing.Grammar) {
+	if err := Register(grammar); err != nil {
+		panic(err)
+	}
+}
+
+// Utils
+
+// Registers all profile files as internal URLs, or none of them
+func registerProfiles(profiles fs.FS) error {
+	var paths []string
+
+	err := fs.WalkDir(profiles, ".", func(path string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if dirEntry.IsDir() {
+			return nil
+		}
+
+		content, err := fs.ReadFile(profiles, path)
+		if err != nil {
+			return err
+		}
+
+		path = "/profiles/" + path
+		if err := exturl.RegisterInternalURL(path, content); err != nil {
+			return err
+		}
+
+		paths = append(paths, path)
+		return nil
+	})
+
+	if err != nil {
+		for _, path := range paths {
+			exturl.DeregisterInternalURL(path)
+		}
+	}
+
+	return err
+}
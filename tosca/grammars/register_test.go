@@ -0,0 +1,111 @@
+package grammars
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+func TestRegisterVersionConflict(t *testing.T) {
+	grammar := newTestGrammar("test_register_version")
+	grammar.RegisterVersion("tosca_definitions_version", "tosca_2_0", "")
+	grammar.RegisterProfiles(fstest.MapFS{
+		"test-register-version/profile.yaml": {Data: []byte("tosca_definitions_version: tosca_2_0\n")},
+	})
+
+	if err := Register(&grammar); err == nil {
+		t.Fatalf("version conflict not reported")
+	}
+
+	// Nothing was registered
+	if _, ok := Grammars["test_register_version"]; ok {
+		t.Errorf("version registered")
+	}
+	if Grammars["tosca_definitions_version"]["tosca_2_0"] == &grammar {
+		t.Errorf("conflicting version registered")
+	}
+	assertTestInternalURL(t, "/profiles/test-register-version/profile.yaml", false)
+}
+
+func TestRegisterProfileConflict(t *testing.T) {
+	grammar := newTestGrammar("test_register_profile_1")
+	grammar.RegisterProfiles(fstest.MapFS{
+		"test-register-profile/b.yaml": {Data: []byte("tosca_definitions_version: tosca_2_0\n")},
+	})
+	if err := Register(&grammar); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	// "a.yaml" is registered before the conflict with "b.yaml" is detected
+	grammar_ := newTestGrammar("test_register_profile_2")
+	grammar_.RegisterProfiles(fstest.MapFS{
+		"test-register-profile/a.yaml": {Data: []byte("tosca_definitions_version: tosca_2_0\n")},
+		"test-register-profile/b.yaml": {Data: []byte("tosca_definitions_version: tosca_2_0\n")},
+	})
+	if err := Register(&grammar_); err == nil {
+		t.Fatalf("profile path conflict not reported")
+	}
+
+	// Nothing was registered
+	if _, ok := Grammars["test_register_profile_2"]; ok {
+		t.Errorf("version registered")
+	}
+	assertTestInternalURL(t, "/profiles/test-register-profile/a.yaml", false)
+	assertTestInternalURL(t, "/profiles/test-register-profile/b.yaml", true)
+
+	// So a corrected grammar can be registered
+	delete(grammar_.Profiles.(fstest.MapFS), "test-register-profile/b.yaml")
+	if err := Register(&grammar_); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if _, ok := Grammars["test_register_profile_2"]; !ok {
+		t.Errorf("version not registered")
+	}
+	assertTestInternalURL(t, "/profiles/test-register-profile/a.yaml", true)
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	grammar := newTestGrammar("test_register_duplicate")
+	grammar.RegisterDetector(func(context *parsing.Context) bool {
+		return false
+	})
+	if err := Register(&grammar); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	detectable := len(DetectableGrammars)
+	if err := Register(&grammar); err == nil {
+		t.Errorf("duplicate registration not reported")
+	}
+	if len(DetectableGrammars) != detectable {
+		t.Errorf("detector registered twice")
+	}
+}
+
+// Utils
+
+func newTestGrammar(keyword string) parsing.Grammar {
+	grammar := parsing.NewGrammar()
+	grammar.RegisterVersion(keyword, "1.0", "")
+	grammar.RegisterReader("$Root", func(context *parsing.Context) parsing.EntityPtr {
+		return nil
+	})
+	return grammar
+}
+
+func assertTestInternalURL(t *testing.T, path string, registered bool) {
+	t.Helper()
+
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	if _, err := urlContext.NewValidInternalURL(path); (err == nil) != registered {
+		if registered {
+			t.Errorf("internal URL not registered: %s", path)
+		} else {
+			t.Errorf("internal URL registered: %s", path)
+		}
+	}
+}
@@ -19,13 +19,13 @@ func ReadRequirementAssignment(context *parsing.Context) parsing.EntityPtr {
 	context.SetReadTag("Count", "")
 	context.SetReadTag("Directives", "")
 	context.SetReadTag("Optional", "")
-	//context.SetReadTag("Allocation", "")
+	context.SetReadTag("Allocation", "")
 
 	self := tosca_v2_0.NewRequirementAssignment(context)
 
 	if context.Is(ard.TypeMap) {
 		// Long notation
-		context.ValidateUnsupportedFields(append(context.ReadFields(self)))
+		context.ValidateUnsupportedFields(context.ReadFields(self))
 	} else if context.ValidateType(ard.TypeMap, ard.TypeString) {
 		// Short notation
 		self.TargetNodeTemplateNameOrTypeName = context.FieldChild("node", context.Data).ReadString()
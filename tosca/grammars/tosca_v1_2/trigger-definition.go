@@ -88,7 +88,7 @@ func (self *TriggerDefinition) render() {
 }
 
 func (self *TriggerDefinition) Normalize(normalPolicy *normal.Policy) *normal.PolicyTrigger {
-	normalPolicyTrigger := normalPolicy.NewTrigger()
+	normalPolicyTrigger := normalPolicy.NewTrigger(self.Name)
 
 	if self.OperationAction != nil {
 		self.OperationAction.Normalize(normalPolicyTrigger.NewOperation())
@@ -20,7 +20,7 @@ func ReadRequirementAssignment(context *parsing.Context) parsing.EntityPtr {
 	context.SetReadTag("Count", "")
 	context.SetReadTag("Directives", "")
 	context.SetReadTag("Optional", "")
-	//context.SetReadTag("Allocation", "")
+	context.SetReadTag("Allocation", "")
 
 	self := tosca_v2_0.NewRequirementAssignment(context)
 
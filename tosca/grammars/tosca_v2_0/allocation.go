@@ -0,0 +1,79 @@
+package tosca_v2_0
+
+import (
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+//
+// Allocation
+//
+// [TOSCA-v2.0] @ 8.5
+//
+
+type Allocation struct {
+	*Entity `name:"allocation"`
+
+	Properties Values `read:"properties,Value"`
+}
+
+func NewAllocation(context *parsing.Context) *Allocation {
+	return &Allocation{
+		Entity:     NewEntity(context),
+		Properties: make(Values),
+	}
+}
+
+// ([parsing.Reader] signature)
+func ReadAllocation(context *parsing.Context) parsing.EntityPtr {
+	self := NewAllocation(context)
+	context.ValidateUnsupportedFields(context.ReadFields(self))
+	return self
+}
+
+// Allocated properties must be declared by the target capability and must be numeric (integers,
+// floats, or scalars), because their values are subtracted from the capability's capacity
+func (self *Allocation) Render(definitions PropertyDefinitions) {
+	logRender.Debug("allocation")
+
+	for key, value := range self.Properties {
+		if definition, ok := definitions[key]; ok {
+			definition.Render()
+			if definition.DataType != nil {
+				if isAllocatable(definition.DataType) {
+					value.RenderProperty(definition.DataType, definition)
+				} else {
+					value.Context.ReportValueInvalid("allocation", "capability property is not numeric")
+				}
+			}
+		} else {
+			value.Context.ReportUndeclared("capability property")
+			delete(self.Properties, key)
+		}
+	}
+}
+
+func (self *Allocation) Normalize(normalRequirement *normal.Requirement) {
+	logNormalize.Debug("allocation")
+
+	normalRequirement.Allocation = make(normal.Values)
+	self.Properties.Normalize(normalRequirement.Allocation)
+}
+
+// Utils
+
+func isAllocatable(dataType *DataType) bool {
+	if dataType.IsScalarType() {
+		return true
+	}
+
+	if internalTypeName, ok := dataType.GetInternalTypeName(); ok {
+		switch internalTypeName {
+		case ard.TypeInteger, ard.TypeFloat:
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,6 +2,7 @@ package tosca_v2_0
 
 import (
 	contextpkg "context"
+	"mime"
 	"path"
 	"strings"
 
@@ -65,7 +66,6 @@ func ReadArtifactDefinition(context *parsing.Context) parsing.EntityPtr {
 	} else if context.ValidateType(ard.TypeMap, ard.TypeString) {
 		// Short notation
 		self.File = context.FieldChild("file", context.Data).ReadString()
-		// TODO: infer ArtifactTypeName from content's URI
 	}
 
 	return self
@@ -82,7 +82,7 @@ func (self *ArtifactDefinition) GetURL(context contextpkg.Context) exturl.URL {
 			path := (*self.File)[1:]
 			self.url = self.Context.RepositoryURL.Relative(path)
 		} else if self.Repository != nil {
-			if url := self.Repository.GetURL(); url != nil {
+			if url := self.Repository.GetURL(context); url != nil {
 				self.url = url.Relative(*self.File)
 			}
 		} else {
@@ -103,7 +103,7 @@ func (self *ArtifactDefinition) GetExtension() string {
 	if self.File == nil {
 		return ""
 	}
-	file := *self.File
+	file := path.Base(*self.File)
 	if dot := strings.Index(file, "."); dot != -1 {
 		// Note: filepath.Ext will return the last extension only
 		return file[dot+1:]
@@ -112,6 +112,89 @@ func (self *ArtifactDefinition) GetExtension() string {
 	}
 }
 
+// When the type is not specified we infer it from the file extension, preferring artifact types
+// that declare a matching "file_ext", and falling back to those that declare a matching
+// "mime_type". The most specific type is selected, and if several unrelated types match the
+// inference is ambiguous.
+func (self *ArtifactDefinition) InferArtifactType() {
+	if self.ArtifactType != nil {
+		return
+	}
+
+	extension := self.GetExtension()
+	if extension == "" {
+		self.Context.FieldChild("type", nil).ReportKeynameMissing()
+		return
+	}
+
+	// Note: the namespace may have several names for the same type
+	artifactTypes := make(map[*ArtifactType]struct{})
+	self.Context.Namespace.Range(func(entityPtr parsing.EntityPtr) bool {
+		if artifactType, ok := entityPtr.(*ArtifactType); ok {
+			artifactTypes[artifactType] = struct{}{}
+		}
+		return true
+	})
+
+	var candidates []*ArtifactType
+	for artifactType := range artifactTypes {
+		if artifactType.FileExtension != nil {
+			for _, fileExtension := range *artifactType.FileExtension {
+				if matchesExtension(extension, fileExtension) {
+					candidates = append(candidates, artifactType)
+					break
+				}
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		if mimeType := getMIMEType(extension); mimeType != "" {
+			for artifactType := range artifactTypes {
+				if (artifactType.MIMEType != nil) && (*artifactType.MIMEType == mimeType) {
+					candidates = append(candidates, artifactType)
+				}
+			}
+		}
+	}
+
+	candidates = self.mostSpecificArtifactTypes(candidates)
+
+	switch len(candidates) {
+	case 0:
+		self.Context.FieldChild("type", nil).ReportKeynameMissing()
+
+	case 1:
+		self.ArtifactType = candidates[0]
+		logRender.Debugf("artifact: %s: inferred type %s", self.Name, parsing.GetCanonicalName(self.ArtifactType))
+
+	default:
+		entityPtrs := make([]parsing.EntityPtr, len(candidates))
+		for index, candidate := range candidates {
+			entityPtrs[index] = candidate
+		}
+		self.Context.FieldChild("type", nil).ReportTypeAmbiguous("artifact", entityPtrs...)
+	}
+}
+
+// Removes candidates that are ancestors of other candidates
+func (self *ArtifactDefinition) mostSpecificArtifactTypes(candidates []*ArtifactType) []*ArtifactType {
+	var mostSpecific []*ArtifactType
+	for _, candidate := range candidates {
+		isAncestor := false
+		for _, other := range candidates {
+			if (other != candidate) && self.Context.Hierarchy.IsCompatible(candidate, other) {
+				isAncestor = true
+				break
+			}
+		}
+		if !isAncestor {
+			mostSpecific = append(mostSpecific, candidate)
+		}
+	}
+	return mostSpecific
+}
+
 // ([parsing.Mappable] interface)
 func (self *ArtifactDefinition) GetKey() string {
 	return self.Name
@@ -188,3 +271,23 @@ func (self ArtifactDefinitions) Inherit(parentDefinitions ArtifactDefinitions) {
 		}
 	}
 }
+
+// Utils
+
+// The extension may be compound (e.g. "tar.gz"), in which case it also matches its suffixes
+// (e.g. "gz")
+func matchesExtension(extension string, fileExtension string) bool {
+	fileExtension = strings.ToLower(strings.TrimPrefix(fileExtension, "."))
+	extension = strings.ToLower(extension)
+	return (extension == fileExtension) || strings.HasSuffix(extension, "."+fileExtension)
+}
+
+func getMIMEType(extension string) string {
+	if dot := strings.LastIndex(extension, "."); dot != -1 {
+		extension = extension[dot+1:]
+	}
+	if mimeType, _, err := mime.ParseMediaType(mime.TypeByExtension("." + extension)); err == nil {
+		return mimeType
+	}
+	return ""
+}
@@ -85,16 +85,18 @@ func (self *Artifact) DoRender() {
 	}
 
 	if self.ArtifactType == nil {
-		self.Context.FieldChild("type", nil).ReportKeynameMissing()
-		return
+		self.InferArtifactType()
+		if self.ArtifactType == nil {
+			return
+		}
 	}
 
 	// Validate extension (if "file_ext" was not set in type, then anything goes)
 	if self.ArtifactType.FileExtension != nil {
 		extension := self.GetExtension()
 		found := false
-		for _, e := range *self.ArtifactType.FileExtension {
-			if e == extension {
+		for _, fileExtension := range *self.ArtifactType.FileExtension {
+			if matchesExtension(extension, fileExtension) {
 				found = true
 				break
 			}
@@ -110,7 +112,7 @@ func (self *Artifact) DoRender() {
 func (self *Artifact) Normalize(normalNodeTemplate *normal.NodeTemplate) *normal.Artifact {
 	logNormalize.Debugf("artifact: %s", self.Name)
 
-	normalArtifact := normalNodeTemplate.NewArtifact(self.Name)
+	normalArtifact := normalNodeTemplate.NewArtifact(self.Name, normal.NewLocationForContext(self.Context))
 
 	if self.Description != nil {
 		normalArtifact.Description = *self.Description
@@ -157,6 +159,9 @@ func (self Artifacts) Render(definitions ArtifactDefinitions, context *parsing.C
 	for key, definition := range definitions {
 		if artifact, ok := self[key]; ok {
 			artifact.Copy(definition)
+			if artifact.ArtifactType == nil {
+				artifact.InferArtifactType()
+			}
 		}
 	}
 
@@ -10,7 +10,7 @@ import (
 //
 // CapabilityAssignment
 //
-// [TOSCA-v2.0] @ ?
+// [TOSCA-v2.0] @ 8.3
 // [TOSCA-Simple-Profile-YAML-v1.3] @ 3.8.1
 // [TOSCA-Simple-Profile-YAML-v1.2] @ 3.8.1
 // [TOSCA-Simple-Profile-YAML-v1.1] @ 3.7.1
@@ -19,7 +19,10 @@ var Grammar = parsing.NewGrammar()
 var DefaultScriptletNamespace = parsing.NewScriptletNamespace()
 
 var nodeTemplatePtrType = reflect.TypeFor[*NodeTemplate]()
+var nodeTypePtrType = reflect.TypeFor[*NodeType]()
+var capabilityTypePtrType = reflect.TypeFor[*CapabilityType]()
 var dataTypePtrType = reflect.TypeFor[*DataType]()
+var repositoryPtrType = reflect.TypeFor[*Repository]()
 
 func init() {
 	Grammar.RegisterVersion("tosca_definitions_version", "tosca_2_0", "/profiles/implicit/2.0/profile.yaml")
@@ -27,6 +30,7 @@ func init() {
 	Grammar.RegisterReader("$Root", ReadServiceFile)
 	Grammar.RegisterReader("$File", ReadFile)
 
+	Grammar.RegisterReader("Allocation", ReadAllocation) // introduced in TOSCA 2.0
 	Grammar.RegisterReader("Artifact", ReadArtifact)
 	Grammar.RegisterReader("ArtifactDefinition", ReadArtifactDefinition)
 	Grammar.RegisterReader("ArtifactType", ReadArtifactType)
@@ -1,7 +1,10 @@
 package tosca_v2_0
 
 import (
+	"strings"
+
 	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 	"github.com/tliron/yamlkeys"
 )
@@ -25,6 +28,9 @@ type ConditionClause struct {
 	// Or one or more child condition clauses
 	Operator         *string
 	ConditionClauses []*ConditionClause
+
+	// Or a boolean expression (TOSCA 2.0)
+	Expression *ValidationClause
 }
 
 func NewConditionClause(context *parsing.Context) *ConditionClause {
@@ -50,6 +56,18 @@ func ReadConditionClause(context *parsing.Context) parsing.EntityPtr {
 				name = "and"
 			}
 
+			if strings.HasPrefix(name, "$") {
+				// TOSCA 2.0
+				switch name {
+				case "$and", "$or", "$not":
+					name = name[1:]
+				default:
+					// Boolean expression
+					self.Expression = ReadValidationClause(context).(*ValidationClause)
+					return self
+				}
+			}
+
 			switch name {
 			case "and":
 				self.Operator = &name
@@ -102,3 +120,31 @@ func ReadConditionClauseAnd(context *parsing.Context) parsing.EntityPtr {
 
 	return self
 }
+
+func (self *ConditionClause) Normalize() *normal.Condition {
+	normalCondition := normal.NewCondition()
+
+	if self.Operator != nil {
+		normalCondition.Operator = *self.Operator
+		for _, conditionClause := range self.ConditionClauses {
+			normalCondition.Conditions = append(normalCondition.Conditions, conditionClause.Normalize())
+		}
+	} else if self.AttributeName != nil {
+		normalCondition.Attribute = *self.AttributeName
+		normalCondition.Validators = self.ValidationClauses.Normalize(self.Context)
+	} else if (self.Expression != nil) && (self.Expression.ScriptletName != "") {
+		// Note: we are not using ValidationClause.ToFunctionCall because "$value" must
+		// remain as is, to be evaluated against the target's attributes
+		arguments := make(ard.List, len(self.Expression.Arguments))
+		for index, argument := range self.Expression.Arguments {
+			argumentContext := self.Context.Clone(argument)
+			ParseFunctionCalls(argumentContext)
+			arguments[index] = argumentContext.Data
+		}
+		functionCall := self.Context.NewFunctionCall(self.Expression.ScriptletName, arguments)
+		NormalizeFunctionCallArguments(functionCall, self.Context)
+		normalCondition.Validators = normal.FunctionCalls{normal.NewFunctionCall(functionCall)}
+	}
+
+	return normalCondition
+}
@@ -0,0 +1,250 @@
+package tosca_v2_0
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tliron/go-puccini/tosca/parsing"
+)
+
+//
+// ETSI NFV SOL001
+//
+// [ETSI-GS-NFV-SOL-001-v4.4.1]
+//
+// Rules that the ETSI NFV descriptors must follow but that cannot be expressed in TOSCA. They are
+// validated only with the "validation.etsinfv.sol001" quirk.
+//
+
+// Embedded copies, one directory per version
+const etsiNFVSOL001Path = "/profiles/etsi-nfv-sol001/"
+
+// Submatches: version, file name
+// (e.g. "https://forge.etsi.org/rep/nfv/SOL001/raw/v4.4.1/etsi_nfv_sol001_vnfd_types.yaml")
+var etsiNFVSOL001URLRegexp = regexp.MustCompile(`(?:^|/)v(\d+\.\d+\.\d+)/(etsi_nfv_sol001_[a-z]+_types\.yaml)$`)
+
+// The node types that a requirement of a node type must target
+type etsiNFVRequirementRule struct {
+	NodeTypeName        string
+	RequirementName     string
+	TargetNodeTypeNames []string
+}
+
+var etsiNFVRequirementRules = []etsiNFVRequirementRule{
+	{"tosca.nodes.nfv.VduCp", "virtual_binding", []string{"tosca.nodes.nfv.Vdu.Compute"}},
+	{"tosca.nodes.nfv.VduCp", "virtual_link", []string{"tosca.nodes.nfv.VnfVirtualLink"}},
+	{"tosca.nodes.nfv.VnfExtCp", "internal_virtual_link", []string{"tosca.nodes.nfv.VnfVirtualLink"}},
+	{"tosca.nodes.nfv.Vdu.Compute", "virtual_storage", []string{"tosca.nodes.nfv.Vdu.VirtualBlockStorage", "tosca.nodes.nfv.Vdu.VirtualObjectStorage", "tosca.nodes.nfv.Vdu.VirtualFileStorage"}},
+	{"tosca.nodes.nfv.Vdu.VirtualFileStorage", "virtual_link", []string{"tosca.nodes.nfv.VnfVirtualLink"}},
+	{"tosca.nodes.nfv.VNF", "virtual_link", []string{"tosca.nodes.nfv.NsVirtualLink"}},
+	{"tosca.nodes.nfv.PNF", "virtual_link", []string{"tosca.nodes.nfv.NsVirtualLink"}},
+	{"tosca.nodes.nfv.NS", "virtual_link", []string{"tosca.nodes.nfv.NsVirtualLink"}},
+}
+
+// The external connection points to which the virtual link requirements of a substituted node
+// type must be mapped, and whether the substitution represents a deployment flavour
+type etsiNFVSubstitutionRule struct {
+	NodeTypeName         string
+	ExtCpNodeTypeName    string
+	ExtCpRequirementName string
+	Flavour              bool
+}
+
+var etsiNFVSubstitutionRules = []etsiNFVSubstitutionRule{
+	{"tosca.nodes.nfv.VNF", "tosca.nodes.nfv.VnfExtCp", "external_virtual_link", true},
+	{"tosca.nodes.nfv.NS", "tosca.nodes.nfv.Sap", "external_virtual_link", true},
+	{"tosca.nodes.nfv.PNF", "tosca.nodes.nfv.PnfExtCp", "external_virtual_link", false},
+}
+
+const (
+	etsiNFVVirtualLinkableCapabilityTypeName = "tosca.capabilities.nfv.VirtualLinkable"
+	etsiNFVFlavourPropertyName               = "flavour_id"
+)
+
+// Imports of the ETSI type definitions from the ETSI forge are redirected to our embedded copies
+// by version and file name. Only exact version matches are redirected.
+func (self *Import) newETSINFVSOL001ImportSpec() (*parsing.ImportSpec, bool) {
+	match := etsiNFVSOL001URLRegexp.FindStringSubmatch(*self.URL)
+	if match == nil {
+		return nil, false
+	}
+
+	url, err := self.Context.URL.Context().NewValidInternalURL(etsiNFVSOL001Path + match[1] + "/" + match[2])
+	if err != nil {
+		// We do not have an embedded copy of this version, so it will be imported as usual
+		return nil, false
+	}
+
+	log.Infof("importing embedded %s instead of %s", url.String(), *self.URL)
+
+	appendShortcutNames := !self.Context.HasQuirk(parsing.QuirkNamespaceNormativeShortcutsDisable)
+
+	importSpec := &parsing.ImportSpec{
+		URL:             url,
+		NameTransformer: newImportNameTransformer(self.Namespace, appendShortcutNames),
+		Implicit:        false,
+	}
+	return importSpec, true
+}
+
+func (self *ServiceTemplate) validateETSINFVSOL001() {
+	self.validateETSINFVRequirements()
+	if self.SubstitutionMappings != nil {
+		self.validateETSINFVSubstitution()
+	}
+}
+
+func (self *ServiceTemplate) validateETSINFVRequirements() {
+	for _, rule := range etsiNFVRequirementRules {
+		nodeType, ok := self.lookupNodeType(rule.NodeTypeName)
+		if !ok {
+			continue
+		}
+
+		targetNodeTypes := self.lookupNodeTypes(rule.TargetNodeTypeNames)
+		if len(targetNodeTypes) == 0 {
+			continue
+		}
+
+		for _, nodeTemplate := range self.GetNodeTemplatesOfType(nodeType) {
+			for _, requirement := range nodeTemplate.Requirements {
+				if (requirement.Name != rule.RequirementName) || (requirement.TargetNodeTemplate == nil) || (requirement.TargetNodeTemplate.NodeType == nil) {
+					continue
+				}
+
+				if !self.isCompatibleWithAny(targetNodeTypes, requirement.TargetNodeTemplate.NodeType) {
+					requirement.Context.ReportPathf(0, "requirement %q of %q must target a node template of type %s, but %q is of type %q",
+						rule.RequirementName, rule.NodeTypeName, strings.Join(quoteAll(rule.TargetNodeTypeNames), " or "),
+						requirement.TargetNodeTemplate.Name, parsing.GetCanonicalName(requirement.TargetNodeTemplate.NodeType))
+				}
+			}
+		}
+	}
+}
+
+func (self *ServiceTemplate) validateETSINFVSubstitution() {
+	substitutionMappings := self.SubstitutionMappings
+	if substitutionMappings.NodeType == nil {
+		return
+	}
+
+	for _, rule := range etsiNFVSubstitutionRules {
+		nodeType, ok := self.lookupNodeType(rule.NodeTypeName)
+		if !ok || !self.Context.Hierarchy.IsCompatible(nodeType, substitutionMappings.NodeType) {
+			continue
+		}
+
+		if extCpNodeType, ok := self.lookupNodeType(rule.ExtCpNodeTypeName); ok {
+			self.validateETSINFVRequirementMappings(rule, extCpNodeType)
+
+			// External connection points that are neither mapped nor connected are not reachable
+			for _, nodeTemplate := range self.GetNodeTemplatesOfType(extCpNodeType) {
+				if !substitutionMappings.IsRequirementMapped(nodeTemplate, rule.ExtCpRequirementName) && !hasAssignedRequirement(nodeTemplate, rule.ExtCpRequirementName) {
+					log.Warningf("%s: %q is not mapped to a requirement of %q", nodeTemplate.Context.Path, nodeTemplate.Name, rule.NodeTypeName)
+				}
+			}
+		}
+
+		if rule.Flavour && !self.hasETSINFVFlavour() {
+			substitutionMappings.Context.FieldChild("properties", nil).MapChild(etsiNFVFlavourPropertyName, nil).ReportValueRequired("deployment flavour")
+		}
+
+		// The rules are mutually exclusive
+		return
+	}
+}
+
+// Virtual link requirements of the substituted node type must be mapped to the external
+// connection points
+func (self *ServiceTemplate) validateETSINFVRequirementMappings(rule etsiNFVSubstitutionRule, extCpNodeType *NodeType) {
+	virtualLinkableCapabilityType, ok := self.Context.Namespace.LookupForType(etsiNFVVirtualLinkableCapabilityTypeName, capabilityTypePtrType)
+	if !ok {
+		return
+	}
+
+	substitutionMappings := self.SubstitutionMappings
+	for _, mapping := range substitutionMappings.RequirementMappings {
+		if mapping.NodeTemplate == nil {
+			// Already reported
+			continue
+		}
+
+		definition, ok := substitutionMappings.NodeType.RequirementDefinitions[mapping.Name]
+		if !ok || (definition.TargetCapabilityType == nil) || !self.Context.Hierarchy.IsCompatible(virtualLinkableCapabilityType, definition.TargetCapabilityType) {
+			continue
+		}
+
+		if (mapping.NodeTemplate.NodeType == nil) || !self.Context.Hierarchy.IsCompatible(extCpNodeType, mapping.NodeTemplate.NodeType) ||
+			(mapping.RequirementName == nil) || (*mapping.RequirementName != rule.ExtCpRequirementName) {
+			mapping.Context.ReportPathf(0, "virtual link requirement %q of %q must be mapped to the %q requirement of a %q node template",
+				mapping.Name, rule.NodeTypeName, rule.ExtCpRequirementName, rule.ExtCpNodeTypeName)
+		}
+	}
+}
+
+// The deployment flavour is either mapped to the "flavour_id" property (usually as a constant
+// value) or selected by the substitution filter
+func (self *ServiceTemplate) hasETSINFVFlavour() bool {
+	substitutionMappings := self.SubstitutionMappings
+
+	if _, ok := substitutionMappings.PropertyMappings[etsiNFVFlavourPropertyName]; ok {
+		return true
+	}
+
+	if substitutionMappings.SubstitutionFilter != nil {
+		for _, propertyFilter := range substitutionMappings.SubstitutionFilter.PropertyFilters {
+			if propertyFilter.Name == etsiNFVFlavourPropertyName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (self *ServiceTemplate) lookupNodeType(name string) (*NodeType, bool) {
+	if nodeType, ok := self.Context.Namespace.LookupForType(name, nodeTypePtrType); ok {
+		return nodeType.(*NodeType), true
+	} else {
+		return nil, false
+	}
+}
+
+func (self *ServiceTemplate) lookupNodeTypes(names []string) []*NodeType {
+	var nodeTypes []*NodeType
+	for _, name := range names {
+		if nodeType, ok := self.lookupNodeType(name); ok {
+			nodeTypes = append(nodeTypes, nodeType)
+		}
+	}
+	return nodeTypes
+}
+
+func (self *ServiceTemplate) isCompatibleWithAny(baseNodeTypes []*NodeType, nodeType *NodeType) bool {
+	for _, baseNodeType := range baseNodeTypes {
+		if self.Context.Hierarchy.IsCompatible(baseNodeType, nodeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Utils
+
+func hasAssignedRequirement(nodeTemplate *NodeTemplate, name string) bool {
+	for _, requirement := range nodeTemplate.Requirements {
+		if (requirement.Name == name) && ((requirement.TargetNodeTemplate != nil) || (requirement.TargetNodeTemplateNameOrTypeName != nil)) {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteAll(names []string) []string {
+	quoted := make([]string, len(names))
+	for index, name := range names {
+		quoted[index] = strconv.Quote(name)
+	}
+	return quoted
+}
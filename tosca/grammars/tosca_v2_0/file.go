@@ -1,6 +1,8 @@
 package tosca_v2_0
 
 import (
+	contextpkg "context"
+
 	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
@@ -60,18 +62,46 @@ func ReadFile(context *parsing.Context) parsing.EntityPtr {
 }
 
 // ([parsing.Importer] interface)
-func (self *File) GetImportSpecs() []*parsing.ImportSpec {
-	// TODO: importing should also import repositories
-
+func (self *File) GetImportSpecs(context contextpkg.Context) []*parsing.ImportSpec {
 	var importSpecs = make([]*parsing.ImportSpec, 0, len(self.Imports))
 	for _, import_ := range self.Imports {
-		if importSpec, ok := import_.NewImportSpec(self); ok {
+		if importSpec, ok := import_.NewImportSpec(context, self); ok {
 			importSpecs = append(importSpecs, importSpec)
 		}
 	}
 	return importSpecs
 }
 
+// ([parsing.DeferredImporter] interface)
+func (self *File) HasDeferredImports() bool {
+	for _, import_ := range self.Imports {
+		if _, deferred := import_.getRepository(self); deferred {
+			return true
+		}
+	}
+	return false
+}
+
+// Imports from repositories declared in imported files. Imports are read concurrently, so these
+// repositories can only be found after the other imports have been read.
+//
+// ([parsing.DeferredImporter] interface)
+func (self *File) GetDeferredImportSpecs(context contextpkg.Context, namespace *parsing.Namespace, final bool) map[string]*parsing.ImportSpec {
+	importSpecs := make(map[string]*parsing.ImportSpec)
+	for _, import_ := range self.Imports {
+		if _, deferred := import_.getRepository(self); deferred {
+			if repository, ok := namespace.LookupForType(*import_.RepositoryName, repositoryPtrType); ok {
+				if importSpec, ok := import_.newImportSpec(context, repository.(*Repository)); ok {
+					importSpecs[import_.Context.Path.String()] = importSpec
+				}
+			} else if final {
+				import_.Context.ReportRepositoryInaccessible(*import_.RepositoryName)
+			}
+		}
+	}
+	return importSpecs
+}
+
 func (self *File) Normalize(normalServiceTemplate *normal.ServiceTemplate) {
 	logNormalize.Debug("file")
 
@@ -1,8 +1,9 @@
 package tosca_v2_0
 
 import (
-	"context"
+	contextpkg "context"
 	"fmt"
+	"maps"
 	"strings"
 	"sync"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/tliron/exturl"
 	"github.com/tliron/go-ard"
 	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/go-puccini/tosca/profiles"
 )
 
 //
@@ -28,6 +30,7 @@ type Import struct {
 
 	URL            *string           `read:"url"`     // Conditional mandatory
 	Profile        *string           `read:"profile"` // Conditional mandatory
+	Version        *string           `read:"version"` // only with "profile"
 	RepositoryName *string           `read:"repository"`
 	Namespace      *string           `read:"namespace"`
 	Description    *string           `read:"description"`
@@ -59,6 +62,18 @@ func ReadImport(context *parsing.Context) parsing.EntityPtr {
 			}
 		}
 
+		// Allow unquoted profile versions (e.g. 2.1)
+		// (We copy the map in order to not modify the original data)
+		if map_, ok := context.Data.(ard.Map); ok {
+			if version, ok := map_["version"]; ok {
+				if _, ok := version.(string); !ok {
+					map_ = maps.Clone(map_)
+					map_["version"] = ard.ValueToString(version)
+					context.Data = map_
+				}
+			}
+		}
+
 		// Long notation
 		context.ValidateUnsupportedFields(context.ReadFields(self))
 
@@ -74,6 +89,11 @@ func ReadImport(context *parsing.Context) parsing.EntityPtr {
 			context.ReportError(fmt.Errorf("'repository' cannot be used with 'profile'"))
 		}
 
+		// Version can only be used with profile
+		if (self.Version != nil) && (self.Profile == nil) {
+			context.ReportError(fmt.Errorf("'version' can only be used with 'profile'"))
+		}
+
 	} else if context.ValidateType(ard.TypeMap, ard.TypeString) {
 		// Short notation (URL only)
 		self.URL = context.FieldChild("url", context.Data).ReadString()
@@ -82,10 +102,36 @@ func ReadImport(context *parsing.Context) parsing.EntityPtr {
 	return self
 }
 
-func (self *Import) NewImportSpec(unit *File) (*parsing.ImportSpec, bool) {
+func (self *Import) NewImportSpec(context contextpkg.Context, unit *File) (*parsing.ImportSpec, bool) {
+	repository, deferred := self.getRepository(unit)
+	if deferred {
+		// See File.GetDeferredImportSpecs
+		return nil, false
+	}
+	return self.newImportSpec(context, repository)
+}
+
+// If the repository is not declared in the unit then it might be declared in an imported file, in
+// which case the import is deferred
+func (self *Import) getRepository(unit *File) (*Repository, bool) {
+	if (self.Repository != nil) || (self.RepositoryName == nil) || (self.URL == nil) || (self.Profile != nil) {
+		return self.Repository, false
+	}
+
+	// Namespace lookup phase may not have run yet, so we will retrieve the repository on our own
+	for _, repository := range unit.Repositories {
+		if repository.Name == *self.RepositoryName {
+			return repository, false
+		}
+	}
+
+	return nil, true
+}
+
+func (self *Import) newImportSpec(context contextpkg.Context, repository *Repository) (*parsing.ImportSpec, bool) {
 	// Handle profile
 	if self.Profile != nil {
-		return self.newProfileImportSpec(*self.Profile)
+		return self.newProfileImportSpec(context, *self.Profile)
 	}
 
 	// Handle URL
@@ -93,29 +139,28 @@ func (self *Import) NewImportSpec(unit *File) (*parsing.ImportSpec, bool) {
 		return nil, false
 	}
 
-	repository := self.Repository
-	if (repository == nil) && (self.RepositoryName != nil) {
-		// Namespace lookup phase may not have run yet, so we will retrieve the repository on our own
-		for _, r := range unit.Repositories {
-			if r.Name == *self.RepositoryName {
-				repository = r
-				break
-			}
+	// Handle embedded ETSI NFV type definitions
+	if self.Context.HasQuirk(parsing.QuirkImportsETSINFVSOL001) {
+		if importSpec, ok := self.newETSINFVSOL001ImportSpec(); ok {
+			return importSpec, true
 		}
 	}
 
 	var bases []exturl.URL
 	var urlContext *exturl.Context
 
-	// Protect URL context creation with mutex to avoid race conditions
-	urlCreationMutex.Lock()
+	var repositoryUrl exturl.URL
 	if repository != nil {
-		repositoryUrl := repository.GetURL()
-		if repositoryUrl == nil {
-			urlCreationMutex.Unlock()
+		// Fetching can be slow, so we do it outside the mutex
+		if repositoryUrl = repository.GetURL(context); repositoryUrl == nil {
 			self.Context.ReportRepositoryInaccessible(repository.Name)
 			return nil, false
 		}
+	}
+
+	// Protect URL context creation with mutex to avoid race conditions
+	urlCreationMutex.Lock()
+	if repositoryUrl != nil {
 
 		bases = []exturl.URL{repositoryUrl}
 		urlContext = repositoryUrl.Context()
@@ -129,7 +174,7 @@ func (self *Import) NewImportSpec(unit *File) (*parsing.ImportSpec, bool) {
 	urlCreationMutex.Unlock()
 
 	// Use cached URL creation with request deduplication to handle network issues
-	url, err := cachedURLCreation(urlContext, *self.URL, bases)
+	url, err := cachedURLCreation(context, urlContext, *self.URL, bases)
 	if err != nil {
 		self.Context.ReportError(err)
 		return nil, false
@@ -161,6 +206,13 @@ var (
 	urlCacheMutex sync.Mutex
 )
 
+// Upper bounds for URL creation and for waiting on another request for the
+// same URL (the caller's context can only shorten them)
+var (
+	urlCreationTimeout = 45 * time.Second
+	urlWaitTimeout     = 30 * time.Second
+)
+
 // ClearURLCache clears the URL cache - useful for tests
 func ClearURLCache() {
 	urlCacheMutex.Lock()
@@ -169,7 +221,7 @@ func ClearURLCache() {
 }
 
 // cachedURLCreation handles URL creation with caching and request deduplication
-func cachedURLCreation(urlContext *exturl.Context, urlString string, bases []exturl.URL) (exturl.URL, error) {
+func cachedURLCreation(context contextpkg.Context, urlContext *exturl.Context, urlString string, bases []exturl.URL) (exturl.URL, error) {
 	// Create a cache key from the URL string and bases
 	cacheKey := urlString
 	for _, base := range bases {
@@ -183,13 +235,16 @@ func cachedURLCreation(urlContext *exturl.Context, urlString string, bases []ext
 		urlCacheMutex.Unlock()
 
 		// Wait for the existing request to complete
+		waitContext, cancel := contextpkg.WithTimeout(context, urlWaitTimeout)
+		defer cancel()
+
 		select {
 		case <-req.done:
 			if req.err != nil {
 				return nil, req.err
 			}
 			return *req.result, nil
-		case <-time.After(30 * time.Second):
+		case <-waitContext.Done():
 			return nil, fmt.Errorf("timeout waiting for URL resolution: %s", urlString)
 		}
 	}
@@ -201,11 +256,29 @@ func cachedURLCreation(urlContext *exturl.Context, urlString string, bases []ext
 	urlCache[cacheKey] = req
 	urlCacheMutex.Unlock()
 
-	// Perform the actual URL creation with extended timeout for external requests
-	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+	fetchContext, cancel := contextpkg.WithTimeout(context, urlCreationTimeout)
 	defer cancel()
 
-	url, err := urlContext.NewValidAnyOrFileURL(ctx, urlString, bases)
+	// Not all URL types honor the context (exturl validates network URLs
+	// without it), so we also stop waiting on our own
+	type result struct {
+		url exturl.URL
+		err error
+	}
+	results := make(chan result, 1)
+	go func() {
+		url, err := urlContext.NewValidAnyOrFileURL(fetchContext, urlString, bases)
+		results <- result{url, err}
+	}()
+
+	var url exturl.URL
+	var err error
+	select {
+	case result := <-results:
+		url, err = result.url, result.err
+	case <-fetchContext.Done():
+		err = fmt.Errorf("timeout creating URL: %s", urlString)
+	}
 
 	// Store the result and notify waiters
 	req.err = err
@@ -217,26 +290,18 @@ func cachedURLCreation(urlContext *exturl.Context, urlString string, bases []ext
 	return url, err
 }
 
-// Known profiles registry
-var KnownProfiles = map[string]string{
-	"org.oasis-open.simple:2.0": "simple/2.0/profile.yaml",
-}
+func (self *Import) newProfileImportSpec(context contextpkg.Context, profileName string) (*parsing.ImportSpec, bool) {
+	var version string
+	if self.Version != nil {
+		version = *self.Version
+	}
 
-func (self *Import) newProfileImportSpec(profileName string) (*parsing.ImportSpec, bool) {
-	// Look up profile in registry
-	profileRelativePath, exists := KnownProfiles[profileName]
-	if !exists {
-		available := make([]string, 0, len(KnownProfiles))
-		for name := range KnownProfiles {
-			available = append(available, name)
-		}
-		self.Context.ReportError(fmt.Errorf("unknown profile: %q. Available profiles: %v", profileName, available))
+	profile, err := profiles.DefaultRegistry.Get(context, profileName, version)
+	if err != nil {
+		self.Context.ReportError(err)
 		return nil, false
 	}
 
-	// Create internal URL for profile (matches the pattern used in profiles.go)
-	profileInternalURL := "internal:/profiles/" + profileRelativePath
-
 	// Use the same URL context as regular imports for proper URL resolution
 	// Protect URL context creation with mutex to avoid race conditions
 	urlCreationMutex.Lock()
@@ -246,10 +311,10 @@ func (self *Import) newProfileImportSpec(profileName string) (*parsing.ImportSpe
 	bases = append(bases, self.Context.Bases...)
 	urlCreationMutex.Unlock()
 
-	// Create URL using the internal URL path with caching and request deduplication
-	profileURL, err := cachedURLCreation(urlContext, profileInternalURL, bases)
+	// Create URL with caching and request deduplication
+	profileURL, err := cachedURLCreation(context, urlContext, profile.Location, bases)
 	if err != nil {
-		self.Context.ReportError(fmt.Errorf("failed to create profile URL for %q: %w", profileName, err))
+		self.Context.ReportError(fmt.Errorf("failed to create profile URL for %q: %w", profile.String(), err))
 		return nil, false
 	}
 
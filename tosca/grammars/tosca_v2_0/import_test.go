@@ -0,0 +1,57 @@
+package tosca_v2_0
+
+import (
+	contextpkg "context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/tliron/exturl"
+)
+
+func TestCachedURLCreationTimeout(t *testing.T) {
+	// A server that accepts connections but never responds
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			if _, err := listener.Accept(); err != nil {
+				return
+			}
+		}
+	}()
+
+	creationTimeout, waitTimeout := urlCreationTimeout, urlWaitTimeout
+	urlCreationTimeout, urlWaitTimeout = 200*time.Millisecond, 100*time.Millisecond
+	defer func() {
+		urlCreationTimeout, urlWaitTimeout = creationTimeout, waitTimeout
+		ClearURLCache()
+	}()
+
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	url := "http://" + listener.Addr().String() + "/types.yaml"
+	done := make(chan error, 2)
+	for range 2 {
+		go func() {
+			// The caller's context has no deadline
+			_, err := cachedURLCreation(contextpkg.TODO(), urlContext, url, nil)
+			done <- err
+		}()
+	}
+
+	for range 2 {
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Errorf("created URL for unresponsive server")
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("URL creation not bounded")
+		}
+	}
+}
@@ -68,11 +68,11 @@ func (self *InterfaceImplementation) Render(definition *InterfaceImplementation)
 		case "ORCHESTRATOR":
 			supported = true
 		case "SELF", "HOST":
-			if path == "topology_template.node_templates" {
+			if (path == "topology_template.node_templates") || (path == "service_template.node_templates") {
 				supported = true
 			}
 		case "SOURCE", "TARGET":
-			if path == "topology_template.relationship_templates" {
+			if (path == "topology_template.relationship_templates") || (path == "service_template.relationship_templates") {
 				supported = true
 			}
 		}
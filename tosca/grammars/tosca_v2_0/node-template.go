@@ -164,19 +164,22 @@ func (self *NodeTemplate) normalizeInstance(normalServiceTemplate *normal.Servic
 	self.Interfaces.NormalizeForNodeTemplate(self, normalNodeTemplate)
 	self.Artifacts.Normalize(normalNodeTemplate)
 
-	// Normalize requirements and update their paths to reflect the instance name
+	return normalNodeTemplate
+}
+
+// Requirements refer to their target node templates, so they can be normalized only after all
+// node templates have been
+func (self *NodeTemplate) normalizeRequirements(normalNodeTemplate *normal.NodeTemplate) {
 	self.Requirements.Normalize(self, normalNodeTemplate)
 
 	// Update requirement paths to reflect the correct instance name
-	if instanceName != self.Name {
+	if normalNodeTemplate.Name != self.Name {
 		for _, requirement := range normalNodeTemplate.Requirements {
 			if requirement.Location != nil {
-				requirement.Location.UpdateNodeTemplatePath(self.Name, instanceName)
+				requirement.Location.UpdateNodeTemplatePath(self.Name, normalNodeTemplate.Name)
 			}
 		}
 	}
-
-	return normalNodeTemplate
 }
 
 //
@@ -186,6 +189,12 @@ func (self *NodeTemplate) normalizeInstance(normalServiceTemplate *normal.Servic
 type NodeTemplates []*NodeTemplate
 
 func (self NodeTemplates) Normalize(normalServiceTemplate *normal.ServiceTemplate) {
+	type instance struct {
+		nodeTemplate       *NodeTemplate
+		normalNodeTemplate *normal.NodeTemplate
+	}
+	var instances []instance
+
 	// First pass: create all node template instances based on count
 	for _, nodeTemplate := range self {
 		count := int64(1) // Default count
@@ -199,14 +208,18 @@ func (self NodeTemplates) Normalize(normalServiceTemplate *normal.ServiceTemplat
 				instanceName := fmt.Sprintf("%s_%d", nodeTemplate.Name, i)
 				normalNodeTemplate := nodeTemplate.normalizeInstance(normalServiceTemplate, instanceName, i)
 				normalServiceTemplate.NodeTemplates[instanceName] = normalNodeTemplate
+				instances = append(instances, instance{nodeTemplate, normalNodeTemplate})
 			}
 		} else {
 			// Single instance (count = 1)
 			normalNodeTemplate := nodeTemplate.normalizeInstance(normalServiceTemplate, nodeTemplate.Name, 0)
 			normalServiceTemplate.NodeTemplates[nodeTemplate.Name] = normalNodeTemplate
+			instances = append(instances, instance{nodeTemplate, normalNodeTemplate})
 		}
 	}
 
-	// Requirements normalization is now handled in normalizeInstance method
-	// This ensures paths are correctly updated for each instance
+	// Second pass: requirements (their targets must all exist by now, regardless of order)
+	for _, instance := range instances {
+		instance.nodeTemplate.normalizeRequirements(instance.normalNodeTemplate)
+	}
 }
@@ -24,6 +24,8 @@ type OperationAssignment struct {
 	Implementation *InterfaceImplementation `read:"implementation,InterfaceImplementation"`
 	Inputs         Values                   `read:"inputs,Value"`
 	Outputs        OutputMappings           `read:"outputs,OutputMapping"` // parameter mapping assignments
+
+	Definition *OperationDefinition `traverse:"ignore" json:"-" yaml:"-"`
 }
 
 func NewOperationAssignment(context *parsing.Context) *OperationAssignment {
@@ -60,6 +62,10 @@ func (self *OperationAssignment) Normalize(normalInterface *normal.Interface) *n
 
 	normalOperation := normalInterface.NewOperation(self.Name)
 
+	if self.Definition != nil {
+		self.Definition.Normalize(normalOperation)
+	}
+
 	if self.Description != nil {
 		normalOperation.Description = *self.Description
 	}
@@ -127,6 +133,8 @@ func (self OperationAssignments) render(definitions OperationDefinitions, contex
 			self[key] = assignment
 		}
 
+		assignment.Definition = definition
+
 		if assignment.Description == nil {
 			assignment.Description = definition.Description
 		}
@@ -75,11 +75,8 @@ func (self *OperationDefinition) Normalize(normalOperation *normal.Operation) {
 		self.Implementation.NormalizeOperation(normalOperation)
 	}
 
-	// TODO: input definitions
-	//self.InputDefinitions.Normalize(o.Inputs)
-
-	// TODO: output definitions
-	//self.OutputDefinitions.Normalize(o.Outputs)
+	self.InputDefinitions.NormalizeDefinitions(normalOperation.InputDefinitions)
+	self.OutputDefinitions.NormalizeDefinitions(normalOperation.OutputDefinitions)
 }
 
 //
@@ -148,6 +148,26 @@ func (self *ParameterDefinition) Normalize(context *parsing.Context) normal.Valu
 	return value.Normalize()
 }
 
+// Normalizes the definition itself (rather than its value)
+func (self *ParameterDefinition) NormalizeDefinition() *normal.ParameterDefinition {
+	normalParameterDefinition := normal.NewParameterDefinition()
+
+	self.Render()
+
+	normalParameterDefinition.Meta = NewValueMeta(self.Context, self.DataType, self.PropertyDefinition, nil)
+	normalParameterDefinition.Required = self.IsRequired()
+
+	if self.Default != nil {
+		normalParameterDefinition.Default = self.Default.Normalize()
+	}
+
+	if self.IsIncoming() {
+		normalParameterDefinition.Mapping = []string{*self.NodeTemplate, *self.AttributeName}
+	}
+
+	return normalParameterDefinition
+}
+
 // Apply parameter mapping to node attributes AND properties
 func (self *ParameterDefinition) ApplyMapping(nodeTemplates NodeTemplates, inputValue ard.Value) {
 	if self.IsIncoming() {
@@ -229,6 +249,12 @@ func (self ParameterDefinitions) Normalize(c normal.Values, context *parsing.Con
 	}
 }
 
+func (self ParameterDefinitions) NormalizeDefinitions(normalParameterDefinitions normal.ParameterDefinitions) {
+	for key, definition := range self {
+		normalParameterDefinitions[key] = definition.NormalizeDefinition()
+	}
+}
+
 // Apply all parameter mappings to node templates
 func (self ParameterDefinitions) ApplyMappings(nodeTemplates NodeTemplates, inputs map[string]ard.Value) {
 	for name, definition := range self {
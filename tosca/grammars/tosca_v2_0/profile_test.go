@@ -0,0 +1,112 @@
+package tosca_v2_0_test
+
+import (
+	contextpkg "context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-puccini/normal"
+	"github.com/tliron/go-puccini/tosca/parser"
+)
+
+const testServiceTemplate = `
+tosca_definitions_version: tosca_2_0
+
+imports:
+- profile: org.oasis-open.simple:2.0
+  namespace: tosca
+
+service_template:
+
+  node_templates:
+
+    server:
+      type: tosca:Compute
+      capabilities:
+        host:
+          properties:
+            cpu_frequency: 2.5 GHz
+            mem_size: 4 GiB
+            disk_size: 10 GB
+      attributes:
+        networks:
+          private:
+            network_name: private
+        ports:
+          http:
+            port_name: http
+      requirements:
+      - local_storage:
+          node: storage
+          relationship:
+            properties:
+              location: /mnt/storage
+      interfaces:
+        Standard:
+          operations:
+            create:
+              implementation:
+                primary: create.sh
+                operation_host: SELF
+            configure:
+              implementation:
+                primary: configure.sh
+                operation_host: HOST
+
+    storage:
+      type: tosca:Storage.BlockStorage
+      properties:
+        size: 1 TB
+`
+
+// Scalar types (e.g. "Size") and partial "NetworkInfo" and "PortInfo" values must not be reported
+func TestSimpleProfile(t *testing.T) {
+	normalServiceTemplate := parseTestServiceTemplate(t, testServiceTemplate)
+
+	server, ok := normalServiceTemplate.NodeTemplates["server"]
+	if !ok {
+		t.Fatalf("no \"server\" node template")
+	}
+
+	// Operation hosts are supported in "service_template" (not only in "topology_template")
+	operations := server.Interfaces["Standard"].Operations
+	for name, host := range map[string]string{"create": "SELF", "configure": "HOST"} {
+		if operation, ok := operations[name]; !ok {
+			t.Errorf("no %q operation", name)
+		} else if operation.Host != host {
+			t.Errorf("%q operation host: expected %q, got %q", name, host, operation.Host)
+		}
+	}
+
+	// "valid_capability_types" of AttachesTo
+	if len(server.Requirements) != 1 {
+		t.Fatalf("expected 1 requirement, got %d", len(server.Requirements))
+	} else if requirement := server.Requirements[0]; (requirement.CapabilityTypeName == nil) || (*requirement.CapabilityTypeName != "org.oasis-open.simple:2.0::Attachment") {
+		t.Errorf("requirement does not target the Attachment capability type")
+	}
+}
+
+// Utils
+
+func parseTestServiceTemplate(t *testing.T, content string) *normal.ServiceTemplate {
+	path := filepath.Join(t.TempDir(), "service-template.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	urlContext := exturl.NewContext()
+	t.Cleanup(func() {
+		urlContext.Release()
+	})
+
+	parserContext := parser.NewParser().NewContext()
+	parserContext.URL = urlContext.NewFileURL(path)
+	normalServiceTemplate, err := parserContext.Parse(contextpkg.TODO())
+	if err != nil {
+		t.Fatalf("%s\n%s", err.Error(), parserContext.GetProblems().ToString(true))
+	}
+
+	return normalServiceTemplate
+}
@@ -1,6 +1,7 @@
 package tosca_v2_0
 
 import (
+	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
 
@@ -94,3 +95,20 @@ func (self PropertyDefinitions) Inherit(parentDefinitions PropertyDefinitions) {
 		}
 	}
 }
+
+// Normalizes the definitions themselves (rather than assigned values) as coercible values:
+// the default if there is one, otherwise an empty value of the data type.
+func (self PropertyDefinitions) Normalize(normalValues normal.Values, context *parsing.Context) {
+	for name, definition := range self {
+		if definition.Default != nil {
+			normalValues[name] = definition.Default.Normalize()
+		} else {
+			// Should always appear, even if they have no default value
+			value := NewValue(context.MapChild(name, nil))
+			if definition.DataType != nil {
+				value.Render(definition.DataType, definition, false, true)
+			}
+			normalValues[name] = value.Normalize()
+		}
+	}
+}
@@ -63,7 +63,7 @@ func ReadPropertyMapping(context *parsing.Context) parsing.EntityPtr {
 		return self
 	}
 
-	if self.tryParseStringMapping(context) {
+	if !context.HasQuirk(parsing.QuirkSubstitutionMappingsPropertiesValues) && self.tryParseStringMapping(context) {
 		return self
 	}
 
@@ -1,6 +1,9 @@
 package tosca_v2_0
 
 import (
+	contextpkg "context"
+	"sync"
+
 	"github.com/tliron/exturl"
 	"github.com/tliron/go-ard"
 	"github.com/tliron/go-puccini/tosca/parsing"
@@ -24,7 +27,9 @@ type Repository struct {
 	URL         *string `read:"url" mandatory:""`
 	Credential  *Value  `read:"credential,Value"` // tosca:Credential
 
-	url exturl.URL
+	url                exturl.URL
+	urlProblemReported bool
+	urlLock            sync.Mutex
 }
 
 func NewRepository(context *parsing.Context) *Repository {
@@ -62,9 +67,24 @@ func (self *Repository) render() {
 	}
 }
 
-func (self *Repository) GetURL() exturl.URL {
-	if (self.url == nil) && (self.URL != nil) {
-		self.url = self.Context.URL.Context().NewAnyOrFileURL(*self.URL)
+// Fetches the repository via the runtime repositories registry, if available.
+// Note that the credential declared in the template is not used for fetching.
+func (self *Repository) GetURL(context contextpkg.Context) exturl.URL {
+	self.urlLock.Lock()
+	defer self.urlLock.Unlock()
+
+	if (self.url == nil) && (self.URL != nil) && !self.urlProblemReported {
+		urlContext := self.Context.URL.Context()
+		if self.Context.Repositories != nil {
+			if url, err := self.Context.Repositories.GetURL(context, urlContext, self.Name, *self.URL); err == nil {
+				self.url = url
+			} else {
+				self.Context.ReportError(err)
+				self.urlProblemReported = true // avoid reporting more than once
+			}
+		} else {
+			self.url = urlContext.NewAnyOrFileURL(*self.URL)
+		}
 	}
 
 	return self.url
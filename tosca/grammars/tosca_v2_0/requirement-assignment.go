@@ -12,7 +12,7 @@ import (
 //
 // RequirementAssignment
 //
-// [TOSCA-v2.0] @ ?
+// [TOSCA-v2.0] @ 8.5
 // [TOSCA-Simple-Profile-YAML-v1.3] @ 3.8.2
 // [TOSCA-Simple-Profile-YAML-v1.2] @ 3.8.2
 // [TOSCA-Simple-Profile-YAML-v1.1] @ 3.7.2
@@ -27,10 +27,10 @@ type RequirementAssignment struct {
 	TargetNodeTemplateNameOrTypeName *string                 `read:"node"`
 	TargetNodeFilter                 *NodeFilter             `read:"node_filter,NodeFilter"`
 	Relationship                     *RelationshipAssignment `read:"relationship,RelationshipAssignment"`
-	Count                            *int64                  `read:"count"`      // introduced in TOSCA 2.0, replacing "occurrences"
-	Directives                       *[]string               `read:"directives"` // introduced in TOSCA 2.0
-	Optional                         *bool                   `read:"optional"`   // introduced in TOSCA 2.0
-	// TODO: Allocation
+	Count                            *int64                  `read:"count"`                 // introduced in TOSCA 2.0, replacing "occurrences"
+	Directives                       *[]string               `read:"directives"`            // introduced in TOSCA 2.0
+	Optional                         *bool                   `read:"optional"`              // introduced in TOSCA 2.0
+	Allocation                       *Allocation             `read:"allocation,Allocation"` // introduced in TOSCA 2.0
 
 	TargetCapabilityType *CapabilityType `lookup:"capability,?TargetCapabilityNameOrTypeName" traverse:"ignore" json:"-" yaml:"-"`
 	TargetNodeTemplate   *NodeTemplate   `lookup:"node,TargetNodeTemplateNameOrTypeName" traverse:"ignore" json:"-" yaml:"-"`
@@ -75,6 +75,29 @@ func NewDefaultRequirementAssignment(index int, definition *RequirementDefinitio
 	return self
 }
 
+// The capability property definitions are taken from the capability definition of the target node
+// template or node type if the capability is specified by name, otherwise from the capability type
+func (self *RequirementAssignment) GetTargetCapabilityPropertyDefinitions() (PropertyDefinitions, bool) {
+	if self.capabilityIsName && (self.TargetCapabilityNameOrTypeName != nil) {
+		var nodeType *NodeType
+		if self.TargetNodeTemplate != nil {
+			nodeType = self.TargetNodeTemplate.NodeType
+		} else {
+			nodeType = self.TargetNodeType
+		}
+
+		if nodeType != nil {
+			if definition, ok := nodeType.CapabilityDefinitions[*self.TargetCapabilityNameOrTypeName]; ok {
+				return definition.PropertyDefinitions, true
+			}
+		}
+	} else if self.TargetCapabilityType != nil {
+		return self.TargetCapabilityType.PropertyDefinitions, true
+	}
+
+	return nil, false
+}
+
 func (self *RequirementAssignment) GetDefinition(nodeTemplate *NodeTemplate) (*RequirementDefinition, bool) {
 	if nodeTemplate.NodeType == nil {
 		return nil, false
@@ -217,6 +240,10 @@ func (self *RequirementAssignment) Normalize(nodeTemplate *NodeTemplate, normalN
 		normalRequirement.Optional = *self.Optional
 	}
 
+	if self.Allocation != nil {
+		self.Allocation.Normalize(normalRequirement)
+	}
+
 	return normalRequirement
 }
 
@@ -312,6 +339,14 @@ func (self *RequirementAssignments) Render(sourceNodeTemplate *NodeTemplate, con
 		} else {
 			assignment.Context.ReportUndeclared("requirement")
 		}
+
+		if assignment.Allocation != nil {
+			if definitions, ok := assignment.GetTargetCapabilityPropertyDefinitions(); ok {
+				assignment.Allocation.Render(definitions)
+			} else {
+				assignment.Allocation.Context.ReportPath(0, "cannot allocate without a known target capability")
+			}
+		}
 	}
 }
 
@@ -8,7 +8,7 @@ import (
 //
 // RequirementDefinition
 //
-// [TOSCA-v2.0] @ ?
+// [TOSCA-v2.0] @ 8.4
 // [TOSCA-Simple-Profile-YAML-v1.3] @ 3.7.3
 // [TOSCA-Simple-Profile-YAML-v1.2] @ 3.7.3
 // [TOSCA-Simple-Profile-YAML-v1.1] @ 3.6.3
@@ -113,6 +113,10 @@ func (self *ServiceTemplate) render() {
 	}
 
 	self.InputDefinitions.Render("input definition", mappedInputs)
+
+	if self.Context.HasQuirk(parsing.QuirkValidationETSINFVSOL001) {
+		self.validateETSINFVSOL001()
+	}
 }
 
 func (self *ServiceTemplate) Normalize(normalServiceTemplate *normal.ServiceTemplate) {
@@ -1,6 +1,9 @@
 package tosca_v2_0
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
@@ -52,7 +55,7 @@ func (self *TriggerDefinition) render() {
 }
 
 func (self *TriggerDefinition) Normalize(normalPolicy *normal.Policy) *normal.PolicyTrigger {
-	normalPolicyTrigger := normalPolicy.NewTrigger()
+	normalPolicyTrigger := normalPolicy.NewTrigger(self.Name)
 
 	if self.Description != nil {
 		normalPolicyTrigger.Description = *self.Description
@@ -71,11 +74,89 @@ func (self *TriggerDefinition) Normalize(normalPolicy *normal.Policy) *normal.Po
 		normalPolicyTrigger.Condition = normal.NewFunctionCall(fc)
 	}
 
-	// TODO: normalize actions
+	for _, action := range self.Action {
+		self.normalizeAction(action, normalPolicyTrigger)
+	}
 
 	return normalPolicyTrigger
 }
 
+// Actions apply to the policy's targets, which are known only per policy, so unlike workflow
+// activities they are resolved here rather than during rendering
+func (self *TriggerDefinition) normalizeAction(action *WorkflowActivityDefinition, normalPolicyTrigger *normal.PolicyTrigger) {
+	logNormalize.Debug("policy trigger action")
+
+	normalPolicy := normalPolicyTrigger.Policy
+	normalAction := normalPolicyTrigger.NewAction()
+
+	if action.DelegateWorkflowDefinition != nil {
+		normalAction.DelegateWorkflow = normalPolicy.ServiceTemplate.Workflows[action.DelegateWorkflowDefinition.Name]
+		return
+	} else if action.InlineWorkflowDefinition != nil {
+		normalAction.InlineWorkflow = normalPolicy.ServiceTemplate.Workflows[action.InlineWorkflowDefinition.Name]
+		return
+	}
+
+	if action.CallOperation != nil {
+		if action.CallOperation.InterfaceAndOperation == nil {
+			return
+		}
+
+		s := strings.SplitN(*action.CallOperation.InterfaceAndOperation, ".", 2)
+		if len(s) != 2 {
+			action.Context.FieldChild("call_operation", *action.CallOperation.InterfaceAndOperation).ReportValueWrongFormat("interface.operation")
+			return
+		}
+
+		// Only targets that have the operation (for groups that don't have it we try their members)
+		addNodeTemplate := func(nodeTemplate *normal.NodeTemplate) {
+			if hasNormalOperation(nodeTemplate.Interfaces, s[0], s[1]) {
+				for _, nodeTemplate_ := range normalAction.NodeTemplateTargets {
+					if nodeTemplate_ == nodeTemplate {
+						return
+					}
+				}
+				normalAction.NodeTemplateTargets = append(normalAction.NodeTemplateTargets, nodeTemplate)
+			}
+		}
+
+		for _, nodeTemplate := range normalPolicy.NodeTemplateTargets {
+			addNodeTemplate(nodeTemplate)
+		}
+		for _, group := range normalPolicy.GroupTargets {
+			if hasNormalOperation(group.Interfaces, s[0], s[1]) {
+				normalAction.GroupTargets = append(normalAction.GroupTargets, group)
+			} else {
+				for _, nodeTemplate := range group.Members {
+					addNodeTemplate(nodeTemplate)
+				}
+			}
+		}
+
+		if (len(normalAction.NodeTemplateTargets) == 0) && (len(normalAction.GroupTargets) == 0) {
+			action.Context.FieldChild("call_operation", *action.CallOperation.InterfaceAndOperation).ReportValueInvalid("operation", fmt.Sprintf("not found in any of the targets of policy %q", normalPolicy.Name))
+		}
+
+		normalAction.CallOperation = &normal.PolicyTriggerCallOperation{
+			Interface: s[0],
+			Operation: s[1],
+			Inputs:    make(normal.Values),
+		}
+		action.CallOperation.Inputs.Normalize(normalAction.CallOperation.Inputs)
+		return
+	}
+
+	normalAction.NodeTemplateTargets = append(normalAction.NodeTemplateTargets, normalPolicy.NodeTemplateTargets...)
+	normalAction.GroupTargets = append(normalAction.GroupTargets, normalPolicy.GroupTargets...)
+
+	if action.SetNodeState != nil {
+		normalAction.SetNodeState = *action.SetNodeState
+	} else if action.Update != nil {
+		normalAction.Update = make(normal.Values)
+		action.Update.Normalize(normalAction.Update)
+	}
+}
+
 //
 // TriggerDefinitions
 //
@@ -87,3 +168,13 @@ func (self TriggerDefinitions) Normalize(normalPolicy *normal.Policy) {
 		triggerDefinition.Normalize(normalPolicy)
 	}
 }
+
+// Utils
+
+func hasNormalOperation(normalInterfaces normal.Interfaces, interfaceName string, operationName string) bool {
+	if normalInterface, ok := normalInterfaces[interfaceName]; ok {
+		_, ok = normalInterface.Operations[operationName]
+		return ok
+	}
+	return false
+}
@@ -23,6 +23,7 @@ type WorkflowActivityDefinition struct {
 	InlineWorkflowDefinitionName   *string
 	SetNodeState                   *string
 	CallOperation                  *WorkflowActivityCallOperation
+	Update                         Values // only in policy trigger actions
 
 	DelegateWorkflowDefinition *WorkflowDefinition `lookup:"delegate,DelegateWorkflowDefinitionName" traverse:"ignore" json:"-" yaml:"-"`
 	InlineWorkflowDefinition   *WorkflowDefinition `lookup:"inline,InlineWorkflowDefinitionName" traverse:"ignore" json:"-" yaml:"-"`
@@ -60,6 +61,14 @@ func ReadWorkflowActivityDefinition(context *parsing.Context) parsing.EntityPtr
 				} else {
 					childContext.ReportValueMalformed("workflow activity definition", "unsupported operator")
 				}
+			case "update":
+				if childContext.ValidateType(ard.TypeMap) {
+					self.Update = make(Values)
+					for name, value := range childContext.Data.(ard.Map) {
+						name_ := yamlkeys.KeyString(name)
+						self.Update[name_] = ReadValue(childContext.MapChild(name_, value)).(*Value)
+					}
+				}
 			default:
 				childContext.ReportValueMalformed("workflow activity definition", "unsupported operator")
 				return self
@@ -74,7 +83,9 @@ func ReadWorkflowActivityDefinition(context *parsing.Context) parsing.EntityPtr
 }
 
 func (self *WorkflowActivityDefinition) Render(stepDefinition *WorkflowStepDefinition) {
-	if self.CallOperation != nil {
+	if self.Update != nil {
+		self.Context.FieldChild("update", nil).ReportValueMalformed("workflow activity definition", "\"update\" is supported only in policy trigger actions")
+	} else if self.CallOperation != nil {
 		self.CallOperation.Render(stepDefinition)
 	}
 }
@@ -21,7 +21,7 @@ type WorkflowDefinition struct {
 	Metadata                Metadata                        `read:"metadata,Metadata"`
 	Description             *string                         `read:"description"`
 	InputDefinitions        PropertyDefinitions             `read:"inputs,PropertyDefinition"`
-	PreconditionDefinitions WorkflowPreconditionDefinitions `read:"preconditions,WorkflowPreconditionDefinition"`
+	PreconditionDefinitions WorkflowPreconditionDefinitions `read:"preconditions,[]WorkflowPreconditionDefinition"`
 	StepDefinitions         WorkflowStepDefinitions         `read:"steps,WorkflowStepDefinition"`
 }
 
@@ -69,12 +69,8 @@ func (self *WorkflowDefinition) Normalize(normalServiceTemplate *normal.ServiceT
 		normalWorkflow.Description = *self.Description
 	}
 
-	// TODO: PreconditionDefinitions
-
-	// TODO: support property definitions
-	//self.InputDefinitions.Normalize(w.Inputs)
-
-	self.StepDefinitions.Normalize(normalWorkflow)
+	self.PreconditionDefinitions.Normalize(normalWorkflow)
+	self.InputDefinitions.Normalize(normalWorkflow.Inputs, self.Context.FieldChild("inputs", nil))
 
 	return normalWorkflow
 }
@@ -89,4 +85,10 @@ func (self WorkflowDefinitions) Normalize(normalServiceTemplate *normal.ServiceT
 	for _, workflowDefinition := range self {
 		normalServiceTemplate.Workflows[workflowDefinition.Name] = workflowDefinition.Normalize(normalServiceTemplate)
 	}
+
+	// Activities can delegate to (or inline) other workflows, so we can normalize steps only
+	// after all workflows exist
+	for _, workflowDefinition := range self {
+		workflowDefinition.StepDefinitions.Normalize(normalServiceTemplate.Workflows[workflowDefinition.Name])
+	}
 }
@@ -1,6 +1,7 @@
 package tosca_v2_0
 
 import (
+	"github.com/tliron/go-puccini/normal"
 	"github.com/tliron/go-puccini/tosca/parsing"
 )
 
@@ -35,8 +36,40 @@ func ReadWorkflowPreconditionDefinition(context *parsing.Context) parsing.Entity
 	return self
 }
 
+func (self *WorkflowPreconditionDefinition) Normalize(normalWorkflow *normal.Workflow) *normal.WorkflowPrecondition {
+	logNormalize.Debug("workflow precondition definition")
+
+	normalWorkflowPrecondition := normalWorkflow.NewPrecondition()
+
+	if self.TargetNodeTemplate != nil {
+		if normalNodeTemplate, ok := normalWorkflow.ServiceTemplate.NodeTemplates[self.TargetNodeTemplate.Name]; ok {
+			normalWorkflowPrecondition.TargetNodeTemplate = normalNodeTemplate
+		}
+	} else if self.TargetGroup != nil {
+		if normalGroup, ok := normalWorkflow.ServiceTemplate.Groups[self.TargetGroup.Name]; ok {
+			normalWorkflowPrecondition.TargetGroup = normalGroup
+		}
+	}
+
+	if self.TargetNodeRequirementName != nil {
+		normalWorkflowPrecondition.TargetRelationship = *self.TargetNodeRequirementName
+	}
+
+	if self.ConditionClause != nil {
+		normalWorkflowPrecondition.Condition = self.ConditionClause.Normalize()
+	}
+
+	return normalWorkflowPrecondition
+}
+
 //
 // WorkflowPreconditionDefinitions
 //
 
 type WorkflowPreconditionDefinitions []*WorkflowPreconditionDefinition
+
+func (self WorkflowPreconditionDefinitions) Normalize(normalWorkflow *normal.Workflow) {
+	for _, preconditionDefinition := range self {
+		preconditionDefinition.Normalize(normalWorkflow)
+	}
+}
@@ -96,6 +96,14 @@ func (self *WorkflowStepDefinition) Normalize(normalWorkflow *normal.Workflow) *
 		}
 	}
 
+	if self.TargetNodeRequirementName != nil {
+		normalWorkflowStep.TargetRelationship = *self.TargetNodeRequirementName
+	}
+
+	if self.FilterConditionClauses != nil {
+		normalWorkflowStep.Filter = self.FilterConditionClauses.Normalize()
+	}
+
 	for _, activity := range self.ActivityDefinitions {
 		activity.Normalize(normalWorkflowStep)
 	}
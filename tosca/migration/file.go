@@ -0,0 +1,425 @@
+package migration
+
+import (
+	contextpkg "context"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/tosca/grammars/tosca_v2_0"
+	"github.com/tliron/go-puccini/tosca/parser"
+	"gopkg.in/yaml.v3"
+)
+
+func (self *Migration) migrateFile(context contextpkg.Context, node *yaml.Node) {
+	var path ard.Path
+
+	// Before TOSCA 1.3 operations were not under an "operations" keyword
+	self.operationsKeyword = *self.ServiceFile.ToscaDefinitionsVersion == "tosca_simple_yaml_1_3"
+
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "tosca_definitions_version":
+			value.Value = "tosca_2_0"
+
+		case "namespace":
+			key.Value = "profile"
+
+		case "imports":
+			self.migrateImports(context, value, path)
+
+		case "artifact_types", "capability_types", "data_types", "group_types", "interface_types", "node_types", "policy_types", "relationship_types":
+			section := key.Value
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateType(value, path.AppendMap(key.Value), section, key.Value)
+			})
+
+		case "topology_template":
+			key.Value = "service_template"
+			self.migrateServiceTemplate(value, path)
+		}
+	})
+
+	if self.profileUsed {
+		self.addProfileImport(node)
+	}
+}
+
+func (self *Migration) migrateImports(context contextpkg.Context, node *yaml.Node, path ard.Path) {
+	if node = resolveAlias(node); (node == nil) || (node.Kind != yaml.SequenceNode) {
+		return
+	}
+
+	for index, item := range node.Content {
+		path := path.AppendList(index)
+
+		// Sequenced list (TOSCA 1.0)
+		if item_ := resolveAlias(item); (item_ != nil) && (item_.Kind == yaml.MappingNode) && (len(item_.Content) == 2) {
+			if value := resolveAlias(item_.Content[1]); (value != nil) && (value.Kind == yaml.MappingNode) {
+				moveComments(item_.Content[0], value)
+				node.Content[index] = value
+				item = value
+			}
+		}
+
+		forEachPair(item, func(index int, key *yaml.Node, value *yaml.Node) {
+			switch key.Value {
+			case "file":
+				key.Value = "url"
+			case "namespace_prefix":
+				key.Value = "namespace"
+			case "namespace_uri":
+				self.report(key, path.AppendField(key.Value), "\"namespace_uri\" was removed in TOSCA 2.0")
+			}
+		})
+
+		if index < len(self.ServiceFile.Imports) {
+			if file, ok := self.getImportedFile(context, self.ServiceFile.Imports[index]); ok && isTOSCA1(file) {
+				self.reportf(item, path, "imported file %q must be migrated to TOSCA 2.0", file.Context.URL.String())
+			}
+		}
+	}
+}
+
+func (self *Migration) getImportedFile(context contextpkg.Context, import_ *tosca_v2_0.Import) (*tosca_v2_0.File, bool) {
+	if importSpec, ok := import_.NewImportSpec(context, self.ServiceFile.File); ok {
+		url := importSpec.URL.String()
+		for _, file := range self.Imports {
+			if file.GetContext().URL.String() == url {
+				return getFile(file)
+			}
+		}
+	}
+	return nil, false
+}
+
+func (self *Migration) addProfileImport(node *yaml.Node) {
+	import_ := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+		newString("profile"), newString(ProfileName),
+		newString("namespace"), newString(ProfileNamespace),
+	}}
+
+	if _, imports, ok := getPair(node, "imports"); ok {
+		if imports = resolveAlias(imports); imports.Kind == yaml.SequenceNode {
+			imports.Content = append([]*yaml.Node{import_}, imports.Content...)
+			return
+		}
+	}
+
+	// Insert after "tosca_definitions_version" and "profile" (if they exist)
+	index := 0
+	for (index+1 < len(node.Content)) && ((node.Content[index].Value == "tosca_definitions_version") || (node.Content[index].Value == "profile")) {
+		index += 2
+	}
+	content := append([]*yaml.Node{newString("imports"), newList(import_)}, node.Content[index:]...)
+	node.Content = append(node.Content[:index], content...)
+}
+
+// The section is the file section in which the type is (e.g. "node_types")
+func (self *Migration) migrateType(node *yaml.Node, path ard.Path, section string, name string) {
+	var nodeType *tosca_v2_0.NodeType
+	if section == "node_types" {
+		if entityPtr, ok := self.ServiceFile.Context.Namespace.LookupForType(name, typeSections[section]); ok {
+			nodeType = entityPtr.(*tosca_v2_0.NodeType)
+		}
+	}
+
+	var dataType *tosca_v2_0.DataType
+	if section == "data_types" {
+		if entityPtr, ok := self.ServiceFile.Context.Namespace.LookupForType(name, typeSections[section]); ok {
+			dataType = entityPtr.(*tosca_v2_0.DataType)
+		}
+	}
+
+	self.migrateDerivedFrom(node, path, section)
+	self.migrateConstraints(node, path, nil, dataType)
+
+	if section == "interface_types" {
+		self.moveOperations(node, "derived_from", "version", "metadata", "description", "inputs", "operations", "notifications")
+	}
+
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "properties":
+			self.migratePropertyDefinitions(value, path, nodeType)
+
+		case "attributes":
+			self.migratePropertyDefinitions(value, path, nodeType)
+
+		case "key_schema", "entry_schema":
+			self.migrateSchema(value, path)
+
+		case "requirements":
+			forEachSequencedPair(value, func(key *yaml.Node, value *yaml.Node) {
+				self.migrateRequirementDefinition(value, path.AppendMap(key.Value))
+			})
+
+		case "capabilities":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateCapabilityDefinition(value, path.AppendMap(key.Value))
+			})
+
+		case "interfaces":
+			if section == "group_types" {
+				self.report(key, path, "group type interfaces were removed in TOSCA 2.0")
+			} else {
+				forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+					self.migrateInterfaceDefinition(value, path.AppendMap(key.Value), nodeType)
+				})
+			}
+
+		case "artifacts":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateArtifact(value, path.AppendMap(key.Value), nodeType)
+			})
+
+		case "inputs":
+			self.migrateOperationInputs(value, path, nodeType)
+
+		case "operations":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateOperationDefinition(value, path.AppendMap(key.Value), nodeType)
+			})
+
+		case "notifications":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateOperationDefinition(value, path.AppendMap(key.Value), nodeType)
+			})
+
+		case "valid_source_types":
+			key.Value = "valid_source_node_types"
+			self.migrateTypeNames(value, path, "node_types")
+
+		case "valid_target_types":
+			key.Value = "valid_capability_types"
+			self.migrateTypeNames(value, path, "capability_types")
+
+		case "members":
+			self.migrateTypeNames(value, path, "node_types")
+
+		case "targets":
+			self.migrateTypeNames(value, path, "node_types", "group_types")
+
+		case "triggers":
+			self.report(key, path, "triggers must be migrated manually")
+		}
+	})
+}
+
+func (self *Migration) migratePropertyDefinitions(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		self.migratePropertyDefinition(value, path.AppendMap(key.Value), nodeType)
+	})
+}
+
+// For property, attribute, and parameter definitions
+func (self *Migration) migratePropertyDefinition(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType) {
+	// Must happen before the type names are migrated
+	dataType := self.lookupDataType(getValue(node, "type"))
+	entryDataType := self.lookupSchemaDataType(getValue(node, "entry_schema"))
+
+	self.migrateTypeName(getValue(node, "type"), path.AppendField("type"), "data_types")
+	self.migrateConstraints(node, path, nodeType, dataType)
+
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "key_schema", "entry_schema":
+			self.migrateSchema(value, path)
+
+		case "default", "value":
+			self.migrateScalars(value, path, dataType, entryDataType)
+			self.migrateValue(value, path, nodeType)
+		}
+	})
+}
+
+func (self *Migration) migrateSchema(node *yaml.Node, path ard.Path) {
+	if isScalar(node) {
+		// Short notation
+		self.migrateTypeName(node, path, "data_types")
+		return
+	}
+
+	self.migratePropertyDefinition(node, path, nil)
+}
+
+func (self *Migration) migrateRequirementDefinition(node *yaml.Node, path ard.Path) {
+	if isScalar(node) {
+		// Short notation
+		self.migrateTypeName(node, path, "capability_types")
+		return
+	}
+
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "capability":
+			self.migrateTypeName(value, path, "capability_types")
+
+		case "node":
+			self.migrateTypeName(value, path, "node_types")
+
+		case "relationship":
+			if isScalar(value) {
+				self.migrateTypeName(value, path, "relationship_types")
+			} else {
+				self.migrateTypeName(getValue(value, "type"), path.AppendField("type"), "relationship_types")
+				forEachPair(getValue(value, "interfaces"), func(index int, key *yaml.Node, value *yaml.Node) {
+					self.migrateInterfaceDefinition(value, path.AppendField("interfaces").AppendMap(key.Value), nil)
+				})
+			}
+
+		case "occurrences":
+			key.Value = "count_range"
+
+		case "node_filter":
+			// Not in TOSCA 1.x, but supported by Puccini
+		}
+	})
+}
+
+func (self *Migration) migrateCapabilityDefinition(node *yaml.Node, path ard.Path) {
+	if isScalar(node) {
+		// Short notation
+		self.migrateTypeName(node, path, "capability_types")
+		return
+	}
+
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "type":
+			self.migrateTypeName(value, path, "capability_types")
+
+		case "properties", "attributes":
+			self.migratePropertyDefinitions(value, path, nil)
+
+		case "valid_source_types":
+			key.Value = "valid_source_node_types"
+			self.migrateTypeNames(value, path, "node_types")
+
+		case "occurrences":
+			self.report(key, path, "capability definition \"occurrences\" has no TOSCA 2.0 equivalent")
+		}
+	})
+}
+
+func (self *Migration) migrateInterfaceDefinition(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType) {
+	self.migrateTypeName(getValue(node, "type"), path.AppendField("type"), "interface_types")
+	self.moveOperations(node, "type", "description", "metadata", "inputs", "operations", "notifications")
+
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "inputs":
+			self.migrateOperationInputs(value, path, nodeType)
+
+		case "operations", "notifications":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateOperationDefinition(value, path.AppendMap(key.Value), nodeType)
+			})
+		}
+	})
+}
+
+func (self *Migration) migrateOperationDefinition(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "inputs", "outputs":
+			self.migrateOperationInputs(value, path, nodeType)
+		}
+	})
+}
+
+// TOSCA 1.x operation and interface definitions may have either parameter definitions or values as
+// inputs
+func (self *Migration) migrateOperationInputs(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendMap(key.Value)
+		if _, _, ok := getPair(value, "type"); ok {
+			self.migratePropertyDefinition(value, path, nodeType)
+		} else {
+			self.migrateValue(value, path, nodeType)
+		}
+	})
+}
+
+func (self *Migration) migrateArtifact(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "type":
+			self.migrateTypeName(value, path, "artifact_types")
+
+		case "properties":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateValue(value, path.AppendMap(key.Value), nodeType)
+			})
+		}
+	})
+}
+
+// Before TOSCA 1.3 operations were not under an "operations" keyword, so all keys other than the
+// reserved ones are moved there
+func (self *Migration) moveOperations(node *yaml.Node, reserved ...string) {
+	if self.operationsKeyword {
+		return
+	}
+
+	if node = resolveAlias(node); (node == nil) || (node.Kind != yaml.MappingNode) {
+		return
+	}
+
+	isReserved := func(key string) bool {
+		for _, reserved_ := range reserved {
+			if key == reserved_ {
+				return true
+			}
+		}
+		return false
+	}
+
+	var content []*yaml.Node
+	var operations []*yaml.Node
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		if isReserved(key.Value) {
+			content = append(content, key, value)
+		} else {
+			operations = append(operations, key, value)
+		}
+	})
+
+	if len(operations) > 0 {
+		content = append(content, newString("operations"), &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: operations})
+	}
+
+	node.Content = content
+}
+
+// Utils
+
+func getValue(node *yaml.Node, key string) *yaml.Node {
+	_, value, _ := getPair(node, key)
+	return value
+}
+
+func getFile(file *parser.File) (*tosca_v2_0.File, bool) {
+	switch entity := file.EntityPtr.(type) {
+	case *tosca_v2_0.File:
+		return entity, true
+	case *tosca_v2_0.ServiceFile:
+		return entity.File, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,125 @@
+package migration
+
+import (
+	"strings"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/tosca/grammars/tosca_v2_0"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"gopkg.in/yaml.v3"
+)
+
+// The TOSCA 2.0 function call prefix
+const functionPrefix = "$"
+
+// Migrates a value, which may contain function calls. In TOSCA 2.0 function names are prefixed with
+// "$", so map keys that already begin with "$" must be escaped as "$$".
+//
+// The node type is that of SELF, if known.
+func (self *Migration) migrateValue(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType) {
+	if node = resolveAlias(node); (node == nil) || (node.Kind == yaml.ScalarNode) || !self.visit(node) {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		if name, arguments, ok := self.getFunctionCall(node); ok {
+			node.Content[0].Value = functionPrefix + name
+			path = path.AppendField(name)
+
+			switch name {
+			case "get_property", "get_attribute":
+				self.migrateGetPath(arguments, path, name, nodeType)
+			}
+
+			self.migrateValue(arguments, path, nodeType)
+			return
+		}
+
+		forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+			if key.Kind == yaml.MappingNode {
+				// Complex key, which may be a function call
+				self.migrateValue(key, path, nodeType)
+			} else if strings.HasPrefix(key.Value, functionPrefix) {
+				key.Value = functionPrefix + key.Value
+			}
+			self.migrateValue(value, path.AppendMap(key.Value), nodeType)
+		})
+
+	case yaml.SequenceNode:
+		for index, item := range node.Content {
+			self.migrateValue(item, path.AppendList(index), nodeType)
+		}
+	}
+}
+
+// In TOSCA 2.0 capabilities must be marked with "CAPABILITY" in property and attribute paths, and
+// "HOST" is no longer supported
+func (self *Migration) migrateGetPath(arguments *yaml.Node, path ard.Path, name string, nodeType *tosca_v2_0.NodeType) {
+	if arguments = resolveAlias(arguments); (arguments == nil) || (arguments.Kind != yaml.SequenceNode) || (len(arguments.Content) < 2) {
+		return
+	}
+
+	entity := resolveAlias(arguments.Content[0])
+	if !isScalar(entity) {
+		return
+	}
+
+	switch entity.Value {
+	case "HOST":
+		self.reportf(entity, path.AppendList(0), "%q is not supported in TOSCA 2.0", entity.Value)
+		return
+
+	case "SELF":
+
+	case "SOURCE", "TARGET":
+		nodeType = nil
+
+	default:
+		nodeType = nil
+		if entityPtr, ok := self.ServiceFile.Context.Namespace.LookupForType(entity.Value, nodeTemplatePtrType); ok {
+			nodeType = entityPtr.(*tosca_v2_0.NodeTemplate).NodeType
+		}
+	}
+
+	// With only two arguments the second must be a property or an attribute
+	if len(arguments.Content) < 3 {
+		return
+	}
+
+	item := resolveAlias(arguments.Content[1])
+	if !isScalar(item) {
+		return
+	}
+
+	if nodeType == nil {
+		self.reportf(item, path.AppendList(1), "cannot determine whether %q is a capability, in which case it must be preceded by \"CAPABILITY\"", item.Value)
+		return
+	}
+
+	if _, ok := nodeType.PropertyDefinitions[item.Value]; ok {
+		return
+	}
+	if name == "get_attribute" {
+		if _, ok := nodeType.AttributeDefinitions[item.Value]; ok {
+			return
+		}
+	}
+
+	if _, ok := nodeType.CapabilityDefinitions[item.Value]; ok {
+		arguments.Content = append(arguments.Content[:1], append([]*yaml.Node{newString("CAPABILITY")}, arguments.Content[1:]...)...)
+	} else if _, ok := nodeType.RequirementDefinitions[item.Value]; ok {
+		self.reportf(item, path.AppendList(1), "requirement %q must be migrated to a \"RELATIONSHIP\" path", item.Value)
+	}
+}
+
+func (self *Migration) getFunctionCall(node *yaml.Node) (string, *yaml.Node, bool) {
+	if len(node.Content) == 2 {
+		if key := node.Content[0]; key.Kind == yaml.ScalarNode {
+			if _, ok := self.ServiceFile.Context.ScriptletNamespace.Lookup(parsing.MetadataFunctionPrefix + key.Value); ok {
+				return key.Value, node.Content[1], true
+			}
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,109 @@
+package migration
+
+import (
+	contextpkg "context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tliron/commonlog"
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-kutil/problems"
+	"github.com/tliron/go-puccini/tosca/grammars/tosca_v2_0"
+	"github.com/tliron/go-puccini/tosca/parser"
+	"gopkg.in/yaml.v3"
+)
+
+var log = commonlog.GetLogger("puccini.migration")
+
+const (
+	// The TOSCA 2.0 profile that replaces the TOSCA 1.x normative types
+	ProfileName      = "org.oasis-open.simple:2.0"
+	ProfileNamespace = "tosca"
+)
+
+//
+// Migration
+//
+// Migrates a TOSCA 1.x service file to TOSCA 2.0 by rewriting its YAML node tree, so that comments
+// are kept where possible. Type information (e.g. whether a name refers to a property or a
+// capability) is taken from the file as parsed by its TOSCA 1.x grammar, which must have completed
+// phase 4 (inheritance).
+//
+// Anything that cannot be migrated automatically is reported as a problem.
+//
+
+type Migration struct {
+	ServiceFile *tosca_v2_0.ServiceFile
+	Imports     parser.Files
+	Problems    *problems.Problems
+
+	operationsKeyword bool
+	profileUsed       bool
+	visited           map[*yaml.Node]struct{}
+}
+
+func NewMigration(parserContext *parser.Context) (*Migration, error) {
+	if parserContext.Root != nil {
+		if serviceFile, ok := parserContext.Root.EntityPtr.(*tosca_v2_0.ServiceFile); ok && isTOSCA1(serviceFile.File) {
+			return &Migration{
+				ServiceFile: serviceFile,
+				Imports:     parserContext.Root.Imports,
+				Problems:    serviceFile.Context.Problems.NewProblems(),
+				visited:     make(map[*yaml.Node]struct{}),
+			}, nil
+		}
+	}
+
+	return nil, errors.New("not a TOSCA 1.x service template")
+}
+
+// Reads the service file and returns its migrated YAML node tree
+func (self *Migration) Migrate(context contextpkg.Context) (*yaml.Node, error) {
+	url := self.ServiceFile.Context.URL
+	log.Infof("migrating %q", url.String())
+
+	reader, err := url.Open(context)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var document yaml.Node
+	if err := yaml.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, err
+	}
+
+	if len(document.Content) > 0 {
+		self.migrateFile(context, document.Content[0])
+	}
+
+	return &document, nil
+}
+
+func (self *Migration) report(node *yaml.Node, path ard.Path, message string) {
+	item := path.String()
+	if item != "" {
+		item = self.Problems.Stylist.Path(item)
+	}
+	self.Problems.ReportFull(1, self.ServiceFile.Context.URL.String(), item, message, node.Line, node.Column)
+}
+
+func (self *Migration) reportf(node *yaml.Node, path ard.Path, format string, arg ...any) {
+	self.report(node, path, fmt.Sprintf(format, arg...))
+}
+
+// Returns false if the node was already visited (via a YAML alias)
+func (self *Migration) visit(node *yaml.Node) bool {
+	if _, ok := self.visited[node]; ok {
+		return false
+	}
+	self.visited[node] = struct{}{}
+	return true
+}
+
+// Utils
+
+func isTOSCA1(file *tosca_v2_0.File) bool {
+	return (file.ToscaDefinitionsVersion != nil) && strings.HasPrefix(*file.ToscaDefinitionsVersion, "tosca_simple_")
+}
@@ -0,0 +1,117 @@
+package migration_test
+
+import (
+	"bytes"
+	contextpkg "context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-kutil/terminal"
+	"github.com/tliron/go-puccini/tosca/migration"
+	"github.com/tliron/go-puccini/tosca/parser"
+)
+
+var update = flag.Bool("update", false, "update the expected files in testdata")
+
+// Each "testdata/<name>.yaml" is migrated and compared with "testdata/<name>.expected.yaml" and
+// "testdata/<name>.expected-problems.txt"
+func TestMigrate(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.yaml"))
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	parser_ := parser.NewParser()
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".expected.yaml") {
+			continue
+		}
+
+		name := strings.TrimSuffix(path, ".yaml")
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			migrated, problems := migrateTestFile(t, parser_, path)
+			assertTestGolden(t, name+".expected.yaml", migrated)
+			assertTestGolden(t, name+".expected-problems.txt", problems)
+		})
+	}
+}
+
+// Utils
+
+func migrateTestFile(t *testing.T, parser_ *parser.Parser, path string) (string, string) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	context := contextpkg.Background()
+	parserContext := parser_.NewContext()
+	parserContext.Stylist = terminal.NewStylist(false)
+	defer parserContext.Repositories.Release()
+
+	// The migration needs only the types
+	ok := parserContext.ReadRoot(context, urlContext.NewFileURL(path), nil, "")
+	parserContext.MergeProblems()
+	if !ok || !parserContext.GetProblems().Empty() {
+		t.Fatalf("%s", parserContext.GetProblems().ToString(true))
+	}
+	parserContext.AddNamespaces()
+	parserContext.LookupNames()
+	parserContext.AddHierarchies()
+	parserContext.Inherit(nil)
+	parserContext.MergeProblems()
+	if !parserContext.GetProblems().Empty() {
+		t.Fatalf("%s", parserContext.GetProblems().ToString(true))
+	}
+
+	migration_, err := migration.NewMigration(parserContext)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	document, err := migration_.Migrate(context)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	var migrated bytes.Buffer
+	if err := migration.Encode(document, &migrated); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	// Problem locations are in the original file
+	var problems strings.Builder
+	for _, problem := range migration_.Problems.Slice() {
+		fmt.Fprintf(&problems, "@%d,%d %s: %s\n", problem.Row, problem.Column, problem.Item, problem.Message)
+	}
+
+	return migrated.String(), problems.String()
+}
+
+func assertTestGolden(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	if content != string(expected) {
+		t.Errorf("%s does not match:\n%s", path, content)
+	}
+}
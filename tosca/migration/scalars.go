@@ -0,0 +1,106 @@
+package migration
+
+import (
+	"regexp"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/tosca/grammars/tosca_v1_3"
+	"github.com/tliron/go-puccini/tosca/grammars/tosca_v2_0"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"gopkg.in/yaml.v3"
+)
+
+type scalarUnitType struct {
+	re            *regexp.Regexp
+	measures      tosca_v1_3.ScalarUnitMeasures
+	caseSensitive bool
+}
+
+// The TOSCA 2.0 profile has the same units as the TOSCA 1.x normative scalar-unit types
+var scalarUnitTypes = map[string]scalarUnitType{
+	"scalar-unit.size":      {tosca_v1_3.ScalarUnitSizeRE, tosca_v1_3.ScalarUnitSizeMeasures, false},
+	"scalar-unit.time":      {tosca_v1_3.ScalarUnitTimeRE, tosca_v1_3.ScalarUnitTimeMeasures, false},
+	"scalar-unit.frequency": {tosca_v1_3.ScalarUnitFrequencyRE, tosca_v1_3.ScalarUnitFrequencyMeasures, false},
+	"scalar-unit.bitrate":   {tosca_v1_3.ScalarUnitBitrateRE, tosca_v1_3.ScalarUnitBitrateMeasures, true},
+}
+
+// TOSCA 2.0 scalar units are case sensitive and must be separated from the number by whitespace.
+// For lists and maps the entry data type is used instead.
+func (self *Migration) migrateScalars(node *yaml.Node, path ard.Path, dataType *tosca_v2_0.DataType, entryDataType *tosca_v2_0.DataType) {
+	if node = resolveAlias(node); node == nil {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if scalarUnitType, ok := getScalarUnitType(dataType); ok {
+			if matches := scalarUnitType.re.FindStringSubmatch(node.Value); len(matches) == 3 {
+				if unit, _ := scalarUnitType.measures.Get(matches[2], scalarUnitType.caseSensitive); unit != "" {
+					node.Value = matches[1] + " " + unit
+					node.Style &^= yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+					return
+				}
+			}
+			self.reportf(node, path, "malformed scalar %q cannot be migrated", node.Value)
+		}
+
+	case yaml.SequenceNode:
+		if entryDataType != nil {
+			for index, item := range node.Content {
+				self.migrateScalars(item, path.AppendList(index), entryDataType, nil)
+			}
+		}
+
+	case yaml.MappingNode:
+		if entryDataType != nil {
+			forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateScalars(value, path.AppendMap(key.Value), entryDataType, nil)
+			})
+		}
+	}
+}
+
+// For values of property and attribute definitions
+func (self *Migration) migrateDefinedScalars(node *yaml.Node, path ard.Path, definition *tosca_v2_0.AttributeDefinition) {
+	if definition != nil {
+		var entryDataType *tosca_v2_0.DataType
+		if definition.EntrySchema != nil {
+			entryDataType = definition.EntrySchema.DataType
+		}
+		self.migrateScalars(node, path, definition.DataType, entryDataType)
+	}
+}
+
+func (self *Migration) lookupDataType(node *yaml.Node) *tosca_v2_0.DataType {
+	if node = resolveAlias(node); isScalar(node) {
+		if entityPtr, ok := self.ServiceFile.Context.Namespace.LookupForType(node.Value, typeSections["data_types"]); ok {
+			return entityPtr.(*tosca_v2_0.DataType)
+		}
+	}
+	return nil
+}
+
+func (self *Migration) lookupSchemaDataType(node *yaml.Node) *tosca_v2_0.DataType {
+	if isScalar(node) {
+		// Short notation
+		return self.lookupDataType(node)
+	}
+	return self.lookupDataType(getValue(node, "type"))
+}
+
+// Utils
+
+func getScalarUnitType(dataType *tosca_v2_0.DataType) (scalarUnitType, bool) {
+	for ; dataType != nil; dataType = dataType.Parent {
+		if isNormative(dataType) {
+			if scalarUnitType, ok := scalarUnitTypes[dataType.Name]; ok {
+				return scalarUnitType, true
+			}
+		}
+	}
+	return scalarUnitType{}, false
+}
+
+func isNormative(entityPtr parsing.EntityPtr) bool {
+	return hasNormativeURL(parsing.GetContext(entityPtr).URL.String())
+}
@@ -0,0 +1,300 @@
+package migration
+
+import (
+	"strings"
+
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/tosca/grammars/tosca_v2_0"
+	"gopkg.in/yaml.v3"
+)
+
+func (self *Migration) migrateServiceTemplate(node *yaml.Node, path ard.Path) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "inputs", "outputs":
+			self.migratePropertyDefinitions(value, path, nil)
+
+		case "node_templates":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateNodeTemplate(value, path.AppendMap(key.Value), key.Value)
+			})
+
+		case "relationship_templates":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateRelationship(value, path.AppendMap(key.Value))
+			})
+
+		case "groups":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateGroupOrPolicy(value, path.AppendMap(key.Value), "group_types")
+			})
+
+		case "policies":
+			forEachSequencedPair(value, func(key *yaml.Node, value *yaml.Node) {
+				self.migrateGroupOrPolicy(value, path.AppendMap(key.Value), "policy_types")
+			})
+
+		case "substitution_mappings":
+			self.migrateSubstitutionMappings(value, path)
+		}
+	})
+}
+
+func (self *Migration) migrateSubstitutionMappings(node *yaml.Node, path ard.Path) {
+	self.migrateTypeName(getValue(node, "node_type"), path.AppendField("node_type"), "node_types")
+	self.migrateNodeFilter(node, "substitution_filter", path, "SELF", nil)
+
+	// TOSCA 2.0 maps operations to workflows instead of interfaces to node template interfaces
+	path = path.AppendField("interfaces")
+	forEachPair(getValue(node, "interfaces"), func(index int, key *yaml.Node, value *yaml.Node) {
+		if value = resolveAlias(value); (value != nil) && (value.Kind == yaml.SequenceNode) {
+			self.reportf(value, path.AppendMap(key.Value), "interface mapping %q must be migrated manually", key.Value)
+		}
+	})
+}
+
+func (self *Migration) migrateNodeTemplate(node *yaml.Node, path ard.Path, name string) {
+	var nodeType *tosca_v2_0.NodeType
+	if entityPtr, ok := self.ServiceFile.Context.Namespace.LookupForType(name, nodeTemplatePtrType); ok {
+		nodeType = entityPtr.(*tosca_v2_0.NodeTemplate).NodeType
+	}
+
+	// In TOSCA 1.x the node filter applies to the requirements, while in TOSCA 2.0 it is a filter for
+	// selecting the node itself
+	if _, filter, ok := getPair(node, "node_filter"); ok {
+		if hasDirective(node, "select") {
+			self.migrateNodeFilter(node, "node_filter", path, "SELF", nodeType)
+		} else {
+			self.report(filter, path.AppendField("node_filter"), "node filter without \"select\" directive must be migrated manually to the requirements")
+		}
+	}
+
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "type":
+			self.migrateTypeName(value, path, "node_types")
+
+		case "properties":
+			if nodeType != nil {
+				self.migrateTemplateValues(value, path, nodeType, nodeType.PropertyDefinitions, nil)
+			} else {
+				self.migrateValues(value, path, nil)
+			}
+
+		case "attributes":
+			self.unpackAttributeValues(value)
+			if nodeType != nil {
+				self.migrateTemplateValues(value, path, nodeType, nodeType.PropertyDefinitions, nodeType.AttributeDefinitions)
+			} else {
+				self.migrateValues(value, path, nil)
+			}
+
+		case "capabilities":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				path := path.AppendMap(key.Value)
+				var definition *tosca_v2_0.CapabilityDefinition
+				if nodeType != nil {
+					definition = nodeType.CapabilityDefinitions[key.Value]
+				}
+
+				self.unpackAttributeValues(getValue(value, "attributes"))
+				if definition != nil {
+					self.migrateTemplateValues(getValue(value, "properties"), path.AppendField("properties"), nodeType, definition.PropertyDefinitions, nil)
+					self.migrateTemplateValues(getValue(value, "attributes"), path.AppendField("attributes"), nodeType, definition.PropertyDefinitions, definition.AttributeDefinitions)
+				} else {
+					self.migrateValues(getValue(value, "properties"), path.AppendField("properties"), nodeType)
+					self.migrateValues(getValue(value, "attributes"), path.AppendField("attributes"), nodeType)
+				}
+			})
+
+		case "requirements":
+			forEachSequencedPair(value, func(key *yaml.Node, value *yaml.Node) {
+				self.migrateRequirementAssignment(value, path.AppendMap(key.Value), nodeType)
+			})
+
+		case "interfaces":
+			self.migrateInterfaceAssignments(value, path, nodeType)
+
+		case "artifacts":
+			forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+				self.migrateArtifact(value, path.AppendMap(key.Value), nodeType)
+			})
+		}
+	})
+}
+
+func (self *Migration) migrateRequirementAssignment(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType) {
+	if isScalar(node) {
+		// Short notation
+		self.migrateTypeName(node, path, "node_types")
+		return
+	}
+
+	self.migrateNodeFilter(node, "node_filter", path, "TARGET", nil)
+
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "node":
+			self.migrateTypeName(value, path, "node_types")
+
+		case "capability":
+			self.migrateTypeName(value, path, "capability_types")
+
+		case "relationship":
+			if isScalar(value) {
+				self.migrateTypeName(value, path, "relationship_types")
+			} else {
+				self.migrateRelationship(value, path)
+			}
+
+		case "occurrences":
+			self.migrateOccurrences(key, value, path)
+		}
+	})
+}
+
+// TOSCA 2.0 "count" is the number of relationships to create, for which we use the lower bound of
+// "occurrences"
+func (self *Migration) migrateOccurrences(key *yaml.Node, value *yaml.Node, path ard.Path) {
+	if range_ := resolveAlias(value); (range_ != nil) && (range_.Kind == yaml.SequenceNode) && (len(range_.Content) == 2) {
+		lower := resolveAlias(range_.Content[0])
+		upper := resolveAlias(range_.Content[1])
+		if isScalar(lower) && isScalar(upper) {
+			if upper.Value != lower.Value {
+				self.reportf(upper, path.AppendList(1), "upper bound %q cannot be migrated to \"count\"", upper.Value)
+			}
+
+			key.Value = "count"
+			moveComments(range_, lower)
+			*value = *lower
+			return
+		}
+	}
+
+	self.report(value, path, "malformed \"occurrences\" cannot be migrated")
+}
+
+// For relationship templates and relationship assignments
+func (self *Migration) migrateRelationship(node *yaml.Node, path ard.Path) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "type":
+			self.migrateTypeName(value, path, "relationship_types")
+
+		case "properties":
+			self.migrateValues(value, path, nil)
+
+		case "attributes":
+			self.unpackAttributeValues(value)
+			self.migrateValues(value, path, nil)
+
+		case "interfaces":
+			self.migrateInterfaceAssignments(value, path, nil)
+		}
+	})
+}
+
+func (self *Migration) migrateGroupOrPolicy(node *yaml.Node, path ard.Path, section string) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendField(key.Value)
+
+		switch key.Value {
+		case "type":
+			self.migrateTypeName(value, path, section)
+
+		case "properties", "attributes":
+			self.migrateValues(value, path, nil)
+
+		case "interfaces":
+			self.report(key, path, "group interfaces were removed in TOSCA 2.0")
+
+		case "triggers":
+			self.report(key, path, "triggers must be migrated manually")
+		}
+	})
+}
+
+func (self *Migration) migrateInterfaceAssignments(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendMap(key.Value)
+		self.moveOperations(value, "inputs", "operations", "notifications")
+
+		forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+			path := path.AppendField(key.Value)
+
+			switch key.Value {
+			case "inputs":
+				self.migrateValues(value, path, nodeType)
+
+			case "operations", "notifications":
+				forEachPair(value, func(index int, key *yaml.Node, value *yaml.Node) {
+					self.migrateValues(getValue(value, "inputs"), path.AppendMap(key.Value).AppendField("inputs"), nodeType)
+				})
+			}
+		})
+	})
+}
+
+// Values are migrated according to their definitions
+func (self *Migration) migrateTemplateValues(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType, propertyDefinitions tosca_v2_0.PropertyDefinitions, attributeDefinitions tosca_v2_0.AttributeDefinitions) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		path := path.AppendMap(key.Value)
+		if definition, ok := attributeDefinitions[key.Value]; ok {
+			self.migrateDefinedScalars(value, path, definition)
+		} else if definition, ok := propertyDefinitions[key.Value]; ok {
+			self.migrateDefinedScalars(value, path, definition.AttributeDefinition)
+		}
+		self.migrateValue(value, path, nodeType)
+	})
+}
+
+// The long notation for attribute values (with a description) was removed in TOSCA 2.0, so the
+// description becomes a comment
+func (self *Migration) unpackAttributeValues(node *yaml.Node) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		if value = resolveAlias(value); (value == nil) || (value.Kind != yaml.MappingNode) || (len(value.Content) != 4) {
+			return
+		}
+
+		_, description, ok := getPair(value, "description")
+		if !ok || !isScalar(description) {
+			return
+		}
+
+		_, value_, ok := getPair(value, "value")
+		if !ok {
+			return
+		}
+
+		moveComments(value, value_)
+		key.LineComment = joinComments(key.LineComment, "# "+strings.Join(strings.Fields(description.Value), " "))
+		resolveAlias(node).Content[index+1] = value_
+	})
+}
+
+func (self *Migration) migrateValues(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType) {
+	forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+		self.migrateValue(value, path.AppendMap(key.Value), nodeType)
+	})
+}
+
+// Utils
+
+func hasDirective(node *yaml.Node, directive string) bool {
+	if directives := resolveAlias(getValue(node, "directives")); (directives != nil) && (directives.Kind == yaml.SequenceNode) {
+		for _, directive_ := range directives.Content {
+			if directive_ = resolveAlias(directive_); isScalar(directive_) && (directive_.Value == directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
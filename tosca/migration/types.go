@@ -0,0 +1,207 @@
+package migration
+
+import (
+	"io/fs"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/tliron/go-ard"
+	embedded "github.com/tliron/go-puccini/assets/tosca/profiles"
+	"github.com/tliron/go-puccini/tosca/grammars/tosca_v2_0"
+	"github.com/tliron/go-puccini/tosca/parsing"
+	"gopkg.in/yaml.v3"
+)
+
+// The file sections that contain types, with the entity types in the namespace
+var typeSections = map[string]reflect.Type{
+	"artifact_types":     reflect.TypeFor[*tosca_v2_0.ArtifactType](),
+	"capability_types":   reflect.TypeFor[*tosca_v2_0.CapabilityType](),
+	"data_types":         reflect.TypeFor[*tosca_v2_0.DataType](),
+	"group_types":        reflect.TypeFor[*tosca_v2_0.GroupType](),
+	"interface_types":    reflect.TypeFor[*tosca_v2_0.InterfaceType](),
+	"node_types":         reflect.TypeFor[*tosca_v2_0.NodeType](),
+	"policy_types":       reflect.TypeFor[*tosca_v2_0.PolicyType](),
+	"relationship_types": reflect.TypeFor[*tosca_v2_0.RelationshipType](),
+}
+
+var nodeTemplatePtrType = reflect.TypeFor[*tosca_v2_0.NodeTemplate]()
+
+// TOSCA 1.x normative types that are not simply renamed by removing their prefix
+var normativeTypeNames = map[string]string{
+	"null":                  "nil",
+	"scalar-unit.bitrate":   "Bitrate",
+	"scalar-unit.frequency": "Frequency",
+	"scalar-unit.size":      "Size",
+	"scalar-unit.time":      "Time",
+	"tosca.interfaces.node.lifecycle.Standard": "Lifecycle.Standard",
+	"tosca.interfaces.relationship.Configure":  "Relationship.Configure",
+}
+
+// Where the TOSCA 1.x normative types come from
+var normativeURLPrefixes = []string{
+	"internal:/profiles/implicit/1.",
+	"internal:/profiles/simple/1.",
+	"internal:/profiles/simple-for-nfv/",
+}
+
+// Migrates a type name. TOSCA 1.x normative types are renamed to their equivalents in the TOSCA 2.0
+// profile, which is then imported (see [ProfileName]). Other names are left as is.
+func (self *Migration) migrateTypeName(node *yaml.Node, path ard.Path, sections ...string) {
+	if normativeName, section, ok := self.lookupNormativeType(node, sections...); ok {
+		if name, ok := getTOSCA2TypeName(section, normativeName); ok {
+			self.setTypeName(node, name)
+		} else {
+			self.reportf(node, path, "normative %s %q has no TOSCA 2.0 equivalent", getTypeSectionKind(section), normativeName)
+		}
+	}
+}
+
+// Like migrateTypeName for a list of type names
+func (self *Migration) migrateTypeNames(node *yaml.Node, path ard.Path, sections ...string) {
+	if node = resolveAlias(node); node == nil {
+		return
+	}
+
+	if node.Kind == yaml.SequenceNode {
+		for index, item := range node.Content {
+			self.migrateTypeName(item, path.AppendList(index), sections...)
+		}
+	} else {
+		self.migrateTypeName(node, path, sections...)
+	}
+}
+
+// TOSCA 2.0 profiles do not have root types for all kinds of types, in which case the parent type
+// is removed
+func (self *Migration) migrateDerivedFrom(node *yaml.Node, path ard.Path, section string) {
+	if index, value, ok := getPair(node, "derived_from"); ok {
+		path = path.AppendField("derived_from")
+		if normativeName, _, ok := self.lookupNormativeType(value, section); ok {
+			if name, ok := getTOSCA2TypeName(section, normativeName); ok {
+				self.setTypeName(value, name)
+			} else if strings.HasSuffix(normativeName, ".Root") {
+				node.Content = append(node.Content[:index], node.Content[index+2:]...)
+			} else {
+				self.reportf(value, path, "normative %s %q has no TOSCA 2.0 equivalent", getTypeSectionKind(section), normativeName)
+			}
+		}
+	}
+}
+
+func (self *Migration) lookupNormativeType(node *yaml.Node, sections ...string) (string, string, bool) {
+	if node = resolveAlias(node); !isScalar(node) {
+		return "", "", false
+	}
+
+	for _, section := range sections {
+		if entityPtr, ok := self.ServiceFile.Context.Namespace.LookupForType(node.Value, typeSections[section]); ok {
+			if context := parsing.GetContext(entityPtr); hasNormativeURL(context.URL.String()) {
+				return context.Name, section, true
+			}
+			return "", "", false
+		}
+	}
+
+	return "", "", false
+}
+
+func (self *Migration) setTypeName(node *yaml.Node, name string) {
+	if strings.HasPrefix(name, ProfileNamespace+":") {
+		self.profileUsed = true
+	}
+	node.Value = name
+}
+
+// Utils
+
+func hasNormativeURL(url string) bool {
+	for _, prefix := range normativeURLPrefixes {
+		if strings.HasPrefix(url, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var tosca2TypeNames map[string]map[string]bool // section -> name -> implicit
+var tosca2TypeNamesOnce sync.Once
+
+// Type names are prefixed with the profile namespace unless they are implicit
+func getTOSCA2TypeName(section string, normativeName string) (string, bool) {
+	tosca2TypeNamesOnce.Do(loadTOSCA2TypeNames)
+
+	names := tosca2TypeNames[section]
+
+	name, ok := normativeTypeNames[normativeName]
+	if !ok {
+		name = normativeName
+		if prefix := "tosca." + getTypeSectionPrefix(section) + "."; strings.HasPrefix(name, prefix) {
+			name = strings.TrimPrefix(name[len(prefix):], "network.")
+		}
+	}
+
+	if implicit, ok := names[name]; ok {
+		if implicit {
+			return name, true
+		}
+		return ProfileNamespace + ":" + name, true
+	}
+
+	return "", false
+}
+
+func loadTOSCA2TypeNames() {
+	tosca2TypeNames = make(map[string]map[string]bool)
+
+	readTypeNames := func(path string, implicit bool) {
+		var file map[string]any
+		if err := yaml.Unmarshal(embedded.Get(path), &file); err != nil {
+			panic(err)
+		}
+
+		for section := range typeSections {
+			if types, ok := file[section].(map[string]any); ok {
+				names, ok := tosca2TypeNames[section]
+				if !ok {
+					names = make(map[string]bool)
+					tosca2TypeNames[section] = names
+				}
+
+				for name := range types {
+					names[name] = implicit
+				}
+			}
+		}
+	}
+
+	paths, err := fs.Glob(embedded.FS(), "simple/2.0/*.yaml")
+	if err != nil {
+		panic(err)
+	}
+	for _, path := range paths {
+		readTypeNames(path, false)
+	}
+
+	readTypeNames("implicit/2.0/data.yaml", true)
+}
+
+// e.g. "node_types" -> "node type"
+func getTypeSectionKind(section string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(section, "s"), "_", " ")
+}
+
+// The TOSCA 1.x normative type name prefixes, e.g. "tosca.nodes."
+func getTypeSectionPrefix(section string) string {
+	switch section {
+	case "capability_types":
+		return "capabilities"
+	case "data_types":
+		return "datatypes"
+	case "policy_types":
+		return "policies"
+	default:
+		// e.g. "node_types" -> "nodes"
+		return strings.TrimSuffix(section, "_types") + "s"
+	}
+}
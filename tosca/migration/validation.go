@@ -0,0 +1,213 @@
+package migration
+
+import (
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-puccini/tosca/grammars/tosca_v2_0"
+	"gopkg.in/yaml.v3"
+)
+
+// TOSCA 1.x constraint operators and their TOSCA 2.0 validation functions
+var validationFunctions = map[string]string{
+	"equal":            "$equal",
+	"greater_than":     "$greater_than",
+	"greater_or_equal": "$greater_or_equal",
+	"less_than":        "$less_than",
+	"less_or_equal":    "$less_or_equal",
+	"in_range":         "$in_range",
+	"valid_values":     "$valid_values",
+	"pattern":          "$matches",
+	"schema":           "$schema",
+}
+
+// TOSCA 1.x length constraint operators and their TOSCA 2.0 validation functions, which are applied
+// to the "$length" of the subject
+var lengthValidationFunctions = map[string]string{
+	"length":     "$equal",
+	"min_length": "$greater_or_equal",
+	"max_length": "$less_or_equal",
+}
+
+// Returns a new subject node for validation clauses
+type subjectFunc func() *yaml.Node
+
+func valueSubject() *yaml.Node {
+	return newString("$value")
+}
+
+// Converts "constraints" to "validation". The data type is that of the value, if known.
+func (self *Migration) migrateConstraints(node *yaml.Node, path ard.Path, nodeType *tosca_v2_0.NodeType, dataType *tosca_v2_0.DataType) {
+	if index, constraints, ok := getPair(node, "constraints"); ok {
+		if clause, ok := self.newValidationClause(constraints, path.AppendField("constraints"), valueSubject, nodeType, dataType); ok {
+			node = resolveAlias(node)
+			node.Content[index].Value = "validation"
+			node.Content[index+1] = clause
+		}
+	}
+}
+
+// Converts a list of TOSCA 1.x constraint clauses (or a single one) to a TOSCA 2.0 validation
+// clause. Nothing is converted if any of the constraint clauses cannot be.
+func (self *Migration) newValidationClause(constraints *yaml.Node, path ard.Path, subject subjectFunc, nodeType *tosca_v2_0.NodeType, dataType *tosca_v2_0.DataType) (*yaml.Node, bool) {
+	if constraints = resolveAlias(constraints); constraints == nil {
+		return nil, false
+	}
+
+	var clauses []*yaml.Node
+	switch constraints.Kind {
+	case yaml.SequenceNode:
+		ok := true
+		for index, constraint := range constraints.Content {
+			if clause, ok_ := self.newConstraintValidationClause(constraint, path.AppendList(index), subject, nodeType, dataType); ok_ {
+				clauses = append(clauses, clause)
+			} else {
+				ok = false
+			}
+		}
+		if !ok {
+			return nil, false
+		}
+
+	default:
+		if clause, ok := self.newConstraintValidationClause(constraints, path, subject, nodeType, dataType); ok {
+			clauses = append(clauses, clause)
+		} else {
+			return nil, false
+		}
+	}
+
+	var clause *yaml.Node
+	switch len(clauses) {
+	case 0:
+		self.report(constraints, path, "empty constraints cannot be migrated")
+		return nil, false
+	case 1:
+		clause = clauses[0]
+	default:
+		clause = newMap("$and", newList(clauses...))
+	}
+
+	moveComments(constraints, clause)
+	return clause, true
+}
+
+func (self *Migration) newConstraintValidationClause(constraint *yaml.Node, path ard.Path, subject subjectFunc, nodeType *tosca_v2_0.NodeType, dataType *tosca_v2_0.DataType) (*yaml.Node, bool) {
+	if constraint = resolveAlias(constraint); (constraint == nil) || (constraint.Kind != yaml.MappingNode) || (len(constraint.Content) != 2) {
+		self.report(constraint, path, "malformed constraint clause cannot be migrated")
+		return nil, false
+	}
+
+	operator := constraint.Content[0]
+	argument := constraint.Content[1]
+	path = path.AppendField(operator.Value)
+	self.migrateValue(argument, path, nodeType)
+
+	var clause *yaml.Node
+	if function, ok := validationFunctions[operator.Value]; ok {
+		if (operator.Value != "pattern") && (operator.Value != "schema") {
+			// Lists (for "in_range" and "valid_values") are of the same data type
+			self.migrateScalars(argument, path, dataType, dataType)
+		}
+		clause = newMap(function, newFlowList(subject(), argument))
+	} else if function, ok := lengthValidationFunctions[operator.Value]; ok {
+		clause = newMap(function, newFlowList(newFlowMap("$length", subject()), argument))
+	} else {
+		self.reportf(operator, path, "unsupported constraint operator %q cannot be migrated", operator.Value)
+		return nil, false
+	}
+
+	moveComments(constraint, clause)
+	moveComments(operator, clause)
+	moveComments(argument, clause)
+
+	// Line comments cannot be placed after flow lists, so they are moved above the clause
+	clause.HeadComment, clause.LineComment = joinComments(clause.HeadComment, clause.LineComment), ""
+	return clause, true
+}
+
+// Converts a TOSCA 1.x node filter to a TOSCA 2.0 validation clause. The entity is either "SELF" or
+// "TARGET".
+func (self *Migration) migrateNodeFilter(node *yaml.Node, key string, path ard.Path, entity string, nodeType *tosca_v2_0.NodeType) {
+	index, filter, ok := getPair(node, key)
+	if !ok {
+		return
+	}
+
+	path = path.AppendField(key)
+	if filter = resolveAlias(filter); (filter == nil) || (filter.Kind != yaml.MappingNode) {
+		return
+	}
+
+	var clauses []*yaml.Node
+	ok = true
+
+	appendPropertyFilters := func(node *yaml.Node, path ard.Path, prefix ...string) {
+		forEachSequencedPair(node, func(name *yaml.Node, constraints *yaml.Node) {
+			subject := func() *yaml.Node {
+				var arguments []*yaml.Node
+				for _, item := range append(append([]string{entity}, prefix...), name.Value) {
+					arguments = append(arguments, newString(item))
+				}
+				return newFlowMap("$get_property", newFlowList(arguments...))
+			}
+
+			path := path.AppendMap(name.Value)
+
+			// Long notation
+			if _, constraints_, ok := getPair(constraints, "constraints"); ok {
+				constraints = constraints_
+				path = path.AppendField("constraints")
+			}
+
+			if clause, ok_ := self.newValidationClause(constraints, path, subject, nodeType, nil); ok_ {
+				moveComments(name, clause)
+				clauses = append(clauses, clause)
+			} else {
+				ok = false
+			}
+		})
+	}
+
+	forEachPair(filter, func(index int, key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "properties":
+			appendPropertyFilters(value, path.AppendField("properties"))
+
+		case "capabilities":
+			path := path.AppendField("capabilities")
+			forEachSequencedPair(value, func(name *yaml.Node, capabilityFilter *yaml.Node) {
+				path := path.AppendMap(name.Value)
+				forEachPair(capabilityFilter, func(index int, key *yaml.Node, value *yaml.Node) {
+					if key.Value == "properties" {
+						appendPropertyFilters(value, path.AppendField("properties"), "CAPABILITY", name.Value)
+					} else {
+						self.reportf(key, path.AppendField(key.Value), "unsupported capability filter keyword %q cannot be migrated", key.Value)
+						ok = false
+					}
+				})
+			})
+
+		default:
+			self.reportf(key, path.AppendField(key.Value), "unsupported node filter keyword %q cannot be migrated", key.Value)
+			ok = false
+		}
+	})
+
+	if !ok {
+		return
+	}
+
+	var clause *yaml.Node
+	switch len(clauses) {
+	case 0:
+		self.report(filter, path, "empty node filter cannot be migrated")
+		return
+	case 1:
+		clause = clauses[0]
+	default:
+		clause = newMap("$and", newList(clauses...))
+	}
+
+	moveComments(filter, clause)
+	node = resolveAlias(node)
+	node.Content[index+1] = clause
+}
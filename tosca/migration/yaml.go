@@ -0,0 +1,111 @@
+package migration
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Writes the YAML node tree, including comments
+func Encode(node *yaml.Node, writer io.Writer) error {
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// Utils
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for (node != nil) && (node.Kind == yaml.AliasNode) {
+		node = node.Alias
+	}
+	return node
+}
+
+// Calls the function for each key-value pair of a mapping node with the index of the key node in
+// its content, so that the function can replace the value node
+func forEachPair(node *yaml.Node, f func(index int, key *yaml.Node, value *yaml.Node)) {
+	if node = resolveAlias(node); (node != nil) && (node.Kind == yaml.MappingNode) {
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			f(index, node.Content[index], node.Content[index+1])
+		}
+	}
+}
+
+func getPair(node *yaml.Node, key string) (int, *yaml.Node, bool) {
+	if node = resolveAlias(node); (node != nil) && (node.Kind == yaml.MappingNode) {
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			if node.Content[index].Value == key {
+				return index, node.Content[index+1], true
+			}
+		}
+	}
+	return -1, nil, false
+}
+
+// For both TOSCA "sequenced lists" (lists of single-key maps) and maps
+func forEachSequencedPair(node *yaml.Node, f func(key *yaml.Node, value *yaml.Node)) {
+	if node = resolveAlias(node); node == nil {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		forEachPair(node, func(index int, key *yaml.Node, value *yaml.Node) {
+			f(key, value)
+		})
+
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			forEachPair(item, func(index int, key *yaml.Node, value *yaml.Node) {
+				f(key, value)
+			})
+		}
+	}
+}
+
+func isScalar(node *yaml.Node) bool {
+	node = resolveAlias(node)
+	return (node != nil) && (node.Kind == yaml.ScalarNode)
+}
+
+func newString(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func newFlowList(items ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle, Content: items}
+}
+
+func newList(items ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: items}
+}
+
+func newMap(key string, value *yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{newString(key), value}}
+}
+
+func newFlowMap(key string, value *yaml.Node) *yaml.Node {
+	node := newMap(key, value)
+	node.Style = yaml.FlowStyle
+	return node
+}
+
+// Comments are appended to those already on the destination node
+func moveComments(from *yaml.Node, to *yaml.Node) {
+	to.HeadComment, from.HeadComment = joinComments(to.HeadComment, from.HeadComment), ""
+	to.LineComment, from.LineComment = joinComments(to.LineComment, from.LineComment), ""
+	to.FootComment, from.FootComment = joinComments(to.FootComment, from.FootComment), ""
+}
+
+func joinComments(a string, b string) string {
+	if a == "" {
+		return b
+	} else if b == "" {
+		return a
+	}
+	return a + "\n" + b
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/tliron/go-kutil/util"
 	"github.com/tliron/go-puccini/tosca/grammars"
 	"github.com/tliron/go-puccini/tosca/parsing"
+	"github.com/tliron/go-puccini/tosca/repositories"
 )
 
 //
@@ -20,11 +21,14 @@ import (
 type Context struct {
 	Parser *Parser
 
-	URL     exturl.URL
-	Bases   []exturl.URL
-	Quirks  parsing.Quirks
-	Inputs  map[string]ard.Value
-	Stylist *terminal.Stylist
+	URL           exturl.URL
+	Bases         []exturl.URL
+	Quirks        parsing.Quirks
+	Inputs        map[string]ard.Value
+	InputDefaults map[string]ard.Value
+	TypeMappings  map[string]string
+	Stylist       *terminal.Stylist
+	Repositories  *repositories.Repositories
 
 	Root  *File
 	Files Files
@@ -35,8 +39,9 @@ type Context struct {
 
 func (self *Parser) NewContext() *Context {
 	return &Context{
-		Parser:    self,
-		filesLock: util.NewDefaultRWLocker(),
+		Parser:       self,
+		Repositories: repositories.NewRepositories(),
+		filesLock:    util.NewDefaultRWLocker(),
 	}
 }
 
@@ -54,6 +59,13 @@ func (self *Context) MergeProblems() {
 	}
 }
 
+func (self *Context) getFiles() Files {
+	self.filesLock.RLock()
+	defer self.filesLock.RUnlock()
+
+	return append(self.Files[:0:0], self.Files...)
+}
+
 func (self *Context) AddFile(file *File) {
 	self.filesLock.Lock()
 	defer self.filesLock.Unlock()
@@ -19,7 +19,8 @@ type File struct {
 	Imports         Files
 	NameTransformer parsing.NameTransformer
 
-	importsLock util.RWLocker
+	importsLock     util.RWLocker
+	deferredImports map[string]struct{}
 }
 
 func NewEmptyFile(parsingContext *parsing.Context, container *File, nameTransformer parsing.NameTransformer) *File {
@@ -32,6 +33,7 @@ func NewFile(entityPtr parsing.EntityPtr, container *File, nameTransformer parsi
 		Container:       container,
 		NameTransformer: nameTransformer,
 		importsLock:     util.NewDefaultRWLocker(),
+		deferredImports: make(map[string]struct{}),
 	}
 
 	if container != nil {
@@ -17,6 +17,10 @@ import (
 func (self *Context) ReadRoot(context contextpkg.Context, url exturl.URL, bases []exturl.URL, serviceTemplateName string) bool {
 	parsingContext := parsing.NewContext(self.Stylist, self.Quirks)
 	parsingContext.Bases = bases
+	parsingContext.Repositories = self.Repositories
+	parsingContext.TypeMappings = self.TypeMappings
+	parsingContext.Inputs = self.Inputs
+	parsingContext.InputDefaults = self.InputDefaults
 
 	parsingContext.URL = url
 
@@ -26,6 +30,8 @@ func (self *Context) ReadRoot(context contextpkg.Context, url exturl.URL, bases
 	self.Root, ok = self.read(context, nil, parsingContext, nil, nil, "$Root", serviceTemplateName)
 	self.readWork.Wait()
 
+	self.readDeferredImports(context)
+
 	self.filesLock.Lock()
 	sort.Sort(self.Files)
 	self.filesLock.Unlock()
@@ -58,13 +64,7 @@ func (self *Context) read(context contextpkg.Context, promise util.Promise, pars
 	// Read ARD
 	var err error
 	if parsingContext.Data, parsingContext.Locator, err = parsingContext.Read(context); err != nil {
-		if decodeError, ok := err.(*yamlkeys.DecodeError); ok {
-			err = NewYAMLDecodeError(decodeError)
-		}
-		parsingContext.ReportError(err)
-		file := NewEmptyFile(parsingContext, container, nameTransformer)
-		self.AddFile(file)
-		return file, false
+		return self.readError(parsingContext, container, nameTransformer, err), false
 	}
 
 	// Detect grammar
@@ -74,6 +74,15 @@ func (self *Context) read(context contextpkg.Context, promise util.Promise, pars
 		return file, false
 	}
 
+	// Read all documents (the first document is still the file's data)
+	if parsingContext.Grammar.MultipleDocuments {
+		if parsingContext.Documents, parsingContext.DocumentsLocator, err = parsingContext.ReadDocuments(context); err != nil {
+			return self.readError(parsingContext, container, nameTransformer, err), false
+		}
+		parsingContext.Data = parsingContext.Documents[0]
+		parsingContext.Locator = parsingContext.DocumentsLocator.Locators[0]
+	}
+
 	// Read entityPtr
 	read, ok := parsingContext.Grammar.Readers[readerName]
 	if !ok {
@@ -93,9 +102,19 @@ func (self *Context) read(context contextpkg.Context, promise util.Promise, pars
 	return self.AddImportFile(context, entityPtr, container, nameTransformer), true
 }
 
+func (self *Context) readError(parsingContext *parsing.Context, container *File, nameTransformer parsing.NameTransformer, err error) *File {
+	if decodeError, ok := err.(*yamlkeys.DecodeError); ok {
+		err = NewYAMLDecodeError(decodeError)
+	}
+	parsingContext.ReportError(err)
+	file := NewEmptyFile(parsingContext, container, nameTransformer)
+	self.AddFile(file)
+	return file
+}
+
 // ([parsing.Importer] interface)
 func (self *Context) goReadImports(context contextpkg.Context, container *File) {
-	importSpecs := parsing.GetImportSpecs(container.EntityPtr)
+	importSpecs := parsing.GetImportSpecs(context, container.EntityPtr)
 
 	// Implicit import
 	if !container.GetContext().HasQuirk(parsing.QuirkImportsImplicitDisable) {
@@ -104,6 +123,51 @@ func (self *Context) goReadImports(context contextpkg.Context, container *File)
 		}
 	}
 
+	self.goReadImportSpecs(context, container, importSpecs)
+}
+
+// ([parsing.DeferredImporter] interface)
+//
+// Deferred imports may themselves import files needed by other deferred imports, so we keep
+// reading until there is no more progress.
+func (self *Context) readDeferredImports(context contextpkg.Context) {
+	final := false
+	for {
+		read := false
+		for _, file := range self.getFiles() {
+			if importer, ok := file.EntityPtr.(parsing.DeferredImporter); ok && importer.HasDeferredImports() {
+				if importSpecs := file.getDeferredImportSpecs(context, importer, final); len(importSpecs) > 0 {
+					self.goReadImportSpecs(context, file, importSpecs)
+					read = true
+				}
+			}
+		}
+
+		if read {
+			self.readWork.Wait()
+			final = false
+		} else if final {
+			return
+		} else {
+			// One last attempt to let importers report unresolved imports
+			final = true
+		}
+	}
+}
+
+// Deferred imports that have already been read are filtered out
+func (self *File) getDeferredImportSpecs(context contextpkg.Context, importer parsing.DeferredImporter, final bool) []*parsing.ImportSpec {
+	var importSpecs []*parsing.ImportSpec
+	for key, importSpec := range importer.GetDeferredImportSpecs(context, self.newImportedNamespace(), final) {
+		if _, ok := self.deferredImports[key]; !ok {
+			self.deferredImports[key] = struct{}{}
+			importSpecs = append(importSpecs, importSpec)
+		}
+	}
+	return importSpecs
+}
+
+func (self *Context) goReadImportSpecs(context contextpkg.Context, container *File, importSpecs []*parsing.ImportSpec) {
 	for _, importSpec := range importSpecs {
 		key := importSpec.URL.Key()
 
@@ -29,6 +29,23 @@ func (self *File) mergeNamespaces() {
 	context.Namespace.Merge(namespace, nil)
 }
 
+// Like the namespace created by mergeNamespaces, but only for the imported files (and without
+// modifying their namespaces), so that it can be used before the namespaces phase
+func (self *File) newImportedNamespace() *parsing.Namespace {
+	namespace := parsing.NewNamespace()
+
+	self.importsLock.RLock()
+	defer self.importsLock.RUnlock()
+
+	for _, import_ := range self.Imports {
+		importNamespace := import_.newImportedNamespace()
+		importNamespace.Merge(parsing.NewNamespaceFor(import_.EntityPtr), nil)
+		namespace.Merge(importNamespace, import_.NameTransformer)
+	}
+
+	return namespace
+}
+
 // Print
 
 func (self *Context) PrintNamespaces(indent int) {
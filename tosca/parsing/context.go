@@ -12,6 +12,7 @@ import (
 	"github.com/tliron/go-kutil/problems"
 	"github.com/tliron/go-kutil/terminal"
 	"github.com/tliron/go-kutil/util"
+	"github.com/tliron/go-puccini/tosca/repositories"
 	"github.com/tliron/yamlkeys"
 )
 
@@ -59,6 +60,7 @@ type Context struct {
 	Path               ard.Path
 	URL                exturl.URL
 	RepositoryURL      exturl.URL
+	Repositories       *repositories.Repositories
 	Bases              []exturl.URL
 	Data               ard.Value
 	Locator            ard.Locator
@@ -70,7 +72,15 @@ type Context struct {
 	Quirks             Quirks
 	Grammar            *Grammar
 	FunctionPrefix     string
+	TypeMappings       map[string]string
+	Inputs             map[string]ard.Value // also set later via [HasInputs]
+	InputDefaults      map[string]ard.Value
 	ReadTagOverrides   map[string]string
+
+	// All the documents in the file, including the first one (which is also Data)
+	// (only set for the file's root context)
+	Documents        ard.List
+	DocumentsLocator *DocumentsLocator
 }
 
 func NewContext(stylist *terminal.Stylist, quirks Quirks) *Context {
@@ -93,6 +103,7 @@ func (self *Context) NewImportContext(url exturl.URL) *Context {
 		Path:               self.Path,
 		URL:                url,
 		RepositoryURL:      self.RepositoryURL,
+		Repositories:       self.Repositories,
 		Bases:              self.Bases,
 		CanonicalNamespace: self.CanonicalNamespace,
 		Namespace:          NewNamespace(),
@@ -102,6 +113,9 @@ func (self *Context) NewImportContext(url exturl.URL) *Context {
 		Quirks:             self.Quirks,
 		Grammar:            self.Grammar,
 		FunctionPrefix:     self.FunctionPrefix,
+		TypeMappings:       self.TypeMappings,
+		Inputs:             self.Inputs,
+		InputDefaults:      self.InputDefaults,
 	}
 }
 
@@ -181,6 +195,7 @@ func (self *Context) Clone(data ard.Value) *Context {
 		Path:               self.Path,
 		URL:                self.URL,
 		RepositoryURL:      self.RepositoryURL,
+		Repositories:       self.Repositories,
 		Bases:              self.Bases,
 		Data:               data,
 		Locator:            self.Locator,
@@ -192,6 +207,9 @@ func (self *Context) Clone(data ard.Value) *Context {
 		Quirks:             self.Quirks,
 		Grammar:            self.Grammar,
 		FunctionPrefix:     self.FunctionPrefix,
+		TypeMappings:       self.TypeMappings,
+		Inputs:             self.Inputs,
+		InputDefaults:      self.InputDefaults,
 	}
 }
 
@@ -203,6 +221,7 @@ func (self *Context) FieldChild(name ard.Value, data ard.Value) *Context {
 		Path:               self.Path.AppendField(nameString),
 		URL:                self.URL,
 		RepositoryURL:      self.RepositoryURL,
+		Repositories:       self.Repositories,
 		Bases:              self.Bases,
 		Data:               data,
 		Locator:            self.Locator,
@@ -214,6 +233,9 @@ func (self *Context) FieldChild(name ard.Value, data ard.Value) *Context {
 		Quirks:             self.Quirks,
 		Grammar:            self.Grammar,
 		FunctionPrefix:     self.FunctionPrefix,
+		TypeMappings:       self.TypeMappings,
+		Inputs:             self.Inputs,
+		InputDefaults:      self.InputDefaults,
 	}
 }
 
@@ -251,6 +273,7 @@ func (self *Context) MapChild(name ard.Value, data ard.Value) *Context {
 		Path:               self.Path.AppendMap(nameString),
 		URL:                self.URL,
 		RepositoryURL:      self.RepositoryURL,
+		Repositories:       self.Repositories,
 		Bases:              self.Bases,
 		Data:               data,
 		Locator:            self.Locator,
@@ -262,6 +285,9 @@ func (self *Context) MapChild(name ard.Value, data ard.Value) *Context {
 		Quirks:             self.Quirks,
 		Grammar:            self.Grammar,
 		FunctionPrefix:     self.FunctionPrefix,
+		TypeMappings:       self.TypeMappings,
+		Inputs:             self.Inputs,
+		InputDefaults:      self.InputDefaults,
 	}
 }
 
@@ -272,6 +298,7 @@ func (self *Context) ListChild(index int, data ard.Value) *Context {
 		Path:               self.Path.AppendList(index),
 		URL:                self.URL,
 		RepositoryURL:      self.RepositoryURL,
+		Repositories:       self.Repositories,
 		Bases:              self.Bases,
 		Data:               data,
 		Locator:            self.Locator,
@@ -283,6 +310,9 @@ func (self *Context) ListChild(index int, data ard.Value) *Context {
 		Quirks:             self.Quirks,
 		Grammar:            self.Grammar,
 		FunctionPrefix:     self.FunctionPrefix,
+		TypeMappings:       self.TypeMappings,
+		Inputs:             self.Inputs,
+		InputDefaults:      self.InputDefaults,
 	}
 }
 
@@ -293,6 +323,7 @@ func (self *Context) SequencedListChild(index int, name string, data ard.Value)
 		Path:               self.Path.AppendSequencedList(index),
 		URL:                self.URL,
 		RepositoryURL:      self.RepositoryURL,
+		Repositories:       self.Repositories,
 		Bases:              self.Bases,
 		Data:               data,
 		Locator:            self.Locator,
@@ -304,5 +335,8 @@ func (self *Context) SequencedListChild(index int, name string, data ard.Value)
 		Quirks:             self.Quirks,
 		Grammar:            self.Grammar,
 		FunctionPrefix:     self.FunctionPrefix,
+		TypeMappings:       self.TypeMappings,
+		Inputs:             self.Inputs,
+		InputDefaults:      self.InputDefaults,
 	}
 }
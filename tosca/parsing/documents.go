@@ -0,0 +1,72 @@
+package parsing
+
+import (
+	contextpkg "context"
+	"io"
+
+	"github.com/tliron/commonlog"
+	"github.com/tliron/go-ard"
+	"github.com/tliron/go-kutil/util"
+	"github.com/tliron/yamlkeys"
+	"gopkg.in/yaml.v3"
+)
+
+// Reads all the YAML documents in the file (a file usually has just one).
+//
+// Most grammars only use the first document (see [Context.Read]), but some (e.g. Kubernetes
+// manifests) expect more than one.
+func (self *Context) ReadDocuments(context contextpkg.Context) (ard.List, *DocumentsLocator, error) {
+	if reader, err := self.URL.Open(context); err == nil {
+		reader = util.NewContextualReadCloser(context, reader)
+		defer commonlog.CallAndLogWarning(reader.Close, "Context.ReadDocuments", log)
+
+		return ReadDocuments(reader)
+	} else {
+		return nil, nil, err
+	}
+}
+
+func ReadDocuments(reader io.Reader) (ard.List, *DocumentsLocator, error) {
+	var documents ard.List
+	var locator DocumentsLocator
+
+	decoder := yaml.NewDecoder(reader)
+	for {
+		var node yaml.Node
+		if err := decoder.Decode(&node); err == nil {
+			if document, err := yamlkeys.DecodeNode(&node); err == nil {
+				documents = append(documents, document)
+				locator.Locators = append(locator.Locators, ard.NewYAMLLocator(&node))
+			} else {
+				return nil, nil, err
+			}
+		} else if (err == io.EOF) && (len(documents) > 0) {
+			return documents, &locator, nil
+		} else {
+			// Note that an empty file is an error
+			return nil, nil, yamlkeys.WrapWithDecodeError(err)
+		}
+	}
+}
+
+//
+// DocumentsLocator
+//
+
+// Locates paths in a list of documents, in which the first path element
+// is the document index
+type DocumentsLocator struct {
+	Locators []ard.Locator
+}
+
+// ([ard.Locator] interface)
+func (self *DocumentsLocator) Locate(path ...ard.PathElement) (int, int, bool) {
+	if len(path) > 0 {
+		if element := path[0]; element.Type == ard.ListPathType {
+			if index, ok := element.Value.(int); ok && (index >= 0) && (index < len(self.Locators)) {
+				return self.Locators[index].Locate(path[1:]...)
+			}
+		}
+	}
+	return -1, -1, false
+}
@@ -1,5 +1,9 @@
 package parsing
 
+import (
+	"io/fs"
+)
+
 //
 // GrammarVersion
 //
@@ -16,6 +20,14 @@ func (self GrammarVersions) Add(keyword string, version string, implicitProfileP
 	self[keyword] = append(self[keyword], grammarVersion)
 }
 
+//
+// GrammarDetector
+//
+
+// Returns true if the file (the root context) is written in the grammar. Used for formats that
+// cannot be detected by a version keyword.
+type GrammarDetector func(context *Context) bool
+
 //
 // Grammar
 //
@@ -23,6 +35,9 @@ func (self GrammarVersions) Add(keyword string, version string, implicitProfileP
 type Grammar struct {
 	Versions                   GrammarVersions
 	Readers                    Readers
+	Detector                   GrammarDetector
+	MultipleDocuments          bool
+	Profiles                   fs.FS // paths are relative to "internal:/profiles/"
 	InvalidNamespaceCharacters string
 }
 
@@ -40,3 +55,21 @@ func (self *Grammar) RegisterVersion(keyword string, version string, implicitPro
 func (self *Grammar) RegisterReader(name string, reader Reader) {
 	self.Readers[name] = reader
 }
+
+// Detectors are tried in order of grammar registration, and only if no grammar was detected by
+// version keyword.
+func (self *Grammar) RegisterDetector(detector GrammarDetector) {
+	self.Detector = detector
+}
+
+// By default only the first YAML document in the file is read. This is for grammars that expect
+// more than one (see [Context.Documents]).
+func (self *Grammar) RegisterMultipleDocuments() {
+	self.MultipleDocuments = true
+}
+
+// The files will be available as internal URLs under "internal:/profiles/" (e.g. for use as
+// implicit profile paths) and indexed by the profile registry.
+func (self *Grammar) RegisterProfiles(profiles fs.FS) {
+	self.Profiles = profiles
+}
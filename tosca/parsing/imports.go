@@ -1,24 +1,44 @@
 package parsing
 
-import "github.com/tliron/exturl"
+import (
+	contextpkg "context"
+
+	"github.com/tliron/exturl"
+)
 
 //
 // Importer
 //
 
 type Importer interface {
-	GetImportSpecs() []*ImportSpec
+	GetImportSpecs(context contextpkg.Context) []*ImportSpec
 }
 
 // From [Importer] interface
-func GetImportSpecs(entityPtr EntityPtr) []*ImportSpec {
+func GetImportSpecs(context contextpkg.Context, entityPtr EntityPtr) []*ImportSpec {
 	if importer, ok := entityPtr.(Importer); ok {
-		return importer.GetImportSpecs()
+		return importer.GetImportSpecs(context)
 	} else {
 		return nil
 	}
 }
 
+//
+// DeferredImporter
+//
+
+// For imports that can only be resolved after the other imports have been read, e.g. imports from
+// repositories declared in imported files.
+type DeferredImporter interface {
+	HasDeferredImports() bool
+
+	// The namespace contains the entities of all imported files read so far. Deferred imports that
+	// cannot be resolved should be kept for another attempt, unless final is true, in which case
+	// they should be reported. The returned map keys are unique per import so that each will be
+	// read only once.
+	GetDeferredImportSpecs(context contextpkg.Context, namespace *Namespace, final bool) map[string]*ImportSpec
+}
+
 //
 // ImportSpec
 //
@@ -75,15 +75,48 @@ const (
 	// and 2.0 to support the TOSCA 1.2 grammar.
 	QuirkInterfacesOperationsPermissive Quirk = "interfaces.operations.permissive"
 
+	// In HOT, an `OS::Heat::ResourceGroup` is normally compiled as a single node template. This
+	// quirk will additionally compile a node template for each member of the group (according to
+	// its `count` and `resource_def`), named with the group name and the member index.
+	QuirkResourceGroupsExpand Quirk = "resource_groups.expand"
+
+	// Docker Compose files have no version keyword, so they cannot be detected like the other
+	// grammars. This quirk will detect files that have a `services` section (and none of the other
+	// grammars' version keywords) as Compose files.
+	QuirkGrammarsCompose Quirk = "grammars.compose"
+
+	// ETSI NFV descriptors import the ETSI GS NFV-SOL 001 type definitions from the ETSI forge
+	// (e.g. "https://forge.etsi.org/rep/nfv/SOL001/raw/v4.4.1/etsi_nfv_sol001_vnfd_types.yaml").
+	// This quirk will instead import Puccini's embedded copies of files with those names and
+	// versions, so that no network access is required. Versions that are not embedded are imported
+	// as usual (currently only v4.4.1 is embedded).
+	QuirkImportsETSINFVSOL001 Quirk = "imports.etsinfv.sol001"
+
+	// In TOSCA 1.2 and 1.3 a property mapping may be a constant value (deprecated, but used
+	// throughout ETSI NFV descriptors, e.g. for "flavour_id"), whereas by default Puccini treats a
+	// plain string as the name of an input. This quirk will treat plain strings as constant values.
+	QuirkSubstitutionMappingsPropertiesValues Quirk = "substitution_mappings.properties.values"
+
+	// Enables validation of the ETSI GS NFV-SOL 001 rules that TOSCA cannot express: the targets
+	// of VDU, connection point, and virtual link requirements, the requirement mappings of VNF,
+	// NS, and PNF substitutions, and the deployment flavour ("flavour_id") of VNF and NS
+	// substitutions.
+	QuirkValidationETSINFVSOL001 Quirk = "validation.etsinfv.sol001"
+
 	// Combines "imports.topology_template.ignore", "data_types.string.permissive",
 	// "capabilities.occurrences.permissive", "substitution_mappings.requirements.permissive",
 	// "substitution_mappings.requirements.list"
 	QuirkETSINFV Quirk = "etsinfv"
 
+	// Combines the "etsinfv" quirks with "imports.etsinfv.sol001",
+	// "substitution_mappings.properties.values", "validation.etsinfv.sol001"
+	QuirkETSINFVSOL001 Quirk = "etsinfv.sol001"
+
 	// Combines "annotations.ignore", "imports.sequencedlist", "imports.version.permissive"
 	QuirkONAP Quirk = "onap"
 )
 
+// Combinations may include other combinations
 var combinationQuirks = map[Quirk][]Quirk{
 	QuirkETSINFV: {
 		QuirkImportsTopologyTemplateIgnore,
@@ -92,6 +125,12 @@ var combinationQuirks = map[Quirk][]Quirk{
 		QuirkSubstitutionMappingsRequirementsPermissive,
 		QuirkSubstitutionMappingsRequirementsList,
 	},
+	QuirkETSINFVSOL001: {
+		QuirkETSINFV,
+		QuirkImportsETSINFVSOL001,
+		QuirkSubstitutionMappingsPropertiesValues,
+		QuirkValidationETSINFVSOL001,
+	},
 	QuirkONAP: {
 		QuirkAnnotationsIgnore,
 		QuirkImportsSequencedList,
@@ -108,12 +147,7 @@ type Quirks []Quirk
 func NewQuirks(quirks ...string) Quirks {
 	var self Quirks
 	for _, quirk := range quirks {
-		quirk_ := Quirk(quirk)
-		if quirks_, ok := combinationQuirks[quirk_]; ok {
-			self = append(self, quirks_...)
-		} else {
-			self = append(self, quirk_)
-		}
+		self = self.append(Quirk(quirk))
 	}
 	return self
 }
@@ -127,6 +161,17 @@ func (self Quirks) Has(quirk Quirk) bool {
 	return false
 }
 
+// Expands combinations
+func (self Quirks) append(quirk Quirk) Quirks {
+	if quirks, ok := combinationQuirks[quirk]; ok {
+		for _, quirk_ := range quirks {
+			self = self.append(quirk_)
+		}
+		return self
+	}
+	return append(self, quirk)
+}
+
 // fmt.Stringify interface
 func (self Quirks) String() string {
 	if len(self) > 0 {
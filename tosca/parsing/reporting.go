@@ -3,6 +3,7 @@ package parsing
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/tliron/exturl"
@@ -227,6 +228,10 @@ func (self *Context) ReportIncompatibleExtension(extension string, requiredExten
 	return self.ReportPathf(1, "extension %s is not %s", self.Problems.Stylist.Value(quote(extension)), terminal.StylizedOptions(requiredExtensions, self.Problems.Stylist.Value))
 }
 
+func (self *Context) ReportTypeAmbiguous(kind string, types ...EntityPtr) bool {
+	return self.ReportPathf(1, "ambiguous %s type, can be %s", kind, terminal.StylizedOptions(canonicalNamesOfEntityPtrs(types), self.Problems.Stylist.TypeName))
+}
+
 func (self *Context) ReportNotInRange(name string, value uint64, lower uint64, upper uint64) bool {
 	return self.ReportPathf(1, "%s is %d, must be >= %d and <= %d", name, value, lower, upper)
 }
@@ -269,6 +274,15 @@ func urlsOfEntityPtrs(entityPtrs []EntityPtr) []string {
 	return urls
 }
 
+func canonicalNamesOfEntityPtrs(entityPtrs []EntityPtr) []string {
+	names := make([]string, len(entityPtrs))
+	for index, entityPtr := range entityPtrs {
+		names[index] = GetCanonicalName(entityPtr)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func entityTypeName(type_ reflect.Type) string {
 	fields := type_.NumField()
 	for index := 0; index < fields; index++ {
@@ -0,0 +1,449 @@
+package profiles
+
+import (
+	contextpkg "context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tliron/exturl"
+	"github.com/tliron/go-ard"
+	embedded "github.com/tliron/go-puccini/assets/tosca/profiles"
+	"github.com/tliron/go-puccini/tosca/csar"
+)
+
+const internalPrefix = "internal:/profiles/"
+
+// The default registry used by the TOSCA parser
+var DefaultRegistry = NewRegistry()
+
+//
+// Profile
+//
+
+type Profile struct {
+	Name     string `json:"name" yaml:"name"`
+	Version  string `json:"version" yaml:"version"`
+	Location string `json:"location" yaml:"location"` // URL or file path
+
+	imports []string
+}
+
+func (self *Profile) String() string {
+	if self.Version != "" {
+		return self.Name + ":" + self.Version
+	} else {
+		return self.Name
+	}
+}
+
+//
+// Profiles
+//
+
+type Profiles []*Profile
+
+// ([sort.Interface])
+func (self Profiles) Len() int {
+	return len(self)
+}
+
+// ([sort.Interface])
+func (self Profiles) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+// ([sort.Interface])
+func (self Profiles) Less(i, j int) bool {
+	if self[i].Name != self[j].Name {
+		return self[i].Name < self[j].Name
+	}
+	if self[i].Version != self[j].Version {
+		return compareVersions(self[i].Version, self[j].Version) < 0
+	}
+	return self[i].Location < self[j].Location
+}
+
+//
+// Registry
+//
+
+// Indexes TOSCA 2.0 files by their declared "profile" name and version (and TOSCA 1.x files by their
+// declared "namespace" and "template_version" metadata).
+//
+// The embedded profiles are always indexed. Additional embedded filesystems, directories, CSARs, and
+// individual files can be added. Files imported by another file declaring the same profile are
+// considered to be parts of that profile and are not indexed on their own.
+type Registry struct {
+	searchPath  []string
+	filesystems []fs.FS
+	profiles    map[string]Profiles // key is name:version
+	indexed     bool
+	lock        sync.Mutex
+}
+
+func NewRegistry() *Registry {
+	return new(Registry)
+}
+
+func (self *Registry) AddSearchPath(paths ...string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.searchPath = append(self.searchPath, paths...)
+	self.indexed = false
+}
+
+// Adds filesystems that are embedded like our own profiles, meaning that their paths are relative
+// to "internal:/profiles/" (see grammars.Register).
+func (self *Registry) AddFS(filesystems ...fs.FS) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.filesystems = append(self.filesystems, filesystems...)
+	self.indexed = false
+}
+
+// Returns all indexed profiles, including conflicting ones, sorted by name and version.
+func (self *Registry) List(context contextpkg.Context) (Profiles, error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if err := self.index(context); err != nil {
+		return nil, err
+	}
+
+	var profiles Profiles
+	for _, profiles_ := range self.profiles {
+		profiles = append(profiles, profiles_...)
+	}
+	sort.Sort(profiles)
+	return profiles, nil
+}
+
+// Returns groups of profiles that declare the same name and version.
+func (self *Registry) Conflicts(context contextpkg.Context) ([]Profiles, error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if err := self.index(context); err != nil {
+		return nil, err
+	}
+
+	var conflicts []Profiles
+	for _, profiles := range self.profiles {
+		if len(profiles) > 1 {
+			sort.Sort(profiles)
+			conflicts = append(conflicts, profiles)
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i][0].String() < conflicts[j][0].String()
+	})
+	return conflicts, nil
+}
+
+// The name may include the version after a ":", in which case the version argument should be empty.
+// If no version is specified the highest available version is returned.
+func (self *Registry) Get(context contextpkg.Context, name string, version string) (*Profile, error) {
+	if version == "" {
+		if colon := strings.LastIndex(name, ":"); colon != -1 {
+			name, version = name[:colon], name[colon+1:]
+		}
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if err := self.index(context); err != nil {
+		return nil, err
+	}
+
+	var found Profiles
+	if version != "" {
+		found = self.profiles[name+":"+version]
+	} else {
+		// Highest version
+		for _, profiles := range self.profiles {
+			if (profiles[0].Name == name) && ((len(found) == 0) || (compareVersions(profiles[0].Version, found[0].Version) > 0)) {
+				found = profiles
+			}
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		if version != "" {
+			return nil, fmt.Errorf("unknown profile: %q version %q", name, version)
+		} else {
+			return nil, fmt.Errorf("unknown profile: %q", name)
+		}
+
+	case 1:
+		return found[0], nil
+
+	default:
+		locations := make([]string, len(found))
+		for index, profile := range found {
+			locations[index] = profile.Location
+		}
+		return nil, fmt.Errorf("profile %q is declared in more than one location: %s", found[0].String(), strings.Join(locations, ", "))
+	}
+}
+
+func (self *Registry) index(context contextpkg.Context) error {
+	if self.indexed {
+		return nil
+	}
+
+	var profiles Profiles
+
+	// Embedded
+	for _, filesystem := range append([]fs.FS{embedded.FS()}, self.filesystems...) {
+		if profiles_, err := indexFS(filesystem); err == nil {
+			profiles = append(profiles, profiles_...)
+		} else {
+			return err
+		}
+	}
+
+	// Search path
+	for _, path := range self.searchPath {
+		if profiles_, err := indexPath(context, path); err == nil {
+			profiles = append(profiles, profiles_...)
+		} else {
+			return err
+		}
+	}
+
+	// Files imported by other files of the same profile are parts of that profile, as are all files
+	// imported by a profile's root "profile.yaml" (whatever profile they declare)
+	imported := make(map[string]struct{})
+	for _, profile := range profiles {
+		isRoot := path.Base(filepath.ToSlash(profile.Location)) == "profile.yaml"
+		for _, import_ := range profile.imports {
+			imported[profile.String()+"|"+import_] = struct{}{}
+			if isRoot {
+				imported["|"+import_] = struct{}{}
+			}
+		}
+	}
+
+	self.profiles = make(map[string]Profiles)
+	for _, profile := range profiles {
+		if !isImportedProfile(imported, profile) {
+			key := profile.Name + ":" + profile.Version
+			self.profiles[key] = append(self.profiles[key], profile)
+		}
+	}
+
+	self.indexed = true
+	return nil
+}
+
+// Utils
+
+func isImportedProfile(imported map[string]struct{}, profile *Profile) bool {
+	if _, ok := imported[profile.String()+"|"+profile.Location]; ok {
+		return true
+	}
+	_, ok := imported["|"+profile.Location]
+	return ok
+}
+
+func indexFS(filesystem fs.FS) (Profiles, error) {
+	var profiles Profiles
+
+	if err := fs.WalkDir(filesystem, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && isYAML(path) {
+			if file, err := filesystem.Open(path); err == nil {
+				defer file.Close()
+				if profile, ok := readProfile(file, internalPrefix+path); ok {
+					profiles = append(profiles, profile)
+				}
+			} else {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+func indexPath(context contextpkg.Context, path string) (Profiles, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles Profiles
+
+	if info.IsDir() {
+		if err := filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && isYAML(path) {
+				if profile, ok := readProfileFile(path); ok {
+					profiles = append(profiles, profile)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	} else if format := exturl.GetFormat(path); csar.IsValidFormat(format) {
+		urlContext := exturl.NewContext()
+		defer urlContext.Release()
+
+		url, _, err := csar.GetDefaultServiceTemplateURL(context, urlContext.NewFileURL(path), format)
+		if err != nil {
+			return nil, err
+		}
+
+		reader, err := url.Open(context)
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		if profile, ok := readProfile(reader, url.String()); ok {
+			profiles = append(profiles, profile)
+		}
+	} else if profile, ok := readProfileFile(path); ok {
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+func readProfileFile(path string) (*Profile, bool) {
+	if file, err := os.Open(path); err == nil {
+		defer file.Close()
+		return readProfile(file, path)
+	} else {
+		return nil, false
+	}
+}
+
+// Files that cannot be read or are not profiles (TOSCA 2.0 files with "profile" or TOSCA 1.x files
+// with "namespace") are silently skipped
+func readProfile(reader io.Reader, location string) (*Profile, bool) {
+	data, _, err := ard.Read(reader, "yaml", false)
+	if err != nil {
+		return nil, false
+	}
+
+	node := ard.With(data).ConvertSimilar()
+	version, _ := node.Get("tosca_definitions_version").String()
+
+	var name string
+	switch {
+	case version == "tosca_2_0":
+		name, _ = node.Get("profile").String()
+	case strings.HasPrefix(version, "tosca_simple_yaml_1_"):
+		// In TOSCA 1.x the "profile" keyname is called "namespace"
+		name, _ = node.Get("namespace").String()
+	}
+	if name == "" {
+		return nil, false
+	}
+
+	profile := Profile{
+		Name:     name,
+		Location: location,
+	}
+
+	// TOSCA 1.x namespaces are often URIs, so they cannot contain the version
+	if colon := strings.LastIndex(name, ":"); (colon != -1) && (version == "tosca_2_0") {
+		profile.Name, profile.Version = name[:colon], name[colon+1:]
+	} else if version, ok := node.Get("metadata", "template_version").String(); ok {
+		profile.Version = version
+	}
+
+	if imports, ok := node.Get("imports").List(); ok {
+		for _, import_ := range imports {
+			url, ok := import_.(string)
+			if !ok {
+				url, _ = ard.With(import_).Get("url").String()
+			}
+			if url != "" {
+				profile.imports = append(profile.imports, relativeLocation(location, url))
+			}
+		}
+	}
+
+	return &profile, true
+}
+
+func relativeLocation(location string, url string) string {
+	if strings.Contains(url, ":") {
+		// Absolute URL
+		return url
+	}
+
+	if strings.HasPrefix(location, "internal:") {
+		return "internal:" + path.Join(path.Dir(location[9:]), url)
+	} else if strings.Contains(location, ":") {
+		// Other URLs (e.g. within CSARs)
+		return path.Join(path.Dir(location), url)
+	} else {
+		return filepath.Join(filepath.Dir(location), filepath.FromSlash(url))
+	}
+}
+
+func isYAML(path string) bool {
+	switch exturl.GetFormat(path) {
+	case "yaml":
+		return true
+	default:
+		return false
+	}
+}
+
+// Compares dot-separated versions, numerically where possible
+func compareVersions(a string, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for index := 0; (index < len(aParts)) || (index < len(bParts)); index++ {
+		var aPart, bPart string
+		if index < len(aParts) {
+			aPart = aParts[index]
+		}
+		if index < len(bParts) {
+			bPart = bParts[index]
+		}
+
+		aNumber, aErr := strconv.ParseUint(aPart, 10, 64)
+		bNumber, bErr := strconv.ParseUint(bPart, 10, 64)
+		if (aErr == nil) && (bErr == nil) {
+			if aNumber != bNumber {
+				if aNumber < bNumber {
+					return -1
+				}
+				return 1
+			}
+		} else if aPart != bPart {
+			return strings.Compare(aPart, bPart)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,176 @@
+package profiles
+
+import (
+	contextpkg "context"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRegistryGet(t *testing.T) {
+	dir := t.TempDir()
+	writeTestProfile(t, dir, "v1.yaml", "tosca_definitions_version: tosca_2_0\nprofile: test.org/example:1.0\n")
+	writeTestProfile(t, dir, "v10.yaml", "tosca_definitions_version: tosca_2_0\nprofile: test.org/example:1.10\n")
+	writeTestProfile(t, dir, "v2.yaml", "tosca_definitions_version: tosca_2_0\nprofile: test.org/example:1.2\n")
+	writeTestProfile(t, dir, "other.yaml", "tosca_definitions_version: tosca_2_0\n")
+
+	registry := NewRegistry()
+	registry.AddSearchPath(dir)
+	context := contextpkg.Background()
+
+	// Highest version (numerically)
+	assertTestProfile(t, registry, "test.org/example", "", filepath.Join(dir, "v10.yaml"))
+
+	// Specific version
+	assertTestProfile(t, registry, "test.org/example", "1.2", filepath.Join(dir, "v2.yaml"))
+	assertTestProfile(t, registry, "test.org/example:1.0", "", filepath.Join(dir, "v1.yaml"))
+
+	if _, err := registry.Get(context, "test.org/example", "3.0"); err == nil {
+		t.Errorf("found unknown version")
+	}
+	if _, err := registry.Get(context, "test.org/missing", ""); err == nil {
+		t.Errorf("found unknown profile")
+	}
+}
+
+func TestRegistryTOSCA1(t *testing.T) {
+	dir := t.TempDir()
+	writeTestProfile(t, dir, "profile.yaml", "tosca_definitions_version: tosca_simple_yaml_1_3\nnamespace: http://test.org/example\nmetadata:\n  template_version: \"1.1\"\n")
+
+	registry := NewRegistry()
+	registry.AddSearchPath(dir)
+
+	// The namespace is a URI, so the colon is not a version separator
+	assertTestProfile(t, registry, "http://test.org/example", "1.1", filepath.Join(dir, "profile.yaml"))
+}
+
+func TestRegistryParts(t *testing.T) {
+	dir := t.TempDir()
+	writeTestProfile(t, dir, "profile.yaml", "tosca_definitions_version: tosca_2_0\nprofile: test.org/example:1.0\nimports:\n- parts/part.yaml\n- url: parts/other.yaml\n")
+	writeTestProfile(t, dir, "parts/part.yaml", "tosca_definitions_version: tosca_2_0\nprofile: test.org/example:1.0\n")
+	writeTestProfile(t, dir, "parts/other.yaml", "tosca_definitions_version: tosca_2_0\nprofile: test.org/example:1.0\n")
+
+	registry := NewRegistry()
+	registry.AddSearchPath(dir)
+
+	// Parts are not conflicts
+	assertTestProfile(t, registry, "test.org/example", "1.0", filepath.Join(dir, "profile.yaml"))
+	assertTestConflicts(t, registry, 0)
+}
+
+func TestRegistryPartsOfRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeTestProfile(t, dir, "profile.yaml", "tosca_definitions_version: tosca_2_0\nprofile: test.org/example:1.0\nimports:\n- part.yaml\n")
+	writeTestProfile(t, dir, "part.yaml", "tosca_definitions_version: tosca_2_0\nprofile: test.org/other:1.0\n")
+
+	registry := NewRegistry()
+	registry.AddSearchPath(dir)
+
+	// Files imported by a root "profile.yaml" are parts even if they declare another profile
+	assertTestProfile(t, registry, "test.org/example", "1.0", filepath.Join(dir, "profile.yaml"))
+	if _, err := registry.Get(contextpkg.Background(), "test.org/other", ""); err == nil {
+		t.Errorf("part listed as profile")
+	}
+}
+
+func TestRegistryEmbedded(t *testing.T) {
+	profiles, err := NewRegistry().List(contextpkg.Background())
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	// Embedded profiles are listed only by their root files
+	for _, profile := range profiles {
+		if path.Base(profile.Location) != "profile.yaml" {
+			t.Errorf("not a profile root: %s %s", profile.String(), profile.Location)
+		}
+	}
+}
+
+func TestRegistryConflicts(t *testing.T) {
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	writeTestProfile(t, dir1, "profile.yaml", "tosca_definitions_version: tosca_2_0\nprofile: test.org/example:1.0\n")
+	writeTestProfile(t, dir2, "profile.yaml", "tosca_definitions_version: tosca_2_0\nprofile: test.org/example:1.0\n")
+
+	registry := NewRegistry()
+	registry.AddSearchPath(dir1)
+	assertTestConflicts(t, registry, 0)
+
+	// Adding to the search path re-indexes
+	registry.AddSearchPath(dir2)
+	conflicts := assertTestConflicts(t, registry, 1)
+	if (len(conflicts) == 1) && (len(conflicts[0]) != 2) {
+		t.Errorf("expected 2 conflicting locations, got %d", len(conflicts[0]))
+	}
+
+	if _, err := registry.Get(contextpkg.Background(), "test.org/example", "1.0"); err == nil {
+		t.Errorf("got conflicting profile")
+	}
+}
+
+func TestRegistryFS(t *testing.T) {
+	registry := NewRegistry()
+	registry.AddFS(fstest.MapFS{
+		"test/1.0/profile.yaml": &fstest.MapFile{Data: []byte("tosca_definitions_version: tosca_2_0\nprofile: test.org/example:1.0\n")},
+	})
+
+	assertTestProfile(t, registry, "test.org/example", "", internalPrefix+"test/1.0/profile.yaml")
+
+	// Embedded profiles are always indexed
+	if profiles, err := registry.List(contextpkg.Background()); err == nil {
+		if len(profiles) < 2 {
+			t.Errorf("embedded profiles not indexed")
+		}
+	} else {
+		t.Fatalf("%s", err.Error())
+	}
+}
+
+func TestRegistrySearchPathError(t *testing.T) {
+	registry := NewRegistry()
+	registry.AddSearchPath(filepath.Join(t.TempDir(), "missing"))
+
+	if _, err := registry.List(contextpkg.Background()); err == nil {
+		t.Errorf("missing search path not reported")
+	}
+}
+
+// Utils
+
+func assertTestProfile(t *testing.T, registry *Registry, name string, version string, location string) {
+	t.Helper()
+
+	if profile, err := registry.Get(contextpkg.Background(), name, version); err == nil {
+		if profile.Location != location {
+			t.Errorf("%s %s: expected %q, got %q", name, version, location, profile.Location)
+		}
+	} else {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func assertTestConflicts(t *testing.T, registry *Registry, count int) []Profiles {
+	t.Helper()
+
+	conflicts, err := registry.Conflicts(contextpkg.Background())
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if len(conflicts) != count {
+		t.Errorf("expected %d conflicts, got %d", count, len(conflicts))
+	}
+	return conflicts
+}
+
+func writeTestProfile(t *testing.T, dir string, path string, content string) {
+	path = filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	contextpkg "context"
+	"strings"
+
+	"github.com/tliron/exturl"
+)
+
+//
+// Fetcher
+//
+
+type Fetcher interface {
+	// Returns the root URL of the repository. Paths within the repository
+	// are resolved relative to it.
+	Fetch(context contextpkg.Context, urlContext *exturl.Context, url string) (exturl.URL, error)
+}
+
+//
+// Releaser
+//
+
+// Fetchers that keep local state (e.g. temporary directories) should
+// implement this interface.
+type Releaser interface {
+	Release() error
+}
+
+// Selects a fetcher according to the URL's format.
+func NewFetcher(url string) Fetcher {
+	format := exturl.GetFormat(stripQuery(url))
+
+	if exturl.IsValidTarballArchiveFormat(format) {
+		return NewArchiveFetcher(format)
+	}
+
+	switch format {
+	case "zip", "csar":
+		return NewArchiveFetcher("zip")
+	case "bundle":
+		return NewGitBundleFetcher("")
+	default:
+		return NewURLFetcher()
+	}
+}
+
+//
+// URLFetcher
+//
+
+// For local directories and any URL type supported by exturl,
+// e.g. "file:", "http:", "git:".
+type URLFetcher struct{}
+
+func NewURLFetcher() *URLFetcher {
+	return new(URLFetcher)
+}
+
+// ([Fetcher] interface)
+func (self *URLFetcher) Fetch(context contextpkg.Context, urlContext *exturl.Context, url string) (exturl.URL, error) {
+	return urlContext.NewAnyOrFileURL(url), nil
+}
+
+//
+// ArchiveFetcher
+//
+
+// For tarballs and zip files (including CSARs).
+type ArchiveFetcher struct {
+	Format string
+}
+
+func NewArchiveFetcher(format string) *ArchiveFetcher {
+	return &ArchiveFetcher{Format: format}
+}
+
+// ([Fetcher] interface)
+func (self *ArchiveFetcher) Fetch(context contextpkg.Context, urlContext *exturl.Context, url string) (exturl.URL, error) {
+	archiveUrl, err := urlContext.NewValidAnyOrFileURL(context, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	format := self.Format
+	if format == "" {
+		format = archiveUrl.Format()
+	}
+
+	if format == "zip" {
+		return exturl.NewZipURL("", archiveUrl), nil
+	} else {
+		return exturl.NewTarballURL("", archiveUrl, format), nil
+	}
+}
+
+// Utils
+
+func stripQuery(url string) string {
+	if index := strings.IndexAny(url, "?#"); index != -1 {
+		return url[:index]
+	}
+	return url
+}
@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"bufio"
+	contextpkg "context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/tliron/exturl"
+)
+
+//
+// GitBundleFetcher
+//
+
+// For git bundles, as created by "git bundle create". The bundle must be
+// self-contained (no prerequisites). The tree of the selected reference is
+// checked out into a temporary directory that is deleted on Release.
+type GitBundleFetcher struct {
+	// Leave empty to use HEAD, or the first reference if there is no HEAD
+	Reference string
+
+	dirs []string
+	lock sync.Mutex
+}
+
+func NewGitBundleFetcher(reference string) *GitBundleFetcher {
+	return &GitBundleFetcher{Reference: reference}
+}
+
+// ([Fetcher] interface)
+func (self *GitBundleFetcher) Fetch(context contextpkg.Context, urlContext *exturl.Context, url string) (exturl.URL, error) {
+	bundleUrl, err := urlContext.NewValidAnyOrFileURL(context, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := bundleUrl.Open(context)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	bufferedReader := bufio.NewReader(reader)
+
+	references, err := readGitBundleHeader(bufferedReader)
+	if err != nil {
+		return nil, fmt.Errorf("malformed git bundle: %s: %s", url, err.Error())
+	}
+
+	hash, err := self.selectReference(references)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", err.Error(), url)
+	}
+
+	storage := memory.NewStorage()
+	if err := packfile.UpdateObjectStorage(storage, bufferedReader); err != nil {
+		return nil, fmt.Errorf("malformed git bundle: %s: %s", url, err.Error())
+	}
+
+	tree, err := getGitTree(storage, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", exturl.GetTemporaryPathPattern(bundleUrl.Key()))
+	if err != nil {
+		return nil, err
+	}
+
+	self.lock.Lock()
+	self.dirs = append(self.dirs, dir)
+	self.lock.Unlock()
+
+	if err := checkoutGitTree(tree, dir); err != nil {
+		return nil, err
+	}
+
+	return urlContext.NewFileURL(dir), nil
+}
+
+// ([Releaser] interface)
+func (self *GitBundleFetcher) Release() error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	var err error
+	for _, dir := range self.dirs {
+		if err_ := exturl.DeleteTemporaryDir(dir); err_ != nil {
+			err = err_
+		}
+	}
+	self.dirs = nil
+
+	return err
+}
+
+func (self *GitBundleFetcher) selectReference(references []gitBundleReference) (plumbing.Hash, error) {
+	if len(references) == 0 {
+		return plumbing.ZeroHash, fmt.Errorf("git bundle has no references")
+	}
+
+	if self.Reference != "" {
+		for _, reference := range references {
+			if (reference.name == self.Reference) || (plumbing.ReferenceName(reference.name).Short() == self.Reference) {
+				return reference.hash, nil
+			}
+		}
+		return plumbing.ZeroHash, fmt.Errorf("git bundle does not have reference %q", self.Reference)
+	}
+
+	for _, reference := range references {
+		if reference.name == "HEAD" {
+			return reference.hash, nil
+		}
+	}
+
+	return references[0].hash, nil
+}
+
+// Utils
+
+type gitBundleReference struct {
+	name string
+	hash plumbing.Hash
+}
+
+// See: https://git-scm.com/docs/gitformat-bundle
+func readGitBundleHeader(reader *bufio.Reader) ([]gitBundleReference, error) {
+	signature, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	switch signature {
+	case "# v2 git bundle\n", "# v3 git bundle\n":
+	default:
+		return nil, fmt.Errorf("unsupported signature: %q", strings.TrimSpace(signature))
+	}
+
+	var references []gitBundleReference
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			// End of header; the packfile follows
+			return references, nil
+		}
+
+		switch line[0] {
+		case '@':
+			// Capability (v3)
+			if (line != "@object-format=sha1") && !strings.HasPrefix(line, "@filter=") {
+				return nil, fmt.Errorf("unsupported capability: %q", line[1:])
+			}
+
+		case '-':
+			return nil, fmt.Errorf("prerequisites are not supported")
+
+		default:
+			if hash, name, ok := strings.Cut(line, " "); ok && plumbing.IsHash(hash) {
+				references = append(references, gitBundleReference{name, plumbing.NewHash(hash)})
+			} else {
+				return nil, fmt.Errorf("malformed reference: %q", line)
+			}
+		}
+	}
+}
+
+func getGitTree(storage *memory.Storage, hash plumbing.Hash) (*object.Tree, error) {
+	if tag, err := object.GetTag(storage, hash); err == nil {
+		if commit, err := tag.Commit(); err == nil {
+			return commit.Tree()
+		} else {
+			return nil, err
+		}
+	}
+
+	if commit, err := object.GetCommit(storage, hash); err == nil {
+		return commit.Tree()
+	} else {
+		return nil, err
+	}
+}
+
+func checkoutGitTree(tree *object.Tree, dir string) error {
+	return tree.Files().ForEach(func(file *object.File) error {
+		if !file.Mode.IsRegular() && (file.Mode != filemode.Executable) {
+			// Skip symlinks and submodules
+			return nil
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(file.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+
+		reader, err := file.Reader()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+
+		writer, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer writer.Close()
+
+		_, err = io.Copy(writer, reader)
+		return err
+	})
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	contextpkg "context"
+	neturlpkg "net/url"
+	"sync"
+
+	"github.com/tliron/exturl"
+)
+
+//
+// Repositories
+//
+
+// Resolves TOSCA repositories to root URLs via fetchers keyed by repository
+// name. Repositories without a registered fetcher are fetched according to
+// the format of their URL (see [NewFetcher]).
+//
+// Credentials are supplied at runtime per repository name and are applied to
+// the host of the repository's URL. They are used only for that repository:
+// it is fetched in its own URL context, to which URLs relative to its root
+// also belong. Credentials declared in the template are not used for
+// fetching.
+type Repositories struct {
+	fetchers    map[string]Fetcher
+	urls        map[string]string
+	credentials map[string]*exturl.Credentials
+	fetches     map[string]*repositoryFetch
+	used        []Fetcher
+	urlContexts []*exturl.Context
+	lock        sync.Mutex
+}
+
+func NewRepositories() *Repositories {
+	return &Repositories{
+		fetchers:    make(map[string]Fetcher),
+		urls:        make(map[string]string),
+		credentials: make(map[string]*exturl.Credentials),
+		fetches:     make(map[string]*repositoryFetch),
+	}
+}
+
+func (self *Repositories) SetFetcher(name string, fetcher Fetcher) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.fetchers[name] = fetcher
+}
+
+// Overrides the URL declared in the template.
+func (self *Repositories) SetURL(name string, url string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.urls[name] = url
+}
+
+func (self *Repositories) SetCredentials(name string, username string, password string, token string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.credentials[name] = &exturl.Credentials{
+		Username: username,
+		Password: password,
+		Token:    token,
+	}
+}
+
+// The URL argument is the one declared in the template. Results are cached
+// per repository name and URL, and concurrent calls for the same repository
+// share a single fetch. Failed fetches are not cached.
+func (self *Repositories) GetURL(context contextpkg.Context, urlContext *exturl.Context, name string, url string) (exturl.URL, error) {
+	self.lock.Lock()
+
+	if url_, ok := self.urls[name]; ok {
+		url = url_
+	}
+
+	key := name + "|" + url
+	if fetch, ok := self.fetches[key]; ok {
+		self.lock.Unlock()
+		return fetch.wait(context)
+	}
+
+	fetch := &repositoryFetch{done: make(chan struct{})}
+	self.fetches[key] = fetch
+
+	fetcher, ok := self.fetchers[name]
+	if !ok {
+		fetcher = NewFetcher(url)
+	}
+
+	var ownURLContext *exturl.Context
+	if credentials, ok := self.credentials[name]; ok {
+		if neturl, err := neturlpkg.Parse(url); (err == nil) && (neturl.Host != "") {
+			ownURLContext = exturl.NewContext()
+			ownURLContext.SetCredentials(neturl.Host, credentials.Username, credentials.Password, credentials.Token)
+			urlContext = ownURLContext
+		}
+	}
+
+	self.lock.Unlock()
+
+	// Fetching can take a while, so we do it without holding the lock
+	fetch.url, fetch.err = fetcher.Fetch(context, urlContext, url)
+
+	self.lock.Lock()
+	if fetch.err == nil {
+		self.used = append(self.used, fetcher)
+		if ownURLContext != nil {
+			self.urlContexts = append(self.urlContexts, ownURLContext)
+		}
+	} else {
+		delete(self.fetches, key)
+		if ownURLContext != nil {
+			ownURLContext.Release()
+		}
+	}
+	self.lock.Unlock()
+
+	close(fetch.done)
+
+	return fetch.url, fetch.err
+}
+
+func (self *Repositories) Release() error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	var err error
+	for _, fetcher := range self.used {
+		if releaser, ok := fetcher.(Releaser); ok {
+			if err_ := releaser.Release(); err_ != nil {
+				err = err_
+			}
+		}
+	}
+
+	for _, urlContext := range self.urlContexts {
+		if err_ := urlContext.Release(); err_ != nil {
+			err = err_
+		}
+	}
+
+	self.used = nil
+	self.urlContexts = nil
+	self.fetches = make(map[string]*repositoryFetch)
+
+	return err
+}
+
+//
+// repositoryFetch
+//
+
+type repositoryFetch struct {
+	done chan struct{}
+	url  exturl.URL
+	err  error
+}
+
+func (self *repositoryFetch) wait(context contextpkg.Context) (exturl.URL, error) {
+	select {
+	case <-self.done:
+		return self.url, self.err
+	case <-context.Done():
+		return nil, context.Err()
+	}
+}
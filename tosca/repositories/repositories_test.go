@@ -0,0 +1,349 @@
+package repositories
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	contextpkg "context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/tliron/exturl"
+)
+
+// Repository content used by all tests
+var testFiles = map[string]string{
+	"types.yaml":        "tosca_definitions_version: tosca_2_0\n",
+	"nested/types.yaml": "tosca_definitions_version: tosca_simple_yaml_1_3\n",
+}
+
+func TestArchiveFetcherZip(t *testing.T) {
+	testFetch(t, writeTestZip(t, filepath.Join(t.TempDir(), "repository.zip")), "", nil)
+}
+
+func TestArchiveFetcherCSAR(t *testing.T) {
+	testFetch(t, writeTestZip(t, filepath.Join(t.TempDir(), "repository.csar")), "", nil)
+}
+
+func TestArchiveFetcherTarball(t *testing.T) {
+	testFetch(t, writeTestTarball(t, filepath.Join(t.TempDir(), "repository.tar.gz")), "", nil)
+}
+
+func TestGitBundleFetcher(t *testing.T) {
+	path := writeTestGitBundle(t, filepath.Join(t.TempDir(), "repository.bundle"), "HEAD", "refs/heads/main")
+
+	// Checked out into a temporary directory that is deleted on release
+	var dir string
+	testFetch(t, path, "", func(root exturl.URL) {
+		if fileUrl, ok := root.(*exturl.FileURL); ok {
+			dir = fileUrl.Path
+		} else {
+			t.Fatalf("not a file URL: %s", root.String())
+		}
+	})
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("temporary directory not deleted: %s", dir)
+	}
+}
+
+func TestGitBundleFetcherReference(t *testing.T) {
+	path := writeTestGitBundle(t, filepath.Join(t.TempDir(), "repository.bundle"), "refs/tags/v1")
+
+	repositories := NewRepositories()
+	repositories.SetFetcher("test", NewGitBundleFetcher("v1"))
+	testFetch(t, path, "", nil, repositories)
+
+	repositories = NewRepositories()
+	repositories.SetFetcher("test", NewGitBundleFetcher("v2"))
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+	if _, err := repositories.GetURL(contextpkg.Background(), urlContext, "test", path); err == nil {
+		t.Errorf("fetched missing reference")
+	}
+}
+
+func TestRepositoriesSetURL(t *testing.T) {
+	path := writeTestZip(t, filepath.Join(t.TempDir(), "repository.zip"))
+
+	repositories := NewRepositories()
+	repositories.SetURL("test", path)
+	testFetch(t, path, "http://localhost:1/missing.zip", nil, repositories)
+}
+
+func TestRepositoriesCredentials(t *testing.T) {
+	repositories := NewRepositories()
+	repositories.SetFetcher("test", NewURLFetcher())
+	repositories.SetCredentials("test", "user", "password", "")
+
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	root, err := repositories.GetURL(contextpkg.Background(), urlContext, "test", "http://localhost:1/repository/")
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	// Scoped to the repository
+	if credentials := root.Context().GetCredentials("localhost:1"); (credentials == nil) || (credentials.Username != "user") {
+		t.Errorf("credentials not applied to repository")
+	}
+	if urlContext.GetCredentials("localhost:1") != nil {
+		t.Errorf("credentials leaked to shared URL context")
+	}
+
+	if err := repositories.Release(); err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestRepositoriesConcurrentFetch(t *testing.T) {
+	release := make(chan struct{})
+	fetcher := &testFetcher{release: release}
+
+	repositories := NewRepositories()
+	repositories.SetFetcher("slow", fetcher)
+
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	context, cancel := contextpkg.WithTimeout(contextpkg.Background(), 10*time.Second)
+	defer cancel()
+
+	var wait sync.WaitGroup
+	roots := make([]exturl.URL, 4)
+	for index := range roots {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			if root, err := repositories.GetURL(context, urlContext, "slow", "/slow"); err == nil {
+				roots[index] = root
+			} else {
+				t.Errorf("%s", err.Error())
+			}
+		}()
+	}
+
+	// Other repositories are not blocked by the slow fetch
+	if _, err := repositories.GetURL(context, urlContext, "fast", t.TempDir()); err != nil {
+		t.Errorf("%s", err.Error())
+	}
+
+	close(release)
+	wait.Wait()
+
+	if count := fetcher.count.Load(); count != 1 {
+		t.Errorf("expected 1 fetch, got %d", count)
+	}
+	for _, root := range roots {
+		if root != roots[0] {
+			t.Errorf("fetch not shared")
+		}
+	}
+}
+
+// Utils
+
+type testFetcher struct {
+	release chan struct{}
+	count   atomic.Int32
+}
+
+// ([Fetcher] interface)
+func (self *testFetcher) Fetch(context contextpkg.Context, urlContext *exturl.Context, url string) (exturl.URL, error) {
+	self.count.Add(1)
+	<-self.release
+	return urlContext.NewFileURL(url), nil
+}
+
+func testFetch(t *testing.T, url string, declaredUrl string, inspect func(exturl.URL), repositories ...*Repositories) {
+	var repositories_ *Repositories
+	if len(repositories) > 0 {
+		repositories_ = repositories[0]
+	} else {
+		repositories_ = NewRepositories()
+	}
+	if declaredUrl == "" {
+		declaredUrl = url
+	}
+
+	context, cancel := contextpkg.WithTimeout(contextpkg.Background(), 10*time.Second)
+	defer cancel()
+
+	urlContext := exturl.NewContext()
+	defer urlContext.Release()
+
+	root, err := repositories_.GetURL(context, urlContext, "test", declaredUrl)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	// Cached
+	if root_, err := repositories_.GetURL(context, urlContext, "test", declaredUrl); (err != nil) || (root_ != root) {
+		t.Errorf("not cached")
+	}
+
+	for path, content := range testFiles {
+		url, err := urlContext.NewValidAnyOrFileURL(context, path, []exturl.URL{root})
+		if err != nil {
+			t.Fatalf("%s: %s", path, err.Error())
+		}
+		if content_, err := exturl.ReadString(context, url); err != nil {
+			t.Errorf("%s: %s", path, err.Error())
+		} else if content_ != content {
+			t.Errorf("%s: expected %q, got %q", path, content, content_)
+		}
+	}
+
+	if inspect != nil {
+		inspect(root)
+	}
+
+	if err := repositories_.Release(); err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func writeTestZip(t *testing.T, path string) string {
+	file := createTestFile(t, path)
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for name, content := range testFiles {
+		if entry, err := writer.Create(name); err == nil {
+			if _, err := entry.Write([]byte(content)); err != nil {
+				t.Fatalf("%s", err.Error())
+			}
+		} else {
+			t.Fatalf("%s", err.Error())
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	return path
+}
+
+func writeTestTarball(t *testing.T, path string) string {
+	file := createTestFile(t, path)
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	writer := tar.NewWriter(gzipWriter)
+	for name, content := range testFiles {
+		if err := writer.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	return path
+}
+
+// All references point to a single commit
+func writeTestGitBundle(t *testing.T, path string, references ...string) string {
+	storage := memory.NewStorage()
+
+	// Tree entries must be sorted by name ("nested" comes before "types.yaml")
+	var rootEntries []object.TreeEntry
+	var nestedEntries []object.TreeEntry
+	for name, content := range testFiles {
+		dir, base := filepath.Split(name)
+		entry := object.TreeEntry{Name: base, Mode: filemode.Regular, Hash: storeTestGitObject(t, storage, plumbing.BlobObject, content)}
+		if dir == "" {
+			rootEntries = append(rootEntries, entry)
+		} else {
+			nestedEntries = append(nestedEntries, entry)
+		}
+	}
+	nestedHash := storeTestGitTree(t, storage, nestedEntries)
+	rootEntries = append([]object.TreeEntry{{Name: "nested", Mode: filemode.Dir, Hash: nestedHash}}, rootEntries...)
+	treeHash := storeTestGitTree(t, storage, rootEntries)
+
+	signature := object.Signature{Name: "Test", Email: "test@localhost", When: time.Unix(0, 0)}
+	commit := object.Commit{Author: signature, Committer: signature, Message: "test\n", TreeHash: treeHash}
+	encodedCommit := storage.NewEncodedObject()
+	if err := commit.Encode(encodedCommit); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	commitHash, err := storage.SetEncodedObject(encodedCommit)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	file := createTestFile(t, path)
+	defer file.Close()
+
+	fmt.Fprint(file, "# v2 git bundle\n")
+	for _, reference := range references {
+		fmt.Fprintf(file, "%s %s\n", commitHash.String(), reference)
+	}
+	fmt.Fprint(file, "\n")
+
+	var hashes []plumbing.Hash
+	for hash := range storage.Objects {
+		hashes = append(hashes, hash)
+	}
+	if _, err := packfile.NewEncoder(file, storage, false).Encode(hashes, 0); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	return path
+}
+
+func storeTestGitTree(t *testing.T, storage *memory.Storage, entries []object.TreeEntry) plumbing.Hash {
+	tree := object.Tree{Entries: entries}
+	encodedTree := storage.NewEncodedObject()
+	if err := tree.Encode(encodedTree); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	hash, err := storage.SetEncodedObject(encodedTree)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	return hash
+}
+
+func storeTestGitObject(t *testing.T, storage *memory.Storage, type_ plumbing.ObjectType, content string) plumbing.Hash {
+	encodedObject := storage.NewEncodedObject()
+	encodedObject.SetType(type_)
+	writer, err := encodedObject.Writer()
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if _, err := writer.Write([]byte(content)); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	writer.Close()
+	hash, err := storage.SetEncodedObject(encodedObject)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	return hash
+}
+
+func createTestFile(t *testing.T, path string) *os.File {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	return file
+}
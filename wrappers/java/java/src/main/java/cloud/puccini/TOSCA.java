@@ -5,19 +5,42 @@ import org.snakeyaml.engine.v2.api.DumpSettings;
 import org.snakeyaml.engine.v2.api.Load;
 import org.snakeyaml.engine.v2.api.LoadSettings;
 
+import java.util.HashMap;
 import java.util.List;
 import java.util.Map;
 
 public class TOSCA
 {
 	public static Object Compile( String url, Map<String, Object> inputs, List<String> quirks, boolean resolve, boolean coerce ) throws Exception
+	{
+		return Compile( url, inputs, quirks, resolve, coerce, false );
+	}
+
+	public static Object Compile( String url, Map<String, Object> inputs, List<String> quirks, boolean resolve, boolean coerce, boolean revealHidden ) throws Exception
+	{
+		return Compile( url, inputs, quirks, resolve, coerce, revealHidden, null );
+	}
+
+	public static Object Compile( String url, Map<String, Object> inputs, List<String> quirks, boolean resolve, boolean coerce, boolean revealHidden, List<String> environments ) throws Exception
+	{
+		return Compile( url, inputs, quirks, resolve, coerce, revealHidden, environments, false, 0 );
+	}
+
+	public static Object Compile( String url, Map<String, Object> inputs, List<String> quirks, boolean resolve, boolean coerce, boolean revealHidden, List<String> environments, boolean verifyArtifacts, long embedArtifacts ) throws Exception
 	{
 		Load load = new SnakeYAML.Load( LoadSettings.builder().build() );
 		Dump dump = new SnakeYAML.Dump( DumpSettings.builder().build() );
 
 		String inputs_ = inputs == null ? "" : dump.dumpToString( inputs );
 		String quirks_ = quirks == null ? "" : dump.dumpToString( quirks );
-		Map<Object, Object> result = (Map<Object, Object>) load.loadFromString( _Compile( url, inputs_, quirks_, resolve, coerce ) );
+		Map<String, Object> options = new HashMap<String, Object>();
+		options.put( "revealHidden", revealHidden );
+		if ( environments != null )
+			options.put( "environments", environments );
+		options.put( "verifyArtifacts", verifyArtifacts );
+		options.put( "embedArtifacts", embedArtifacts );
+		String options_ = dump.dumpToString( options );
+		Map<Object, Object> result = (Map<Object, Object>) load.loadFromString( _CompileWithOptions( url, inputs_, quirks_, resolve, coerce, options_ ) );
 
 		if ( result.containsKey( "problems" ) )
 		{
@@ -44,5 +67,5 @@ public class TOSCA
 		System.loadLibrary( "puccinijni" );
 	}
 
-	public static native String _Compile( String url, String inputs, String quirks, boolean resolve, boolean coerce );
+	public static native String _CompileWithOptions( String url, String inputs, String quirks, boolean resolve, boolean coerce, String options );
 }
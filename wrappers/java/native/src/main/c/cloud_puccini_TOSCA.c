@@ -2,18 +2,20 @@
 #include "libpuccini.h"
 #include <stdlib.h>
 
-JNIEXPORT jstring JNICALL Java_cloud_puccini_TOSCA__1Compile
-  (JNIEnv *env, jclass cls, jstring url, jstring inputs, jstring quirks, jboolean resolve, jboolean coerce)
+JNIEXPORT jstring JNICALL Java_cloud_puccini_TOSCA__1CompileWithOptions
+  (JNIEnv *env, jclass cls, jstring url, jstring inputs, jstring quirks, jboolean resolve, jboolean coerce, jstring options)
 {
 	const char *url_ = (*env)->GetStringUTFChars(env, url, 0);
 	const char *inputs_ = (*env)->GetStringUTFChars(env, inputs, 0);
 	const char *quirks_ = (*env)->GetStringUTFChars(env, quirks, 0);
+	const char *options_ = (*env)->GetStringUTFChars(env, options, 0);
 
-	char *result = Compile((char *) url_, (char *) inputs_, (char *) quirks_, resolve, coerce);
+	char *result = CompileWithOptions((char *) url_, (char *) inputs_, (char *) quirks_, resolve, coerce, (char *) options_);
 
 	(*env)->ReleaseStringUTFChars(env, url, url_);
 	(*env)->ReleaseStringUTFChars(env, inputs, inputs_);
 	(*env)->ReleaseStringUTFChars(env, quirks, quirks_);
+	(*env)->ReleaseStringUTFChars(env, options, options_);
 
 	jstring result_ = (*env)->NewStringUTF(env, result);
 	free(result);
@@ -5,8 +5,8 @@ from . import go
 library_path = pathlib.Path(__file__).parents[0] / 'libpuccini.so'
 library = ctypes.cdll.LoadLibrary(library_path)
 
-library.Compile.argtypes = (ctypes.c_char_p, ctypes.c_char_p, ctypes.c_char_p, ctypes.c_char, ctypes.c_char)
-library.Compile.restype = ctypes.c_char_p
+library.CompileWithOptions.argtypes = (ctypes.c_char_p, ctypes.c_char_p, ctypes.c_char_p, ctypes.c_char, ctypes.c_char, ctypes.c_char_p)
+library.CompileWithOptions.restype = ctypes.c_char_p
 
 
 class Problems(Exception):
@@ -15,10 +15,11 @@ class Problems(Exception):
     self.problems = problems
 
 
-def compile(url, inputs=None, quirks=None, resolve=True, coerce=True):
+def compile(url, inputs=None, quirks=None, resolve=True, coerce=True, reveal_hidden=False, environments=None, verify_artifacts=False, embed_artifacts=0):
   inputs = ard.encode(inputs or {})
   quirks = ard.encode(quirks or [])
-  result = ard.read(library.Compile(go.to_c_char_p(url), go.to_c_char_p(inputs), go.to_c_char_p(quirks), go.to_c_char(resolve), go.to_c_char(coerce)))
+  options = ard.encode({'revealHidden': reveal_hidden, 'environments': environments or [], 'verifyArtifacts': verify_artifacts, 'embedArtifacts': embed_artifacts})
+  result = ard.read(library.CompileWithOptions(go.to_c_char_p(url), go.to_c_char_p(inputs), go.to_c_char_p(quirks), go.to_c_char(resolve), go.to_c_char(coerce), go.to_c_char_p(options)))
   if 'problems' in result:
     raise Problems(result['problems'])
   elif 'error' in result:
@@ -5,7 +5,7 @@ require 'yaml'
 module Puccini
   extend Fiddle::Importer
   dlload File.join(__dir__, 'libpuccini.so')
-  extern 'char *Compile(char *, char *, char *, char, char)'
+  extern 'char *CompileWithOptions(char *, char *, char *, char, char, char *)'
 
   module TOSCA
     extend self
@@ -17,10 +17,11 @@ module Puccini
       attr_reader :problems
     end
 
-    def compile(url, inputs=nil, quirks=nil, resolve=true, coerce=true)
+    def compile(url, inputs=nil, quirks=nil, resolve=true, coerce=true, reveal_hidden=false, environments=nil, verify_artifacts=false, embed_artifacts=0)
       inputs = YAML.dump (inputs || {})
       quirks = YAML.dump (quirks || [])
-      result = YAML.unsafe_load Puccini::Compile(url, inputs, quirks, resolve ? 1 : 0, coerce ? 1 : 0).to_s
+      options = YAML.dump ({ 'revealHidden' => reveal_hidden, 'environments' => (environments || []), 'verifyArtifacts' => verify_artifacts, 'embedArtifacts' => embed_artifacts })
+      result = YAML.unsafe_load Puccini::CompileWithOptions(url, inputs, quirks, resolve ? 1 : 0, coerce ? 1 : 0, options).to_s
       if result.key? 'problems'
         raise Problems.new result['problems']
       elsif result.key? 'error'